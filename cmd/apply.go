@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/helmcode/kubectl-ai/pkg/k8s"
+	"github.com/helmcode/kubectl-ai/pkg/model"
+	"github.com/helmcode/kubectl-ai/pkg/parser"
+	"github.com/spf13/cobra"
+)
+
+// applyFixes runs the analysis' remediation commands with kubectl, against the cluster that was
+// analyzed, asking before each one. Destructive commands are skipped unless allowDestructive is
+// set. It stops at the first command that fails, since later steps usually depend on it.
+func applyFixes(cmd *cobra.Command, k8sClient *k8s.Client, analysis *model.Analysis, allowDestructive bool) error {
+	commands := remediationCommands(analysis)
+	fmt.Fprintln(statusOutput)
+	if len(commands) == 0 {
+		fmt.Fprintln(statusOutput, "⚠️  The analysis has no kubectl command to apply")
+		return nil
+	}
+
+	color.New(color.FgGreen, color.Bold).Fprintln(statusOutput, "🛠️  APPLYING FIXES")
+	// Read stdin in the background so Ctrl-C is noticed while waiting for an answer
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	for i, command := range commands {
+		fmt.Fprintf(statusOutput, "\n%d. %s\n", i+1, command.Description)
+		fmt.Fprintf(statusOutput, "   $ %s\n", color.CyanString(command.Command))
+
+		destructive := command.Destructive
+		args, err := parser.SplitCommand(command.Command)
+		if err == nil {
+			// Classify the arguments kubectl will actually run again rather than trusting the flag alone
+			destructive = destructive || parser.Destructive(args[1:])
+			args, err = kubectlArgs(k8sClient, args[1:])
+		}
+		if err != nil {
+			printError(fmt.Sprintf("Skipped: %v", err))
+			continue
+		}
+		if destructive && !allowDestructive {
+			fmt.Fprintln(statusOutput, "   ⏭️  Skipped: destructive commands only run with --apply-destructive")
+			continue
+		}
+		if destructive {
+			fmt.Fprintln(statusOutput, color.RedString("   ⚠️  This command is destructive"))
+		}
+		answer, ok := ask(cmd.Context(), lines, "   Run it?")
+		if !ok {
+			return nil
+		}
+		if answer != "y" && answer != "yes" {
+			fmt.Fprintln(statusOutput, "   ⏭️  Skipped")
+			continue
+		}
+
+		// Each command gets its own --timeout so time spent deciding doesn't count
+		ctx, cancel := commandContext(cmd)
+		kubectl := exec.CommandContext(ctx, "kubectl", args...)
+		kubectl.Stdout = statusOutput
+		kubectl.Stderr = statusOutput
+		err = kubectl.Run()
+		cancel()
+		if err != nil {
+			return fmt.Errorf("%s failed: %w", command.Command, err)
+		}
+		printSuccess("Done")
+	}
+	return nil
+}
+
+// remediationCommands returns the analysis' commands, or its quick fix when the model listed none
+func remediationCommands(analysis *model.Analysis) []model.RemediationCommand {
+	if len(analysis.Commands) > 0 || analysis.QuickFix == "" {
+		return analysis.Commands
+	}
+	return parser.ValidCommands([]model.RemediationCommand{{Description: "Quick fix", Command: analysis.QuickFix}})
+}
+
+// ask asks a yes/no question and returns the lowercased answer. ok is false when the user
+// interrupts (Ctrl-C) or closes stdin (Ctrl-D), which ends the whole session.
+func ask(ctx context.Context, lines <-chan string, question string) (answer string, ok bool) {
+	fmt.Fprintf(statusOutput, "%s [y/N] ", question)
+	select {
+	case <-ctx.Done():
+	case answer, ok = <-lines:
+	}
+	if !ok {
+		fmt.Fprintln(statusOutput)
+		return "", false
+	}
+	return strings.ToLower(strings.TrimSpace(answer)), true
+}
+
+// connectionFlags are the kubectl flags that choose the cluster or the identity to act as. The
+// model's output is shaped by cluster data such as logs and annotations, so a command must not
+// pick its own.
+var connectionFlags = []string{
+	"--kubeconfig", "--context", "--cluster", "--user", "-s", "--server", "--token", "--username", "--password",
+	"--as", "--as-group", "--as-uid", "--certificate-authority", "--client-certificate", "--client-key",
+	"--insecure-skip-tls-verify", "--tls-server-name",
+}
+
+// kubectlArgs points a command at the kubeconfig and context the analysis used, and at its
+// namespace unless the command chooses one. Commands that set their own cluster or identity, or
+// read manifests from a URL or a kustomization, are rejected.
+func kubectlArgs(k8sClient *k8s.Client, args []string) ([]string, error) {
+	// Short flags also take their value attached, as in -nfoo
+	has := func(flags ...string) string {
+		for _, arg := range args {
+			for _, flag := range flags {
+				if arg == flag || strings.HasPrefix(arg, flag+"=") || (len(flag) == 2 && strings.HasPrefix(arg, flag)) {
+					return flag
+				}
+			}
+		}
+		return ""
+	}
+	if flag := has(connectionFlags...); flag != "" {
+		return nil, fmt.Errorf("commands can't set %s, they always run against the analyzed cluster", flag)
+	}
+	if source := parser.RemoteManifest(args); source != "" {
+		return nil, fmt.Errorf("commands can't read manifests from %s, only ones that can be reviewed", source)
+	}
+
+	if contextName := k8sClient.GetContextName(); contextName != "in-cluster" {
+		if kubeconfig != "" {
+			args = append(args, "--kubeconfig", kubeconfig)
+		}
+		if contextName != "" {
+			args = append(args, "--context", contextName)
+		}
+	}
+	if !allNamespaces && has("-n", "--namespace", "-A", "--all-namespaces") == "" {
+		args = append(args, "--namespace", namespace)
+	}
+	return args, nil
+}
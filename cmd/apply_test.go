@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/helmcode/kubectl-ai/pkg/k8s"
+)
+
+func TestKubectlArgs(t *testing.T) {
+	kubeconfig, namespace, allNamespaces = "", "prod", false
+	t.Cleanup(func() { kubeconfig, namespace, allNamespaces = "", "", false })
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr bool
+	}{
+		{"namespace added", []string{"scale", "deploy/api", "--replicas=3"}, []string{"scale", "deploy/api", "--replicas=3", "--namespace", "prod"}, false},
+		{"own -n kept", []string{"scale", "deploy/api", "-n", "staging"}, []string{"scale", "deploy/api", "-n", "staging"}, false},
+		{"own attached -n kept", []string{"scale", "deploy/api", "-nstaging"}, []string{"scale", "deploy/api", "-nstaging"}, false},
+		{"own --namespace= kept", []string{"scale", "deploy/api", "--namespace=staging"}, []string{"scale", "deploy/api", "--namespace=staging"}, false},
+		{"-A kept", []string{"label", "pods", "-A", "tier=web"}, []string{"label", "pods", "-A", "tier=web"}, false},
+		{"--all-namespaces kept", []string{"label", "pods", "--all-namespaces", "tier=web"}, []string{"label", "pods", "--all-namespaces", "tier=web"}, false},
+		{"local manifest", []string{"apply", "-f", "fix.yaml"}, []string{"apply", "-f", "fix.yaml", "--namespace", "prod"}, false},
+		{"--context", []string{"scale", "deploy/api", "--context", "other"}, nil, true},
+		{"--kubeconfig=", []string{"scale", "deploy/api", "--kubeconfig=/tmp/config"}, nil, true},
+		{"-s", []string{"scale", "deploy/api", "-s", "https://evil"}, nil, true},
+		{"attached -s", []string{"scale", "deploy/api", "-shttps://evil"}, nil, true},
+		{"--token=", []string{"scale", "deploy/api", "--token=abc"}, nil, true},
+		{"--as", []string{"scale", "deploy/api", "--as", "admin"}, nil, true},
+		{"-k", []string{"apply", "-k", "overlays/prod"}, nil, true},
+		{"-f=https", []string{"apply", "-f=https://example.com/fix.yaml"}, nil, true},
+		{"--filename=https", []string{"apply", "--filename=https://example.com/fix.yaml"}, nil, true},
+		{"--filename https", []string{"apply", "--filename", "https://example.com/fix.yaml"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := kubectlArgs(&k8s.Client{}, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error: %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKubectlArgsAllNamespaces(t *testing.T) {
+	kubeconfig, namespace, allNamespaces = "", "prod", true
+	t.Cleanup(func() { kubeconfig, namespace, allNamespaces = "", "", false })
+
+	got, err := kubectlArgs(&k8s.Client{}, []string{"label", "pods", "tier=web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"label", "pods", "tier=web"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/fatih/color"
+	"github.com/helmcode/kubectl-ai/pkg/formatter"
+	"github.com/helmcode/kubectl-ai/pkg/k8s"
+	"github.com/helmcode/kubectl-ai/pkg/metrics"
+	"github.com/spf13/cobra"
+)
+
+// comparedCharts are the line charts shown side by side in compare mode, in display order
+var comparedCharts = []struct {
+	metric string
+	title  string
+	unit   string
+}{
+	{"cpu_utilization", "CPU", "%"},
+	{"memory_utilization", "Memory", "MB"},
+	{"network_receive", "Network Receive", "MB/s"},
+	{"network_transmit", "Network Transmit", "MB/s"},
+}
+
+// compareMetrics collects the resource's metrics from the current context and --compare-context,
+// renders their charts side by side and, with --analyze, asks the AI to explain the divergence
+func compareMetrics(ctx context.Context, cmd *cobra.Command, s *spinner.Spinner, k8sClient *k8s.Client, prometheusClient *metrics.PrometheusClient, metricsAnalyzer *metrics.Analyzer, prometheusAuth metrics.AuthConfig, customQueries []metrics.PrometheusQuery) error {
+	primaryResults, err := collectAndAnalyzeMetrics(ctx, s, k8sClient, prometheusClient, metricsAnalyzer, false)
+	if err != nil {
+		return err
+	}
+
+	// Connect to the second cluster the same way as the first
+	s.Suffix = fmt.Sprintf(" Connecting to context %s...", metricsCompareContext)
+	s.Start()
+	compareK8sClient, err := k8s.NewClient(metricsKubeconfig, metricsCompareContext)
+	if err != nil {
+		s.Stop()
+		return fmt.Errorf("failed to connect to context %s: %w", metricsCompareContext, err)
+	}
+	s.Stop()
+	printSuccess(fmt.Sprintf("Connected to Kubernetes cluster (context %s)", metricsCompareContext))
+
+	compareK8sClient.SetConcurrency(metricsConcurrency)
+	compareK8sClient.SetRefreshDiscoveryCache(refreshCache(cmd))
+	if err := configureRedaction(compareK8sClient, metricsNoRedact, metricsRedactPattern); err != nil {
+		return err
+	}
+
+	comparePrometheusClient, err := metrics.NewPrometheusClient(ctx, metricsComparePrometheusURL, prometheusNamespace, prometheusServiceNames, compareK8sClient, prometheusAuth)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Prometheus in context %s: %w", metricsCompareContext, err)
+	}
+	defer comparePrometheusClient.Close()
+
+	comparePrometheusClient.SetConcurrency(metricsConcurrency)
+	comparePrometheusClient.SetStep(queryStep)
+	comparePrometheusClient.SetQueryTimeout(prometheusQueryTimeout)
+	if !metricsStartTime.IsZero() {
+		comparePrometheusClient.SetTimeRange(metricsStartTime, metricsEndTime)
+	}
+	comparePrometheusClient.SetQueries(customQueries)
+
+	compareAnalyzer := metrics.NewAnalyzer(nil, comparePrometheusClient, compareK8sClient)
+	compareResults, err := collectAndAnalyzeMetrics(ctx, s, compareK8sClient, comparePrometheusClient, compareAnalyzer, false)
+	if err != nil {
+		return err
+	}
+
+	primaryLabel := contextLabel(k8sClient)
+	compareLabel := contextLabel(compareK8sClient)
+	if len(primaryResults) == 0 {
+		return fmt.Errorf("resource not found in context %s", primaryLabel)
+	}
+	if len(compareResults) == 0 {
+		return fmt.Errorf("resource not found in context %s", compareLabel)
+	}
+	// --compare-context is limited to a single resource
+	primary, compare := onlyResult(primaryResults), onlyResult(compareResults)
+
+	displayMetricsComparison(primaryLabel, primary, compareLabel, compare)
+
+	if !analyzeScaling && !hpaAnalysis && !kedaAnalysis {
+		return nil
+	}
+
+	s.Suffix = " Comparing clusters with AI..."
+	s.Start()
+	summary, err := withContext(ctx, func() (string, error) {
+		return metricsAnalyzer.CompareResults(primaryLabel, primary, compareLabel, compare)
+	})
+	s.Stop()
+	if err != nil {
+		return fmt.Errorf("metrics comparison failed: %w", err)
+	}
+
+	cyan := color.New(color.FgCyan, color.Bold)
+	cyan.Printf("🤖 AI COMPARISON: %s vs %s\n", primaryLabel, compareLabel)
+	fmt.Println(strings.Repeat("=", 40))
+	fmt.Print(formatter.FormatMarkdownText(summary))
+	fmt.Println()
+
+	return nil
+}
+
+// contextLabel names a client's kube context for display and prompts
+func contextLabel(k8sClient *k8s.Client) string {
+	if name := k8sClient.GetContextName(); name != "" {
+		return name
+	}
+	return "current-context"
+}
+
+// displayMetricsComparison renders each chart for both contexts side by side
+func displayMetricsComparison(primaryLabel string, primary *metrics.AnalysisResult, compareLabel string, compare *metrics.AnalysisResult) {
+	cyan := color.New(color.FgCyan, color.Bold)
+	fmt.Println()
+	cyan.Println("📊 METRICS COMPARISON")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Printf("📦 Resource: %s/%s (%s)\n", primary.Namespace, primary.ResourceName, primary.ResourceType)
+	fmt.Printf("📅 Duration: %s\n", windowLabel(primary.Duration, primary.Start, primary.End))
+	fmt.Printf("⚖️  Contexts: %s (left) vs %s (right)\n", primaryLabel, compareLabel)
+	fmt.Println()
+
+	for _, chart := range comparedCharts {
+		left, leftOK := comparisonChart(primary, chart.metric, chart.title, chart.unit)
+		right, rightOK := comparisonChart(compare, chart.metric, chart.title, chart.unit)
+		if !leftOK && !rightOK {
+			continue
+		}
+		fmt.Print(formatter.SideBySide(labelChart(primaryLabel, left), labelChart(compareLabel, right)))
+		fmt.Println()
+	}
+
+	fmt.Print(formatter.SideBySide(labelChart(primaryLabel, replicaChart(primary)), labelChart(compareLabel, replicaChart(compare))))
+	fmt.Println()
+
+	fmt.Print(formatter.SideBySide(labelChart(primaryLabel, scalingConfigSummary(primary)), labelChart(compareLabel, scalingConfigSummary(compare))))
+	fmt.Println()
+}
+
+// comparisonChart renders one metric's line chart, or a placeholder when there is no data
+func comparisonChart(analysis *metrics.AnalysisResult, metric, title, unit string) (string, bool) {
+	summary, exists := analysis.MetricsSummary[metric]
+	if !exists || len(summary.Values) == 0 {
+		return fmt.Sprintf("⚠️  No %s metrics data available\n", title), false
+	}
+	return formatter.CreateEnhancedLineChart(summary.Values, summary.Timestamps, title, unit, analysis.Duration), true
+}
+
+// replicaChart renders the replica history, or daemon pod coverage for DaemonSets
+func replicaChart(analysis *metrics.AnalysisResult) string {
+	if analysis.ResourceType == "Pod" {
+		return ""
+	}
+	if analysis.ResourceType == "DaemonSet" {
+		desired := analysis.MetricsSummary["pod_replicas"]
+		if len(desired.Values) == 0 {
+			return "⚠️  No daemon pod data available\n"
+		}
+		ready := analysis.MetricsSummary["pod_ready"]
+		available := analysis.MetricsSummary["pod_available"]
+		return formatter.CreateDaemonSetChart(desired.Values, ready.Values, available.Values, desired.Timestamps, "Daemon Pods")
+	}
+
+	if len(analysis.ScalingEvents) == 0 {
+		return "⚠️  No scaling events data available\n"
+	}
+	replicas := make([]int, len(analysis.ScalingEvents))
+	timestamps := make([]time.Time, len(analysis.ScalingEvents))
+	for i, event := range analysis.ScalingEvents {
+		replicas[i] = event.Replicas
+		timestamps[i] = event.Timestamp
+	}
+	return formatter.CreateReplicaBarChart(replicas, timestamps, "Replica Scaling Events")
+}
+
+// scalingConfigSummary describes the autoscaler currently configured for the resource
+func scalingConfigSummary(analysis *metrics.AnalysisResult) string {
+	config := analysis.CurrentConfig
+	if config == nil {
+		return "⚙️  Scaling: unknown\n"
+	}
+
+	var summary strings.Builder
+	summary.WriteString(fmt.Sprintf("⚙️  Scaling: %s\n", config.Type))
+	summary.WriteString(fmt.Sprintf("  Min/Max Replicas: %d/%d\n", config.MinReplicas, config.MaxReplicas))
+	summary.WriteString(fmt.Sprintf("  Current Size: %d\n", config.CurrentSize))
+	if config.TargetCPU > 0 {
+		summary.WriteString(fmt.Sprintf("  Target CPU: %d%%\n", config.TargetCPU))
+	}
+	if config.TargetMemory > 0 {
+		summary.WriteString(fmt.Sprintf("  Target Memory: %d%%\n", config.TargetMemory))
+	}
+	return summary.String()
+}
+
+// labelChart prefixes a chart with the context it came from
+func labelChart(label, chart string) string {
+	return color.New(color.FgMagenta, color.Bold).Sprintf("◆ %s", label) + "\n" + chart
+}
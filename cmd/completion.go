@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/helmcode/kubectl-ai/pkg/k8s"
+	"github.com/spf13/cobra"
+)
+
+// completionTimeout bounds the cluster lookups behind a single tab press
+const completionTimeout = 5 * time.Second
+
+// debugResourceTypes are offered for -r before a resource type has been typed
+var debugResourceTypes = []string{
+	"deployment", "statefulset", "daemonset", "pod", "service", "ingress", "configmap",
+	"secret", "job", "cronjob", "hpa", "pvc", "networkpolicy", "pdb",
+}
+
+// workloadResourceTypes are the resource types metrics can be collected for
+var workloadResourceTypes = []string{"deployment", "statefulset", "daemonset", "job", "cronjob"}
+
+func NewCompletionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion bash|zsh|fish",
+		Short: "Generate a shell completion script",
+		Long: `Generate a shell completion script for kubectl-ai.
+
+Examples:
+  # Load completions in the current bash session
+  source <(kubectl-ai completion bash)
+
+  # Install zsh completions
+  kubectl-ai completion zsh > "${fpath[1]}/_kubectl-ai"
+
+  # Install fish completions
+  kubectl-ai completion fish > ~/.config/fish/completions/kubectl-ai.fish`,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{"bash", "zsh", "fish"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(os.Stdout, true)
+			case "zsh":
+				return root.GenZshCompletion(os.Stdout)
+			case "fish":
+				return root.GenFishCompletion(os.Stdout, true)
+			default:
+				return fmt.Errorf("unsupported shell %q (supported: bash, zsh, fish)", args[0])
+			}
+		},
+	}
+}
+
+// registerCompletions wires live cluster completion for the namespace and resource flags
+func registerCompletions(cmd *cobra.Command, resourceTypes []string) {
+	cmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
+	if cmd.Flags().Lookup("resource") != nil {
+		cmd.RegisterFlagCompletionFunc("resource", resourceCompletion(resourceTypes))
+	}
+}
+
+// completionClient connects with the command's --kubeconfig and --context flags
+func completionClient(cmd *cobra.Command) (*k8s.Client, error) {
+	kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+	if strings.HasPrefix(kubeconfigPath, "~/") {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			kubeconfigPath = filepath.Join(homeDir, kubeconfigPath[2:])
+		}
+	}
+	kubeContext, _ := cmd.Flags().GetString("context")
+	return k8s.NewClient(kubeconfigPath, kubeContext)
+}
+
+// completionContext bounds completion lookups so a slow cluster doesn't hang the shell
+func completionContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithTimeout(ctx, completionTimeout)
+}
+
+func completeNamespaces(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	k8sClient, err := completionClient(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	ctx, cancel := completionContext(cmd)
+	defer cancel()
+
+	namespaces, err := k8sClient.ListNamespaces(ctx)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return namespaces, cobra.ShellCompDirectiveNoFileComp
+}
+
+// resourceCompletion completes type/name: resource types until a "/" is typed, then the names
+// of that type in the command's namespace
+func resourceCompletion(resourceTypes []string) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		resourceType, _, found := strings.Cut(toComplete, "/")
+		if !found {
+			completions := make([]string, 0, len(resourceTypes))
+			for _, t := range resourceTypes {
+				completions = append(completions, t+"/")
+			}
+			return completions, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+		}
+
+		k8sClient, err := completionClient(cmd)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		ctx, cancel := completionContext(cmd)
+		defer cancel()
+
+		namespace, _ := cmd.Flags().GetString("namespace")
+		names, err := k8sClient.ListResourceNames(ctx, namespace, resourceType)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		completions := make([]string, 0, len(names))
+		for _, name := range names {
+			completions = append(completions, resourceType+"/"+name)
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// singleArgCompletion stops completing once the command's only positional argument is given
+func singleArgCompletion(complete cobra.CompletionFunc) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return complete(cmd, args, toComplete)
+	}
+}
+
+// completePods completes pod names in the command's namespace
+func completePods(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	k8sClient, err := completionClient(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	ctx, cancel := completionContext(cmd)
+	defer cancel()
+
+	namespace, _ := cmd.Flags().GetString("namespace")
+	pods, err := k8sClient.ListResourceNames(ctx, namespace, "pods")
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return pods, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/helmcode/kubectl-ai/pkg/llm"
+	"github.com/spf13/cobra"
+)
+
+// DefaultTimeout bounds how long a single command may run
+const DefaultTimeout = 2 * time.Minute
+
+// commandContext derives the context for a command from cobra's context and the global --timeout flag
+func commandContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	if err != nil || timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// refreshCache reports whether the global --refresh-cache flag is set
+func refreshCache(cmd *cobra.Command) bool {
+	refresh, err := cmd.Flags().GetBool("refresh-cache")
+	return err == nil && refresh
+}
+
+// createLLM creates the LLM client for --provider and --model. The global --openai-base-url takes
+// precedence over OPENAI_BASE_URL, and is an error with any provider but openai.
+func createLLM(cmd *cobra.Command, provider, model string) (llm.LLM, error) {
+	var options llm.EnvOptions
+	if cmd.Flags().Changed("openai-base-url") {
+		options.OpenAIBaseURL, _ = cmd.Flags().GetString("openai-base-url")
+	}
+
+	llmClient, err := llm.CreateFromEnv(provider, model, options)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := llmClient.(*llm.OpenAI); options.OpenAIBaseURL != "" && !ok {
+		return nil, fmt.Errorf("--openai-base-url only applies to the openai provider (set --provider openai or LLM_PROVIDER=openai)")
+	}
+	return llmClient, nil
+}
+
+// responseCache wraps the LLM client in the on-disk response cache when --cache or
+// KUBECTL_AI_CACHE is set, unless the cache directory can't be located
+func responseCache(cmd *cobra.Command, llmClient llm.LLM) llm.LLM {
+	enabled, _ := cmd.Flags().GetBool("cache")
+	if env, err := strconv.ParseBool(os.Getenv("KUBECTL_AI_CACHE")); err == nil && !cmd.Flags().Changed("cache") {
+		enabled = env
+	}
+	if !enabled {
+		return llmClient
+	}
+
+	dir, err := llm.DefaultCacheDir()
+	if err != nil {
+		fmt.Fprintf(statusOutput, "⚠️  LLM response cache disabled: %v\n", err)
+		return llmClient
+	}
+	ttl, _ := cmd.Flags().GetDuration("cache-ttl")
+	noCache, _ := cmd.Flags().GetBool("no-cache")
+	return llm.NewCached(llmClient, dir, ttl, noCache)
+}
+
+// withContext runs fn in the background and returns early with ctx.Err() if ctx is done first.
+// It is used for calls such as LLM requests that don't accept a context themselves.
+func withContext[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	type result struct {
+		value T
+		err   error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		value, err := fn()
+		done <- result{value: value, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	case res := <-done:
+		return res.value, res.err
+	}
+}
@@ -2,32 +2,60 @@ package cmd
 
 import (
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"regexp"
 	"strings"
-	"time"
 
 	"path/filepath"
 
-	"github.com/briandowns/spinner"
 	"github.com/fatih/color"
 	"github.com/helmcode/kubectl-ai/pkg/analyzer"
 	"github.com/helmcode/kubectl-ai/pkg/formatter"
 	"github.com/helmcode/kubectl-ai/pkg/k8s"
 	"github.com/helmcode/kubectl-ai/pkg/llm"
+	"github.com/helmcode/kubectl-ai/pkg/model"
+	"github.com/helmcode/kubectl-ai/pkg/notify"
+	"github.com/helmcode/kubectl-ai/pkg/prompts"
 	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/util/homedir"
 )
 
 var (
-	kubeconfig   string
-	namespace    string
-	kubeContext  string
-	resources    []string
-	allResources bool
-	outputFormat string
-	verbose      bool
-	llmProvider  string
-	llmModel     string
+	kubeconfig       string
+	namespace        string
+	kubeContext      string
+	resources        []string
+	allResources     bool
+	selector         string
+	allNamespaces    bool
+	outputFormat     string
+	verbose          bool
+	llmProvider      string
+	llmModel         string
+	maxRetries       int
+	temperature      float64
+	maxTokens        int
+	includeLogs      bool
+	allEvents        bool
+	logLines         int64
+	concurrency      int
+	noRedact         bool
+	redactPattern    string
+	maxContextTokens int
+	dryRun           bool
+	interactive      bool
+	failOn           string
+	slackWebhook     string
+	webhookURL       string
+	webhookHeaders   []string
+	manifestFiles    []string
+	applyFix         bool
+	applyDestructive bool
+	maxResources     int
 )
 
 func NewDebugCmd() *cobra.Command {
@@ -43,9 +71,39 @@ Examples:
   # Debug multiple resources
   kubectl ai debug "secrets not updating" -r deployment/vault -r vaultstaticsecret/db-creds
 
+  # Triage the whole cluster
+  kubectl ai debug "pods pending" --all -A
+
   # Debug all resources in a namespace
   kubectl ai debug "application not working" -n production --all
 
+  # Debug every resource labeled app=api, whatever its name
+  kubectl ai debug "api returns 502" -n production -l app=api
+
+  # Include recent container logs in the analysis
+  kubectl ai debug "pods in CrashLoopBackOff" -r deployment/api --include-logs
+
+  # Ask follow-up questions after the analysis
+  kubectl ai debug "service returns 503" -r deployment/api -r service/api --interactive
+
+  # Fail a CI job when a high or critical issue is found
+  kubectl ai debug "post-deploy check" -n production --all -o json --fail-on high
+
+  # Post the root cause and top suggestions to a Slack channel
+  kubectl ai debug "pods are crashing" -r deployment/api --slack-webhook $SLACK_WEBHOOK_URL
+
+  # POST the analysis JSON to your own endpoint
+  kubectl ai debug "pods are crashing" -r deployment/api --webhook-url https://bot.example.com/hook --webhook-header "Authorization: Bearer $TOKEN"
+
+  # Run the suggested kubectl commands after confirming each one
+  kubectl ai debug "pods are crashing" -r deployment/api --apply
+
+  # Review manifests before applying them, without a cluster
+  kubectl ai debug "will this work" -f deploy.yaml -f service.yaml
+
+  # Review everything a Helm chart renders
+  helm template api ./chart | kubectl ai debug "will this work" -f -
+
   # Get detailed output
   kubectl ai debug "high memory usage" -r deployment/app -v`,
 		Args: cobra.ExactArgs(1),
@@ -58,109 +116,422 @@ Examples:
 	}
 
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Kubernetes namespace")
+	cmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "Analyze resources across all namespaces (use namespace/type/name with -r)")
 	cmd.Flags().StringVar(&kubeContext, "context", "", "Kubeconfig context (overrides current-context)")
 	cmd.Flags().StringSliceVarP(&resources, "resource", "r", []string{}, "Resources to analyze (e.g., deployment/nginx, pod/nginx-xxx)")
 	cmd.Flags().BoolVar(&allResources, "all", false, "Analyze all resources in the namespace")
-	cmd.Flags().StringVarP(&outputFormat, "output", "o", "human", "Output format (human, json, yaml)")
-	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
-	cmd.Flags().StringVar(&llmProvider, "provider", "", "LLM provider (claude, openai). Defaults to auto-detect from env")
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "Label selector to analyze matching resources instead of naming them (e.g. app=api,tier!=cache)")
+	cmd.Flags().StringSliceVarP(&manifestFiles, "filename", "f", nil, "Manifest files, directories or - for stdin to analyze instead of the cluster, e.g. before applying them (repeatable)")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "human", "Output format (human, json, yaml, markdown)")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Trace every Kubernetes API call and LLM request to stderr")
+	cmd.Flags().StringVar(&llmProvider, "provider", "", "LLM provider (claude, openai, gemini, ollama, azure, bedrock). Defaults to auto-detect from env")
 	cmd.Flags().StringVar(&llmModel, "model", "", "LLM model to use (overrides default)")
+	cmd.Flags().BoolVar(&includeLogs, "include-logs", false, "Include recent container logs of related pods in the analysis")
+	cmd.Flags().BoolVar(&allEvents, "all-events", false, "Include Normal events, not only Warnings (the 30 most recent events are kept either way)")
+	cmd.Flags().Int64Var(&logLines, "log-lines", k8s.DefaultLogTailLines, "Number of log lines per container to include with --include-logs")
+	cmd.Flags().IntVar(&maxResources, "max-resources", k8s.DefaultMaxResources, "Stop gathering after this many resources with --all or -l, to keep the prompt and its cost bounded (0 disables the limit)")
+	cmd.Flags().IntVar(&concurrency, "concurrency", k8s.DefaultConcurrency, "Maximum number of concurrent Kubernetes API requests")
+	cmd.Flags().IntVar(&maxRetries, "max-retries", llm.DefaultMaxRetries, "Maximum retries for transient LLM API errors (429, 5xx, network)")
+	cmd.Flags().Float64Var(&temperature, "temperature", llm.DefaultTemperature, "Sampling temperature for the LLM (0 keeps answers deterministic)")
+	cmd.Flags().IntVar(&maxTokens, "max-tokens", 0, "Maximum tokens in the LLM response; raise it if large analyses are cut off (0 uses the default of 4000, unlimited for Ollama)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the prompt and its estimated token count instead of calling the LLM")
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "After the analysis, ask follow-up questions with the gathered resources kept in context")
+	cmd.Flags().BoolVar(&applyFix, "apply", false, "After the analysis, run its kubectl commands against the cluster, asking before each one")
+	cmd.Flags().BoolVar(&applyDestructive, "apply-destructive", false, "With --apply, also offer to run commands flagged destructive")
+	cmd.Flags().StringVar(&failOn, "fail-on", "", "Exit non-zero when the overall or any issue severity is at or above this level (low, medium, high, critical)")
+	cmd.Flags().StringVar(&slackWebhook, "slack-webhook", "", "Slack incoming webhook URL to post the root cause, severity and top suggestions to (env: SLACK_WEBHOOK_URL)")
+	cmd.Flags().StringVar(&webhookURL, "webhook-url", "", "URL to POST the analysis to as JSON (the same document as -o json)")
+	cmd.Flags().StringArrayVar(&webhookHeaders, "webhook-header", nil, "Header to send with --webhook-url, e.g. \"Authorization: Bearer $TOKEN\" (repeatable)")
+	cmd.Flags().IntVar(&maxContextTokens, "max-context-tokens", 0, "Context window in tokens used to trim large prompts (0 uses the provider's default)")
+	cmd.Flags().BoolVar(&noRedact, "no-redact", false, "Send ConfigMap values, annotations and env var values to the LLM unmasked (trusted environments only)")
+	cmd.Flags().StringVar(&redactPattern, "redact-pattern", k8s.DefaultRedactPattern, "Regular expression of keys whose values are masked before reaching the LLM")
+
+	_ = cmd.MarkFlagFilename("filename", "yaml", "yml", "json")
+
+	registerCompletions(cmd, debugResourceTypes)
 
 	return cmd
 }
 
 func runDebug(cmd *cobra.Command, args []string) error {
 	problem := args[0]
+	configureOutput(cmd, outputFormat)
+	configureVerbose(verbose)
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
 
 	// Validate inputs
-	if !allResources && len(resources) == 0 {
-		return fmt.Errorf("either specify resources with -r or use --all flag")
+	if len(manifestFiles) > 0 {
+		if allResources || selector != "" || len(resources) > 0 || includeLogs {
+			return fmt.Errorf("-f reads resources from manifests and can't be combined with -r, -l, --all or --include-logs")
+		}
+		stdinReads := 0
+		for _, file := range manifestFiles {
+			if file == k8s.StdinManifest {
+				stdinReads++
+			}
+		}
+		if stdinReads > 1 {
+			return fmt.Errorf("-f - can only be passed once")
+		}
+		if stdinReads > 0 && interactive {
+			return fmt.Errorf("--interactive reads questions from stdin and can't be combined with -f -")
+		}
+	} else if !allResources && selector == "" && len(resources) == 0 {
+		return fmt.Errorf("either specify resources with -r, select them with -l, use --all flag or read manifests with -f")
+	}
+	if selector != "" && len(resources) > 0 {
+		return fmt.Errorf("-l selects resources by label and can't be combined with -r")
+	}
+	if err := validateSelector(selector); err != nil {
+		return err
+	}
+	if interactive && (outputFormat != "human" || dryRun) {
+		return fmt.Errorf("--interactive requires human output and can't be combined with --dry-run")
+	}
+	if maxResources < 0 {
+		return fmt.Errorf("--max-resources can't be negative (0 disables the limit)")
+	}
+	if applyDestructive && !applyFix {
+		return fmt.Errorf("--apply-destructive only takes effect with --apply")
+	}
+	if applyFix {
+		if len(manifestFiles) > 0 || dryRun || interactive {
+			return fmt.Errorf("--apply runs commands against the cluster and can't be combined with -f, --dry-run or --interactive")
+		}
+		if !isTerminal(os.Stdin) {
+			return fmt.Errorf("--apply asks before running each command and needs an interactive terminal")
+		}
+	}
+	generation := llm.GenerationOptions{Temperature: temperature, MaxTokens: maxTokens}
+	if err := generation.Validate(); err != nil {
+		return fmt.Errorf("invalid LLM options: %w", err)
+	}
+	if failOn != "" {
+		if _, ok := model.SeverityLevel(failOn); !ok {
+			return fmt.Errorf("invalid --fail-on %q: must be one of low, medium, high, critical", failOn)
+		}
+	}
+	webhookHeader, err := webhookOptions(webhookURL, webhookHeaders)
+	if err != nil {
+		return err
+	}
+
+	// Resolve the kube context before the header so it shows which cluster is analyzed
+	var k8sClient *k8s.Client
+	if len(manifestFiles) == 0 {
+		// Expand home symbol in kubeconfig if needed
+		if strings.HasPrefix(kubeconfig, "~/") {
+			if homeDir, err := os.UserHomeDir(); err == nil {
+				kubeconfig = filepath.Join(homeDir, kubeconfig[2:])
+			}
+		}
+
+		// Initialize K8s client
+		if k8sClient, err = k8s.NewClient(kubeconfig, kubeContext); err != nil {
+			return fmt.Errorf("failed to connect to cluster: %w", err)
+		}
 	}
 
 	// Show what we're doing
-	printHeader(problem)
+	printHeader(problem, k8sClient)
 
 	// Create spinner for visual feedback
-	s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	s.Suffix = " Connecting to Kubernetes cluster..."
-	s.Start()
+	s := newSpinner()
 
-	// Expand home symbol in kubeconfig if needed
-	if strings.HasPrefix(kubeconfig, "~/") {
-		if homeDir, err := os.UserHomeDir(); err == nil {
-			kubeconfig = filepath.Join(homeDir, kubeconfig[2:])
+	var resourcesData map[string]interface{}
+	if len(manifestFiles) > 0 {
+		if resourcesData, err = loadManifests(manifestFiles, noRedact, redactPattern); err != nil {
+			return err
+		}
+		// Don't count the list of files stored alongside the resources
+		files, _ := resourcesData[k8s.ManifestsKey].([]string)
+		printSuccess(fmt.Sprintf("Read %d resources from %d manifest files", len(resourcesData)-1, len(files)))
+	} else {
+		k8sClient.SetConcurrency(concurrency)
+		k8sClient.SetMaxResources(maxResources)
+		k8sClient.SetIncludeNormalEvents(allEvents)
+		k8sClient.SetRefreshDiscoveryCache(refreshCache(cmd))
+		if err := configureRedaction(k8sClient, noRedact, redactPattern); err != nil {
+			return err
+		}
+		if includeLogs {
+			k8sClient.SetLogTailLines(logLines)
 		}
-	}
 
-	// Initialize K8s client
-	k8sClient, err := k8s.NewClient(kubeconfig, kubeContext)
-	if err != nil {
-		s.Stop()
-		return fmt.Errorf("failed to connect to cluster: %w", err)
-	}
-	s.Stop()
-	printSuccess("Connected to Kubernetes cluster")
+		s.Suffix = " Gathering Kubernetes resources..."
+		s.Start()
 
-	s.Suffix = " Gathering Kubernetes resources..."
-	s.Start()
+		resourcesData, err = k8sClient.GatherResources(ctx, targetNamespace(namespace, allNamespaces), resources, allResources, selector)
+		if err != nil {
+			s.Stop()
+			return fmt.Errorf("failed to gather resources: %w", err)
+		}
 
-	resourcesData, err := k8sClient.GatherResources(namespace, resources, allResources)
-	if err != nil {
 		s.Stop()
-		return fmt.Errorf("failed to gather resources: %w", err)
+		printSuccess(fmt.Sprintf("Gathered %d resources", len(resourcesData)))
 	}
 
-	s.Stop()
-	printSuccess(fmt.Sprintf("Gathered %d resources", len(resourcesData)))
-
 	s.Suffix = " Initializing AI client..."
 	s.Start()
 
 	// Initialize LLM client using factory
-	llmClient, err := llm.CreateFromEnv(llmProvider, llmModel)
-	if err != nil {
+	llmClient, err := createLLM(cmd, llmProvider, llmModel)
+	if err != nil && !dryRun {
 		s.Stop()
 		return fmt.Errorf("failed to initialize LLM client: %w", err)
 	}
-
 	s.Stop()
-	printSuccess("AI client initialized")
 
-	// Show LLM provider and model info
-	printLLMInfo(llmClient)
-	fmt.Println()
+	if llmClient != nil {
+		llmClient = responseCache(cmd, llmClient)
+		llm.SetMaxRetries(llmClient, maxRetries)
+		if err := llm.SetGenerationOptions(llmClient, generation); err != nil {
+			return fmt.Errorf("invalid LLM options: %w", err)
+		}
+		printSuccess("AI client initialized")
 
-	s.Suffix = " Analyzing with AI..."
-	s.Start()
+		// Show LLM provider and model info
+		printLLMInfo(llmClient)
+		fmt.Fprintln(statusOutput)
+	}
 
 	aiAnalyzer := analyzer.NewWithLLM(llmClient)
-	analysis, err := aiAnalyzer.Analyze(problem, resourcesData)
-	if err != nil {
-		s.Stop()
-		return fmt.Errorf("AI analysis failed: %w", err)
+	aiAnalyzer.SetMaxContextTokens(maxContextTokens)
+
+	if dryRun {
+		// A dry run only needs the LLM client to size the prompt, so a missing API key is fine
+		prompt, err := aiAnalyzer.BuildPrompt(problem, resourcesData)
+		if err != nil {
+			return fmt.Errorf("failed to build prompt: %w", err)
+		}
+		printDryRun("", prompt)
+		return nil
 	}
 
-	s.Stop()
+	var analysis *model.Analysis
+	if outputFormat == "human" && isTerminal(os.Stdout) && aiAnalyzer.CanStream() {
+		// Stream tokens as they arrive so the user gets immediate feedback
+		analysis, err = withContext(ctx, func() (*model.Analysis, error) {
+			return streamAnalysis(aiAnalyzer, problem, resourcesData)
+		})
+		if err != nil {
+			return fmt.Errorf("AI analysis failed: %w", err)
+		}
+	} else {
+		s.Suffix = " Analyzing with AI..."
+		s.Start()
+
+		analysis, err = withContext(ctx, func() (*model.Analysis, error) {
+			return aiAnalyzer.Analyze(problem, resourcesData)
+		})
+		if err != nil {
+			s.Stop()
+			return fmt.Errorf("AI analysis failed: %w", err)
+		}
+
+		s.Stop()
+	}
 	printSuccess("Analysis complete")
 
 	formatter.DisplayResults(analysis, outputFormat)
 
+	if slackWebhook == "" {
+		slackWebhook = os.Getenv("SLACK_WEBHOOK_URL")
+	}
+	if slackWebhook != "" {
+		if err := notify.PostSlack(ctx, slackWebhook, analysis); err != nil {
+			return fmt.Errorf("failed to notify Slack: %w", err)
+		}
+		printSuccess("Summary posted to Slack")
+	}
+	if webhookURL != "" {
+		if err := notify.PostJSON(ctx, webhookURL, webhookHeader, analysis); err != nil {
+			return fmt.Errorf("failed to post analysis to webhook: %w", err)
+		}
+		printSuccess("Analysis posted to webhook")
+	}
+
+	if applyFix {
+		if err := applyFixes(cmd, k8sClient, analysis, applyDestructive); err != nil {
+			return err
+		}
+	}
+
+	if interactive {
+		conversation, err := aiAnalyzer.StartConversation(problem, resourcesData, analysis)
+		if err != nil {
+			return fmt.Errorf("failed to start follow-up chat: %w", err)
+		}
+		if err := followUpChat(cmd, conversation); err != nil {
+			return err
+		}
+	}
+
+	if failOn != "" && analysis.MeetsSeverity(failOn) {
+		return fmt.Errorf("severity %s meets --fail-on threshold %s", strings.ToUpper(analysis.MaxSeverity()), strings.ToUpper(failOn))
+	}
+
 	return nil
 }
 
-func printHeader(problem string) {
+// printDryRun prints a prompt that would have been sent to the LLM along with its estimated size
+func printDryRun(title, prompt string) {
+	yellow := color.New(color.FgYellow, color.Bold)
+	if title != "" {
+		yellow.Printf("🧪 DRY RUN: prompt for %s\n", title)
+	} else {
+		yellow.Println("🧪 DRY RUN: prompt that would be sent to the LLM")
+	}
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Println(prompt)
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Printf("📏 Estimated tokens: %d (%d characters)\n\n", prompts.EstimateTokens(prompt), len(prompt))
+}
+
+// streamAnalysis runs the analysis while echoing the raw LLM response to the terminal
+func streamAnalysis(aiAnalyzer *analyzer.Analyzer, problem string, resourcesData map[string]interface{}) (*model.Analysis, error) {
+	color.New(color.FgCyan).Println("🤖 AI response:")
+
+	out := make(chan string)
+	printed := make(chan struct{})
+	go func() {
+		for chunk := range out {
+			fmt.Print(color.HiBlackString(chunk))
+		}
+		fmt.Println()
+		close(printed)
+	}()
+
+	analysis, err := aiAnalyzer.AnalyzeStream(problem, resourcesData, out)
+	<-printed
+	return analysis, err
+}
+
+// isTerminal reports whether the file is attached to a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func printHeader(problem string, k8sClient *k8s.Client) {
 	cyan := color.New(color.FgCyan, color.Bold)
-	fmt.Println()
-	cyan.Println("🔍 Kubernetes AI Debugger")
-	fmt.Printf("📝 Problem: %s\n", problem)
-	fmt.Printf("📍 Namespace: %s\n", namespace)
+	fmt.Fprintln(statusOutput)
+	cyan.Fprintln(statusOutput, "🔍 Kubernetes AI Debugger")
+	fmt.Fprintf(statusOutput, "📝 Problem: %s\n", problem)
+	if len(manifestFiles) > 0 {
+		sources := make([]string, len(manifestFiles))
+		for i, file := range manifestFiles {
+			sources[i] = manifestSource(file)
+		}
+		fmt.Fprintf(statusOutput, "📄 Manifests: %s\n\n", strings.Join(sources, ", "))
+		return
+	}
+	printContext(k8sClient)
+	printNamespace(namespace, allNamespaces)
+
+	switch {
+	case selector != "":
+		fmt.Fprintf(statusOutput, "📊 Resources: matching %s\n", selector)
+	case allResources:
+		fmt.Fprintln(statusOutput, "📊 Resources: all")
+	default:
+		fmt.Fprintf(statusOutput, "📊 Resources: %s\n", strings.Join(resources, ", "))
+	}
+	fmt.Fprintln(statusOutput)
+}
 
-	if allResources {
-		fmt.Println("📊 Resources: all")
-	} else {
-		fmt.Printf("📊 Resources: %s\n", strings.Join(resources, ", "))
+// configureRedaction applies --no-redact and --redact-pattern to the client
+func configureRedaction(k8sClient *k8s.Client, disabled bool, pattern string) error {
+	re, err := redaction(disabled, pattern)
+	if err != nil {
+		return err
 	}
-	fmt.Println()
+	k8sClient.SetRedactPattern(re)
+	return nil
+}
+
+// redaction compiles --redact-pattern, or returns nil with --no-redact
+func redaction(disabled bool, pattern string) (*regexp.Regexp, error) {
+	if disabled {
+		return nil, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --redact-pattern: %w", err)
+	}
+	return re, nil
+}
+
+// loadManifests reads -f files, directories and stdin into the same shape GatherResources
+// returns, without a cluster
+func loadManifests(files []string, disabled bool, pattern string) (map[string]interface{}, error) {
+	redact, err := redaction(disabled, pattern)
+	if err != nil {
+		return nil, err
+	}
+	manifests, err := k8s.ReadManifests(files, os.Stdin)
+	if err != nil {
+		return nil, err
+	}
+	return k8s.ResourcesFromManifests(manifests, redact)
+}
+
+// validateSelector rejects a malformed -l selector up front, since failed list calls are tolerated
+// while gathering and would otherwise just find nothing
+func validateSelector(selector string) error {
+	if selector == "" {
+		return nil
+	}
+	if _, err := labels.Parse(selector); err != nil {
+		return fmt.Errorf("invalid label selector: %w", err)
+	}
+	return nil
+}
+
+// webhookOptions validates --webhook-url and parses --webhook-header before any work is done, so a
+// typo doesn't surface only after a long analysis
+func webhookOptions(webhookURL string, headers []string) (http.Header, error) {
+	if webhookURL == "" {
+		if len(headers) > 0 {
+			return nil, fmt.Errorf("--webhook-header requires --webhook-url")
+		}
+		return nil, nil
+	}
+	if parsed, err := url.Parse(webhookURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid --webhook-url %q: must be an http or https URL", webhookURL)
+	}
+	header, err := notify.ParseHeaders(headers)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --webhook-header: %w", err)
+	}
+	return header, nil
+}
+
+// targetNamespace returns the namespace to gather from, metav1.NamespaceAll with --all-namespaces
+func targetNamespace(namespace string, allNamespaces bool) string {
+	if allNamespaces {
+		return metav1.NamespaceAll
+	}
+	return namespace
+}
+
+// printContext shows the kube context and API server a command runs against, so an analysis
+// of the wrong cluster is caught before it starts
+func printContext(k8sClient *k8s.Client) {
+	fmt.Fprintf(statusOutput, "☸️  Context: %s (server: %s)\n", contextLabel(k8sClient), k8sClient.GetServer())
+}
+
+func printNamespace(namespace string, allNamespaces bool) {
+	if allNamespaces {
+		fmt.Fprintln(statusOutput, "📍 Namespace: all namespaces")
+		return
+	}
+	fmt.Fprintf(statusOutput, "📍 Namespace: %s\n", namespace)
 }
 
 func printLLMInfo(llmClient llm.LLM) {
@@ -169,24 +540,42 @@ func printLLMInfo(llmClient llm.LLM) {
 	model := "unknown"
 
 	// Type assertion to get provider and model information
-	switch client := llmClient.(type) {
+	switch client := llm.Unwrap(llmClient).(type) {
 	case *llm.Claude:
 		provider = "claude"
 		model = client.GetModel()
 	case *llm.OpenAI:
 		provider = "openai"
+		if client.BaseURL() != llm.DefaultOpenAIBaseURL {
+			provider = "openai at " + client.BaseURL()
+		}
+		model = client.GetModel()
+	case *llm.Gemini:
+		provider = "gemini"
+		model = client.GetModel()
+	case *llm.Ollama:
+		provider = "ollama"
+		model = client.GetModel()
+	case *llm.AzureOpenAI:
+		provider = "azure"
+		model = client.GetModel()
+	case *llm.Bedrock:
+		provider = "bedrock"
 		model = client.GetModel()
 	}
 
-	fmt.Printf("✓ LLM Provider: %s (%s)\n", provider, model)
+	fmt.Fprintf(statusOutput, "✓ LLM Provider: %s (%s)\n", provider, model)
+	if cached, ok := llmClient.(*llm.Cached); ok {
+		fmt.Fprintf(statusOutput, "✓ LLM responses cached for %s\n", cached.TTL())
+	}
 }
 
 func printSuccess(msg string) {
 	green := color.New(color.FgGreen)
-	green.Printf("✓ %s\n", msg)
+	green.Fprintf(statusOutput, "✓ %s\n", msg)
 }
 
 func printError(msg string) {
 	red := color.New(color.FgRed)
-	red.Printf("✗ %s\n", msg)
+	red.Fprintf(statusOutput, "✗ %s\n", msg)
 }
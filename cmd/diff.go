@@ -0,0 +1,326 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/helmcode/kubectl-ai/pkg/analyzer"
+	"github.com/helmcode/kubectl-ai/pkg/formatter"
+	"github.com/helmcode/kubectl-ai/pkg/k8s"
+	"github.com/helmcode/kubectl-ai/pkg/llm"
+	"github.com/helmcode/kubectl-ai/pkg/model"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/util/homedir"
+)
+
+var (
+	// Common flags (similar to debug command)
+	diffKubeconfig    string
+	diffNamespace     string
+	diffKubeContext   string
+	diffOutputFormat  string
+	diffLLMProvider   string
+	diffLLMModel      string
+	diffMaxRetries    int
+	diffTemperature   float64
+	diffMaxTokens     int
+	diffNoRedact      bool
+	diffRedactPattern string
+
+	// Diff-specific flags
+	diffFiles        []string
+	diffFromRevision int64
+	diffToRevision   int64
+	diffSymptom      string
+)
+
+// revisionResourceTypes are the resource types with a rollout history
+var revisionResourceTypes = []string{"deployment", "statefulset", "daemonset"}
+
+func NewDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff [RESOURCE] [flags]",
+		Short: "Explain the changes between two versions of a resource with AI",
+		Long: `Compare two manifests, or two revisions from a workload's rollout history, and
+use AI to explain what changed and whether the change is a likely cause of a symptom.
+
+Examples:
+  # Explain the changes between two manifest files
+  kubectl ai diff -f old.yaml -f new.yaml
+
+  # Explain what an upgrade of a chart changes, comparing every resource it renders
+  helm template api ./chart -f new-values.yaml | kubectl ai diff -f rendered-before.yaml -f -
+
+  # Ask whether the change explains a symptom
+  kubectl ai diff -f old.yaml -f new.yaml --symptom "pods crash on startup"
+
+  # Compare the last two revisions of a deployment's rollout history
+  kubectl ai diff deploy/api -n production --symptom "latency doubled after the rollout"
+
+  # Compare specific revisions
+  kubectl ai diff deploy/api --from-revision 3 --to-revision 5`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: singleArgCompletion(resourceCompletion(revisionResourceTypes)),
+		RunE:              runDiff,
+	}
+
+	if home := homedir.HomeDir(); home != "" {
+		cmd.Flags().StringVar(&diffKubeconfig, "kubeconfig", "~/.kube/config", "Path to kubeconfig file")
+	}
+
+	cmd.Flags().StringVarP(&diffNamespace, "namespace", "n", "default", "Kubernetes namespace")
+	cmd.Flags().StringVar(&diffKubeContext, "context", "", "Kubeconfig context (overrides current-context)")
+	cmd.Flags().StringVarP(&diffOutputFormat, "output", "o", "human", "Output format (human, json, yaml, markdown)")
+	cmd.Flags().StringVar(&diffLLMProvider, "provider", "", "LLM provider (claude, openai, gemini, ollama, azure, bedrock). Defaults to auto-detect from env")
+	cmd.Flags().StringVar(&diffLLMModel, "model", "", "LLM model to use (overrides default)")
+	cmd.Flags().IntVar(&diffMaxRetries, "max-retries", llm.DefaultMaxRetries, "Maximum retries for transient LLM API errors (429, 5xx, network)")
+	cmd.Flags().Float64Var(&diffTemperature, "temperature", llm.DefaultTemperature, "Sampling temperature for the LLM (0 keeps answers deterministic)")
+	cmd.Flags().IntVar(&diffMaxTokens, "max-tokens", 0, "Maximum tokens in the LLM response; raise it if large analyses are cut off (0 uses the default of 4000, unlimited for Ollama)")
+	cmd.Flags().BoolVar(&diffNoRedact, "no-redact", false, "Send changed env var, annotation and data values to the LLM unmasked (trusted environments only)")
+	cmd.Flags().StringVar(&diffRedactPattern, "redact-pattern", k8s.DefaultRedactPattern, "Regular expression of keys whose values are masked before reaching the LLM")
+
+	// Diff-specific flags
+	cmd.Flags().StringSliceVarP(&diffFiles, "filename", "f", nil, "Manifests to compare, old first then new (pass -f twice; each may be a file, a directory or - for stdin)")
+	cmd.Flags().Int64Var(&diffFromRevision, "from-revision", 0, "Rollout history revision to compare from (defaults to the one before --to-revision)")
+	cmd.Flags().Int64Var(&diffToRevision, "to-revision", 0, "Rollout history revision to compare to (defaults to the latest)")
+	cmd.Flags().StringVar(&diffSymptom, "symptom", "", "Problem seen after the change, e.g. \"pods crash on startup\"")
+	_ = cmd.MarkFlagFilename("filename", "yaml", "yml", "json")
+
+	registerCompletions(cmd, nil)
+
+	return cmd
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	configureOutput(cmd, diffOutputFormat)
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	switch {
+	case len(diffFiles) > 0 && len(args) > 0:
+		return fmt.Errorf("pass either two -f files or a RESOURCE, not both")
+	case len(diffFiles) > 0 && len(diffFiles) != 2:
+		return fmt.Errorf("-f needs exactly two files, old then new (got %d)", len(diffFiles))
+	case len(diffFiles) == 2 && diffFiles[0] == k8s.StdinManifest && diffFiles[1] == k8s.StdinManifest:
+		return fmt.Errorf("only one side of the diff can be read from stdin")
+	case len(diffFiles) == 0 && len(args) == 0:
+		return fmt.Errorf("either pass two manifests with -f old.yaml -f new.yaml, or a RESOURCE to compare revisions of")
+	case len(diffFiles) > 0 && (diffFromRevision != 0 || diffToRevision != 0):
+		return fmt.Errorf("--from-revision and --to-revision compare a RESOURCE's rollout history and can't be used with -f")
+	}
+
+	generation := llm.GenerationOptions{Temperature: diffTemperature, MaxTokens: diffMaxTokens}
+	if err := generation.Validate(); err != nil {
+		return fmt.Errorf("invalid LLM options: %w", err)
+	}
+
+	var redact *regexp.Regexp
+	if !diffNoRedact {
+		var err error
+		redact, err = regexp.Compile(diffRedactPattern)
+		if err != nil {
+			return fmt.Errorf("invalid --redact-pattern: %w", err)
+		}
+	}
+
+	var subject, from, to string
+	var changes []k8s.FieldChange
+	if len(diffFiles) == 2 {
+		oldManifests, err := k8s.ReadManifests(diffFiles[:1], os.Stdin)
+		if err != nil {
+			return err
+		}
+		newManifests, err := k8s.ReadManifests(diffFiles[1:], os.Stdin)
+		if err != nil {
+			return err
+		}
+		from, to = manifestSource(diffFiles[0]), manifestSource(diffFiles[1])
+
+		// A single resource on each side is compared even if it was renamed
+		if len(oldManifests) == 1 && len(newManifests) == 1 {
+			subject = manifestName(newManifests[0].Object)
+			changes = k8s.DiffManifests(oldManifests[0].Object, newManifests[0].Object, redact)
+		} else {
+			subject = fmt.Sprintf("%d resources", max(len(oldManifests), len(newManifests)))
+			if changes, err = k8s.DiffManifestSets(oldManifests, newManifests, redact); err != nil {
+				return err
+			}
+		}
+		printDiffHeader(subject, from, to)
+	} else {
+		subject = args[0]
+		printDiffHeader(subject, "", "")
+
+		s := newSpinner()
+		s.Suffix = " Connecting to Kubernetes cluster..."
+		s.Start()
+
+		// Expand home symbol in kubeconfig if needed
+		if strings.HasPrefix(diffKubeconfig, "~/") {
+			if homeDir, err := os.UserHomeDir(); err == nil {
+				diffKubeconfig = filepath.Join(homeDir, diffKubeconfig[2:])
+			}
+		}
+
+		k8sClient, err := k8s.NewClient(diffKubeconfig, diffKubeContext)
+		if err != nil {
+			s.Stop()
+			return fmt.Errorf("failed to connect to cluster: %w", err)
+		}
+		s.Stop()
+		printSuccess("Connected to Kubernetes cluster")
+
+		s.Suffix = " Reading rollout history..."
+		s.Start()
+		revisions, err := k8sClient.RolloutHistory(ctx, diffNamespace, subject)
+		s.Stop()
+		if err != nil {
+			return fmt.Errorf("failed to read rollout history: %w", err)
+		}
+
+		fromRevision, toRevision, err := pickRevisions(revisions, diffFromRevision, diffToRevision)
+		if err != nil {
+			return err
+		}
+		from, to = fmt.Sprintf("revision %d", fromRevision.Number), fmt.Sprintf("revision %d", toRevision.Number)
+		printSuccess(fmt.Sprintf("Comparing pod templates of %s and %s", from, to))
+		changes = k8s.DiffManifests(fromRevision.Template, toRevision.Template, redact)
+	}
+
+	if len(changes) == 0 {
+		printSuccess(fmt.Sprintf("No differences between %s and %s", from, to))
+		return nil
+	}
+	printSuccess(fmt.Sprintf("Found %d changed fields", len(changes)))
+
+	s := newSpinner()
+	s.Suffix = " Initializing AI client..."
+	s.Start()
+
+	// Initialize LLM client using factory
+	llmClient, err := createLLM(cmd, diffLLMProvider, diffLLMModel)
+	if err != nil {
+		s.Stop()
+		return fmt.Errorf("failed to initialize LLM client: %w", err)
+	}
+	llmClient = responseCache(cmd, llmClient)
+	llm.SetMaxRetries(llmClient, diffMaxRetries)
+	if err := llm.SetGenerationOptions(llmClient, generation); err != nil {
+		return fmt.Errorf("invalid LLM options: %w", err)
+	}
+
+	s.Stop()
+	printSuccess("AI client initialized")
+
+	// Show LLM provider and model info
+	printLLMInfo(llmClient)
+	fmt.Fprintln(statusOutput)
+
+	s.Suffix = " Analyzing changes with AI..."
+	s.Start()
+
+	aiAnalyzer := analyzer.NewWithLLM(llmClient)
+	analysis, err := withContext(ctx, func() (*model.Analysis, error) {
+		return aiAnalyzer.AnalyzeDiff(subject, from, to, diffSymptom, changes)
+	})
+	if err != nil {
+		s.Stop()
+		return fmt.Errorf("AI analysis failed: %w", err)
+	}
+
+	s.Stop()
+	printSuccess("Analysis complete")
+
+	formatter.DisplayResults(analysis, diffOutputFormat)
+
+	return nil
+}
+
+// manifestSource names where a -f argument is read from, for headers and prompts
+func manifestSource(path string) string {
+	if path == k8s.StdinManifest {
+		return "stdin"
+	}
+	return path
+}
+
+// manifestName returns kind/name of a manifest, for display and prompts
+func manifestName(manifest map[string]interface{}) string {
+	kind, _ := manifest["kind"].(string)
+	var name string
+	if metadata, ok := manifest["metadata"].(map[string]interface{}); ok {
+		name, _ = metadata["name"].(string)
+	}
+	switch {
+	case kind != "" && name != "":
+		return strings.ToLower(kind) + "/" + name
+	case kind != "":
+		return strings.ToLower(kind)
+	default:
+		return "manifest"
+	}
+}
+
+// pickRevisions finds the requested revisions, defaulting to the latest one and the one before it
+func pickRevisions(revisions []k8s.Revision, from, to int64) (k8s.Revision, k8s.Revision, error) {
+	toIndex := len(revisions) - 1
+	if to != 0 {
+		toIndex = revisionIndex(revisions, to)
+		if toIndex < 0 {
+			return k8s.Revision{}, k8s.Revision{}, fmt.Errorf("revision %d not found (available: %s)", to, revisionNumbers(revisions))
+		}
+	}
+
+	fromIndex := toIndex - 1
+	if from != 0 {
+		fromIndex = revisionIndex(revisions, from)
+		if fromIndex < 0 {
+			return k8s.Revision{}, k8s.Revision{}, fmt.Errorf("revision %d not found (available: %s)", from, revisionNumbers(revisions))
+		}
+	}
+	if fromIndex < 0 {
+		return k8s.Revision{}, k8s.Revision{}, fmt.Errorf("no revision before %d to compare with (available: %s)", revisions[toIndex].Number, revisionNumbers(revisions))
+	}
+
+	return revisions[fromIndex], revisions[toIndex], nil
+}
+
+func revisionIndex(revisions []k8s.Revision, number int64) int {
+	for i, revision := range revisions {
+		if revision.Number == number {
+			return i
+		}
+	}
+	return -1
+}
+
+func revisionNumbers(revisions []k8s.Revision) string {
+	numbers := make([]string, len(revisions))
+	for i, revision := range revisions {
+		numbers[i] = fmt.Sprint(revision.Number)
+	}
+	return strings.Join(numbers, ", ")
+}
+
+func printDiffHeader(subject, from, to string) {
+	cyan := color.New(color.FgCyan, color.Bold)
+	fmt.Fprintln(statusOutput)
+	cyan.Fprintln(statusOutput, "🔀 Kubernetes AI Change Analyzer")
+	fmt.Fprintf(statusOutput, "📦 Resource: %s\n", subject)
+	if from != "" {
+		fmt.Fprintf(statusOutput, "📄 Before: %s\n", from)
+		fmt.Fprintf(statusOutput, "📄 After: %s\n", to)
+	} else {
+		fmt.Fprintf(statusOutput, "📍 Namespace: %s\n", diffNamespace)
+	}
+	if diffSymptom != "" {
+		fmt.Fprintf(statusOutput, "🔍 Symptom: %s\n", diffSymptom)
+	}
+	fmt.Fprintln(statusOutput)
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/helmcode/kubectl-ai/pkg/analyzer"
+	"github.com/helmcode/kubectl-ai/pkg/formatter"
+	"github.com/helmcode/kubectl-ai/pkg/k8s"
+	"github.com/helmcode/kubectl-ai/pkg/llm"
+	"github.com/helmcode/kubectl-ai/pkg/model"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/util/homedir"
+)
+
+var (
+	// Common flags (similar to debug command)
+	explainKubeconfig    string
+	explainNamespace     string
+	explainKubeContext   string
+	explainOutputFormat  string
+	explainLLMProvider   string
+	explainLLMModel      string
+	explainMaxRetries    int
+	explainTemperature   float64
+	explainMaxTokens     int
+	explainNoRedact      bool
+	explainRedactPattern string
+)
+
+func NewExplainCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explain RESOURCE [flags]",
+		Short: "Describe a resource in plain English",
+		Long: `Gather a resource with its pods and the Services and Ingresses that route to it,
+and use AI to explain what it does: images, ports, probes, dependencies and exposure.
+
+Examples:
+  # Explain a deployment
+  kubectl ai explain deploy/api -n production
+
+  # Explain a StatefulSet as Markdown for onboarding docs
+  kubectl ai explain statefulset/postgres -o markdown > postgres.md`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: singleArgCompletion(resourceCompletion(debugResourceTypes)),
+		RunE:              runExplain,
+	}
+
+	if home := homedir.HomeDir(); home != "" {
+		cmd.Flags().StringVar(&explainKubeconfig, "kubeconfig", "~/.kube/config", "Path to kubeconfig file")
+	}
+
+	cmd.Flags().StringVarP(&explainNamespace, "namespace", "n", "default", "Kubernetes namespace")
+	cmd.Flags().StringVar(&explainKubeContext, "context", "", "Kubeconfig context (overrides current-context)")
+	cmd.Flags().StringVarP(&explainOutputFormat, "output", "o", "human", "Output format (human, json, yaml, markdown)")
+	cmd.Flags().StringVar(&explainLLMProvider, "provider", "", "LLM provider (claude, openai, gemini, ollama, azure, bedrock). Defaults to auto-detect from env")
+	cmd.Flags().StringVar(&explainLLMModel, "model", "", "LLM model to use (overrides default)")
+	cmd.Flags().IntVar(&explainMaxRetries, "max-retries", llm.DefaultMaxRetries, "Maximum retries for transient LLM API errors (429, 5xx, network)")
+	cmd.Flags().Float64Var(&explainTemperature, "temperature", llm.DefaultTemperature, "Sampling temperature for the LLM (0 keeps answers deterministic)")
+	cmd.Flags().IntVar(&explainMaxTokens, "max-tokens", 0, "Maximum tokens in the LLM response; raise it if large analyses are cut off (0 uses the default of 4000, unlimited for Ollama)")
+	cmd.Flags().BoolVar(&explainNoRedact, "no-redact", false, "Send ConfigMap values, annotations and env var values to the LLM unmasked (trusted environments only)")
+	cmd.Flags().StringVar(&explainRedactPattern, "redact-pattern", k8s.DefaultRedactPattern, "Regular expression of keys whose values are masked before reaching the LLM")
+
+	registerCompletions(cmd, nil)
+
+	return cmd
+}
+
+func runExplain(cmd *cobra.Command, args []string) error {
+	resource := args[0]
+	configureOutput(cmd, explainOutputFormat)
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	generation := llm.GenerationOptions{Temperature: explainTemperature, MaxTokens: explainMaxTokens}
+	if err := generation.Validate(); err != nil {
+		return fmt.Errorf("invalid LLM options: %w", err)
+	}
+
+	printExplainHeader(resource)
+
+	// Create spinner for visual feedback
+	s := newSpinner()
+	s.Suffix = " Connecting to Kubernetes cluster..."
+	s.Start()
+
+	// Expand home symbol in kubeconfig if needed
+	if strings.HasPrefix(explainKubeconfig, "~/") {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			explainKubeconfig = filepath.Join(homeDir, explainKubeconfig[2:])
+		}
+	}
+
+	// Initialize K8s client
+	k8sClient, err := k8s.NewClient(explainKubeconfig, explainKubeContext)
+	if err != nil {
+		s.Stop()
+		return fmt.Errorf("failed to connect to cluster: %w", err)
+	}
+	k8sClient.SetRefreshDiscoveryCache(refreshCache(cmd))
+	s.Stop()
+	printSuccess("Connected to Kubernetes cluster")
+
+	if err := configureRedaction(k8sClient, explainNoRedact, explainRedactPattern); err != nil {
+		return err
+	}
+
+	// Fail fast with a clear message instead of explaining an empty result
+	if err := k8sClient.CheckResource(ctx, explainNamespace, resource); err != nil {
+		return err
+	}
+
+	s.Suffix = " Gathering resource..."
+	s.Start()
+
+	resourcesData, err := k8sClient.GatherResources(ctx, explainNamespace, []string{resource}, false, "")
+	if err != nil {
+		s.Stop()
+		return fmt.Errorf("failed to gather resources: %w", err)
+	}
+	// Namespace events say what happened recently, not what the resource is
+	delete(resourcesData, "events")
+
+	if err := k8sClient.AddExposure(ctx, explainNamespace, resource, resourcesData); err != nil && ctx.Err() == nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to find Services for %s: %v\n", resource, err)
+	}
+
+	s.Stop()
+	printSuccess(fmt.Sprintf("Gathered %s and %d related objects", resource, len(resourcesData)-1))
+
+	s.Suffix = " Initializing AI client..."
+	s.Start()
+
+	// Initialize LLM client using factory
+	llmClient, err := createLLM(cmd, explainLLMProvider, explainLLMModel)
+	if err != nil {
+		s.Stop()
+		return fmt.Errorf("failed to initialize LLM client: %w", err)
+	}
+	llmClient = responseCache(cmd, llmClient)
+	llm.SetMaxRetries(llmClient, explainMaxRetries)
+	if err := llm.SetGenerationOptions(llmClient, generation); err != nil {
+		return fmt.Errorf("invalid LLM options: %w", err)
+	}
+
+	s.Stop()
+	printSuccess("AI client initialized")
+
+	// Show LLM provider and model info
+	printLLMInfo(llmClient)
+	fmt.Fprintln(statusOutput)
+
+	s.Suffix = " Explaining with AI..."
+	s.Start()
+
+	aiAnalyzer := analyzer.NewWithLLM(llmClient)
+	analysis, err := withContext(ctx, func() (*model.Analysis, error) {
+		return aiAnalyzer.Explain(resource, resourcesData)
+	})
+	if err != nil {
+		s.Stop()
+		return fmt.Errorf("AI explanation failed: %w", err)
+	}
+
+	s.Stop()
+	printSuccess("Explanation complete")
+
+	formatter.DisplayResults(analysis, explainOutputFormat)
+
+	return nil
+}
+
+func printExplainHeader(resource string) {
+	cyan := color.New(color.FgCyan, color.Bold)
+	fmt.Fprintln(statusOutput)
+	cyan.Fprintln(statusOutput, "📘 Kubernetes AI Explainer")
+	fmt.Fprintf(statusOutput, "📦 Resource: %s\n", resource)
+	fmt.Fprintf(statusOutput, "📍 Namespace: %s\n", explainNamespace)
+	fmt.Fprintln(statusOutput)
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/helmcode/kubectl-ai/pkg/analyzer"
+	"github.com/spf13/cobra"
+)
+
+// followUpChat reads questions from stdin and answers them in the context of the analysis
+// until the user types exit, sends EOF (Ctrl-D) or interrupts (Ctrl-C)
+func followUpChat(cmd *cobra.Command, conversation *analyzer.Conversation) error {
+	fmt.Println()
+	color.New(color.FgCyan, color.Bold).Println("💬 Ask follow-up questions (type 'exit' or press Ctrl-D to quit)")
+
+	// Read stdin in the background so Ctrl-C is noticed while waiting for input
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	s := newSpinner()
+	for {
+		fmt.Print(color.CyanString("> "))
+
+		var line string
+		var ok bool
+		select {
+		case <-cmd.Context().Done():
+			fmt.Println()
+			return nil
+		case line, ok = <-lines:
+		}
+		if !ok {
+			fmt.Println()
+			return nil
+		}
+
+		question := strings.TrimSpace(line)
+		if question == "" {
+			continue
+		}
+		if question == "exit" || question == "quit" {
+			return nil
+		}
+
+		// Each question gets its own --timeout so a long session isn't cut short
+		ctx, cancel := commandContext(cmd)
+		s.Suffix = " Thinking..."
+		s.Start()
+		answer, err := withContext(ctx, func() (string, error) {
+			return conversation.Ask(question)
+		})
+		s.Stop()
+		cancel()
+		if err != nil {
+			if cmd.Context().Err() != nil {
+				return nil
+			}
+			printError(fmt.Sprintf("Follow-up failed: %v", err))
+			continue
+		}
+
+		fmt.Println()
+		fmt.Println(answer)
+		fmt.Println()
+	}
+}
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/helmcode/kubectl-ai/pkg/analyzer"
+	"github.com/helmcode/kubectl-ai/pkg/formatter"
+	"github.com/helmcode/kubectl-ai/pkg/k8s"
+	"github.com/helmcode/kubectl-ai/pkg/llm"
+	"github.com/helmcode/kubectl-ai/pkg/model"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/homedir"
+)
+
+var (
+	// Common flags (similar to debug command)
+	logsKubeconfig    string
+	logsNamespace     string
+	logsKubeContext   string
+	logsOutputFormat  string
+	logsLLMProvider   string
+	logsLLMModel      string
+	logsMaxRetries    int
+	logsTemperature   float64
+	logsMaxTokens     int
+	logsNoRedact      bool
+	logsRedactPattern string
+
+	// Logs-specific flags
+	logsContainer string
+	logsPrevious  bool
+	logsTail      int64
+	logsSince     time.Duration
+)
+
+func NewLogsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs POD [flags]",
+		Short: "Analyze pod logs with AI assistance",
+		Long: `Fetch the logs of a pod and use AI to explain errors, crashes and restarts.
+
+Examples:
+  # Analyze the logs of a pod
+  kubectl ai logs nginx-7d9c8b7f6-abcde -n production
+
+  # Analyze the previous container instance of a crashlooping pod
+  kubectl ai logs api-5f6d4c8b9-xyz12 --previous
+
+  # Analyze a specific container in a multi-container pod
+  kubectl ai logs worker-0 -c sidecar --tail 500
+
+  # Only look at the last 30 minutes of logs
+  kubectl ai logs api-5f6d4c8b9-xyz12 --since 30m`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completePods,
+		RunE:              runLogs,
+	}
+
+	// Common flags (similar to debug command)
+	if home := homedir.HomeDir(); home != "" {
+		cmd.Flags().StringVar(&logsKubeconfig, "kubeconfig", "~/.kube/config", "Path to kubeconfig file")
+	}
+
+	cmd.Flags().StringVarP(&logsNamespace, "namespace", "n", "default", "Kubernetes namespace")
+	cmd.Flags().StringVar(&logsKubeContext, "context", "", "Kubeconfig context (overrides current-context)")
+	cmd.Flags().StringVarP(&logsOutputFormat, "output", "o", "human", "Output format (human, json, yaml, markdown)")
+	cmd.Flags().StringVar(&logsLLMProvider, "provider", "", "LLM provider (claude, openai, gemini, ollama, azure, bedrock). Defaults to auto-detect from env")
+	cmd.Flags().StringVar(&logsLLMModel, "model", "", "LLM model to use (overrides default)")
+	cmd.Flags().IntVar(&logsMaxRetries, "max-retries", llm.DefaultMaxRetries, "Maximum retries for transient LLM API errors (429, 5xx, network)")
+	cmd.Flags().Float64Var(&logsTemperature, "temperature", llm.DefaultTemperature, "Sampling temperature for the LLM (0 keeps answers deterministic)")
+	cmd.Flags().IntVar(&logsMaxTokens, "max-tokens", 0, "Maximum tokens in the LLM response; raise it if large analyses are cut off (0 uses the default of 4000, unlimited for Ollama)")
+	cmd.Flags().BoolVar(&logsNoRedact, "no-redact", false, "Send pod annotations and env var values to the LLM unmasked (trusted environments only)")
+	cmd.Flags().StringVar(&logsRedactPattern, "redact-pattern", k8s.DefaultRedactPattern, "Regular expression of keys whose values are masked before reaching the LLM")
+
+	// Logs-specific flags
+	cmd.Flags().StringVarP(&logsContainer, "container", "c", "", "Container name (defaults to the only container in the pod)")
+	cmd.Flags().BoolVarP(&logsPrevious, "previous", "p", false, "Analyze logs of the previous terminated container instance")
+	cmd.Flags().Int64Var(&logsTail, "tail", 200, "Number of most recent log lines to analyze (-1 for all)")
+	cmd.Flags().DurationVar(&logsSince, "since", 0, "Only analyze logs newer than a relative duration like 5s, 2m or 3h")
+
+	registerCompletions(cmd, nil)
+
+	return cmd
+}
+
+func runLogs(cmd *cobra.Command, args []string) error {
+	podName := args[0]
+	configureOutput(cmd, logsOutputFormat)
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	generation := llm.GenerationOptions{Temperature: logsTemperature, MaxTokens: logsMaxTokens}
+	if err := generation.Validate(); err != nil {
+		return fmt.Errorf("invalid LLM options: %w", err)
+	}
+
+	printLogsHeader(podName)
+
+	// Create spinner for visual feedback
+	s := newSpinner()
+	s.Suffix = " Connecting to Kubernetes cluster..."
+	s.Start()
+
+	// Expand home symbol in kubeconfig if needed
+	if strings.HasPrefix(logsKubeconfig, "~/") {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			logsKubeconfig = filepath.Join(homeDir, logsKubeconfig[2:])
+		}
+	}
+
+	// Initialize K8s client
+	k8sClient, err := k8s.NewClient(logsKubeconfig, logsKubeContext)
+	if err != nil {
+		s.Stop()
+		return fmt.Errorf("failed to connect to cluster: %w", err)
+	}
+	k8sClient.SetRefreshDiscoveryCache(refreshCache(cmd))
+	s.Stop()
+	printSuccess("Connected to Kubernetes cluster")
+
+	if err := configureRedaction(k8sClient, logsNoRedact, logsRedactPattern); err != nil {
+		return err
+	}
+
+	s.Suffix = " Fetching pod logs..."
+	s.Start()
+
+	pod, err := k8sClient.GetClientset().CoreV1().Pods(logsNamespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		s.Stop()
+		return fmt.Errorf("failed to get pod %s: %w", podName, err)
+	}
+	k8sClient.Redact(pod)
+
+	logOptions := &corev1.PodLogOptions{
+		Container: logsContainer,
+		Previous:  logsPrevious,
+	}
+	if logsTail >= 0 {
+		logOptions.TailLines = &logsTail
+	}
+	if logsSince > 0 {
+		sinceSeconds := int64(logsSince.Seconds())
+		logOptions.SinceSeconds = &sinceSeconds
+	}
+
+	logs, err := k8sClient.GetPodLogs(ctx, logsNamespace, podName, logOptions)
+	if err != nil {
+		s.Stop()
+		return err
+	}
+
+	s.Stop()
+	if strings.TrimSpace(logs) == "" {
+		return fmt.Errorf("no logs found for pod %s", podName)
+	}
+	printSuccess(fmt.Sprintf("Fetched %d log lines", strings.Count(logs, "\n")))
+
+	s.Suffix = " Initializing AI client..."
+	s.Start()
+
+	// Initialize LLM client using factory
+	llmClient, err := createLLM(cmd, logsLLMProvider, logsLLMModel)
+	if err != nil {
+		s.Stop()
+		return fmt.Errorf("failed to initialize LLM client: %w", err)
+	}
+	llmClient = responseCache(cmd, llmClient)
+	llm.SetMaxRetries(llmClient, logsMaxRetries)
+	if err := llm.SetGenerationOptions(llmClient, generation); err != nil {
+		return fmt.Errorf("invalid LLM options: %w", err)
+	}
+
+	s.Stop()
+	printSuccess("AI client initialized")
+
+	// Show LLM provider and model info
+	printLLMInfo(llmClient)
+	fmt.Fprintln(statusOutput)
+
+	s.Suffix = " Analyzing logs with AI..."
+	s.Start()
+
+	aiAnalyzer := analyzer.NewWithLLM(llmClient)
+	analysis, err := withContext(ctx, func() (*model.Analysis, error) {
+		return aiAnalyzer.AnalyzeLogs(podName, pod, logs)
+	})
+	if err != nil {
+		s.Stop()
+		return fmt.Errorf("AI analysis failed: %w", err)
+	}
+
+	s.Stop()
+	printSuccess("Analysis complete")
+
+	formatter.DisplayResults(analysis, logsOutputFormat)
+
+	return nil
+}
+
+func printLogsHeader(podName string) {
+	cyan := color.New(color.FgCyan, color.Bold)
+	fmt.Fprintln(statusOutput)
+	cyan.Fprintln(statusOutput, "📜 Kubernetes AI Log Analyzer")
+	fmt.Fprintf(statusOutput, "📦 Pod: %s\n", podName)
+	fmt.Fprintf(statusOutput, "📍 Namespace: %s\n", logsNamespace)
+	if logsContainer != "" {
+		fmt.Fprintf(statusOutput, "🧱 Container: %s\n", logsContainer)
+	}
+	if logsPrevious {
+		fmt.Fprintln(statusOutput, "⏮️  Logs: previous container instance")
+	}
+	fmt.Fprintln(statusOutput)
+}
@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -14,29 +17,78 @@ import (
 	"github.com/helmcode/kubectl-ai/pkg/k8s"
 	"github.com/helmcode/kubectl-ai/pkg/llm"
 	"github.com/helmcode/kubectl-ai/pkg/metrics"
+	"github.com/helmcode/kubectl-ai/pkg/notify"
 	"github.com/spf13/cobra"
 	"k8s.io/client-go/util/homedir"
+	"sigs.k8s.io/yaml"
 )
 
 var (
 	// Common flags (similar to debug command)
-	metricsKubeconfig   string
-	metricsNamespace    string
-	metricsKubeContext  string
-	metricsResources    []string
-	metricsAllResources bool
-	metricsOutputFormat string
-	metricsVerbose      bool
-	metricsLLMProvider  string
-	metricsLLMModel     string
+	metricsKubeconfig    string
+	metricsNamespace     string
+	metricsKubeContext   string
+	metricsResources     []string
+	metricsAllResources  bool
+	metricsSelector      string
+	metricsAllNamespaces bool
+	metricsOutputFormat  string
+	metricsVerbose       bool
+	metricsLLMProvider   string
+	metricsLLMModel      string
+	metricsMaxRetries    int
+	metricsTemperature   float64
+	metricsMaxTokens     int
+	metricsConcurrency   int
+	metricsNoRedact      bool
+	metricsRedactPattern string
+	metricsDryRun        bool
 
 	// Metrics-specific flags
-	analyzeScaling      bool
-	duration            string
-	hpaAnalysis         bool
-	kedaAnalysis        bool
-	prometheusURL       string
-	prometheusNamespace string
+	analyzeScaling         bool
+	duration               string
+	metricsStart           string
+	metricsEnd             string
+	metricsStartTime       time.Time // Parsed --start, zero without a fixed window
+	metricsEndTime         time.Time
+	queryStep              time.Duration
+	queriesFile            string
+	showQueries            bool
+	hpaAnalysis            bool
+	kedaAnalysis           bool
+	costPerPodHour         float64
+	mesh                   string
+	byContainer            bool
+	allowScaleToZero       bool
+	prometheusURL          string
+	prometheusNamespace    string
+	prometheusServiceNames []string
+	prometheusQueryTimeout time.Duration
+	metricsSource          string
+	metricsNoPrometheus    bool
+
+	// Watch mode flags
+	metricsWatch         bool
+	metricsWatchInterval time.Duration
+
+	// Compare mode flags
+	metricsCompareContext       string
+	metricsComparePrometheusURL string
+
+	// Snapshot flags
+	metricsSaveSnapshot    string
+	metricsCompareSnapshot string
+
+	// Webhook flags
+	metricsWebhookURL     string
+	metricsWebhookHeaders []string
+
+	// Prometheus TLS and authentication flags
+	prometheusToken              string
+	prometheusCACert             string
+	prometheusInsecureSkipVerify bool
+	prometheusUsername           string
+	prometheusPassword           string
 )
 
 func NewMetricsCmd() *cobra.Command {
@@ -48,6 +100,8 @@ func NewMetricsCmd() *cobra.Command {
 By default, this command shows:
 - CPU usage line chart with statistics (average, min, max)
 - Memory usage line chart with statistics (average, min, max)  
+- Network receive/transmit throughput charts
+- Filesystem/PVC usage and disk read/write charts
 - Replica scaling events bar chart
 
 With the --analyze flag, it additionally provides:
@@ -65,16 +119,63 @@ Examples:
   # Analyze with specific duration
   kubectl ai metrics deploy/api --duration 7d --analyze
 
+  # Look back at a past incident window instead of the latest data
+  kubectl ai metrics deploy/api --start 2026-10-14T14:00:00Z --end 2026-10-14T15:00:00Z --analyze
+
+  # Use a finer query resolution for more chart points
+  kubectl ai metrics deploy/api --duration 30d --step 30m
+
+  # Chart and analyze app-specific metrics from a queries file
+  kubectl ai metrics deploy/worker --queries-file queries.yaml --analyze
+
+  # Analyze a StatefulSet
+  kubectl ai metrics statefulset/postgres -n databases --analyze
+
+  # Analyze a DaemonSet (desired vs ready vs available pods, no HPA/KEDA)
+  kubectl ai metrics ds/fluentd -n logging --analyze
+
+  # Analyze a CronJob (running, succeeded and failed pods, no HPA/KEDA)
+  kubectl ai metrics cronjob/nightly-backup --duration 7d --analyze
+
+  # Analyze a single misbehaving pod (CPU and memory of exactly that pod, no replicas or HPA/KEDA)
+  kubectl ai metrics pod/api-7d9f8b6c5-x2k4q --analyze
+
+  # Save this week's metrics and compare against them after a config change
+  kubectl ai metrics deploy/api --duration 7d --save api-week1.json
+  kubectl ai metrics deploy/api --duration 7d --compare api-week1.json
+
+  # Compare a deployment's metrics between staging and prod side by side
+  kubectl ai metrics deploy/api --context staging --compare-context prod --analyze
+
+  # POST the results as JSON to an automation endpoint
+  kubectl ai metrics deploy/api --analyze --webhook-url https://bot.example.com/hook --webhook-header "Authorization: Bearer $TOKEN"
+
+  # Refresh the charts every 30s during a load test
+  kubectl ai metrics deploy/api --watch --interval 30s --duration 1h
+
   # Analyze all deployments in a namespace
-  kubectl ai metrics --all-deployments -n production --analyze
+  kubectl ai metrics --all -n production --analyze
+
+  # Compare every deployment in a namespace at a glance
+  kubectl ai metrics --all -n production -o table
+
+  # Analyze every deployment, job and cronjob labeled app=api
+  kubectl ai metrics -l app=api -n production --analyze
 
   # Get HPA and KEDA recommendations
   kubectl ai metrics deployment/worker --hpa-analysis --keda-analysis
 
   # Use specific Prometheus URL
-  kubectl ai metrics deployment/app --prometheus-url http://prometheus.monitoring:9090`,
-		Args: cobra.MaximumNArgs(1),
-		RunE: runMetrics,
+  kubectl ai metrics deployment/app --prometheus-url http://prometheus.monitoring:9090
+
+  # Use a Prometheus behind TLS with a bearer token
+  kubectl ai metrics deployment/app --prometheus-url https://prometheus.example.com --prometheus-token $TOKEN --prometheus-ca-cert ca.pem
+
+  # Analyze the spec, autoscalers and events on a cluster without Prometheus
+  kubectl ai metrics deployment/app --analyze --no-prometheus`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: singleArgCompletion(resourceCompletion(workloadResourceTypes)),
+		RunE:              runMetrics,
 	}
 
 	// Common flags (similar to debug command)
@@ -83,26 +184,69 @@ Examples:
 	}
 
 	cmd.Flags().StringVarP(&metricsNamespace, "namespace", "n", "default", "Kubernetes namespace")
+	cmd.Flags().BoolVarP(&metricsAllNamespaces, "all-namespaces", "A", false, "Analyze resources across all namespaces (use namespace/type/name with -r)")
 	cmd.Flags().StringVar(&metricsKubeContext, "context", "", "Kubeconfig context (overrides current-context)")
-	cmd.Flags().StringSliceVarP(&metricsResources, "resource", "r", []string{}, "Resources to analyze (e.g., deployment/nginx)")
+	cmd.Flags().StringSliceVarP(&metricsResources, "resource", "r", []string{}, "Resources to analyze (e.g., deployment/nginx, statefulset/postgres, daemonset/fluentd)")
 	cmd.Flags().BoolVar(&metricsAllResources, "all", false, "Analyze all deployments in the namespace")
-	cmd.Flags().StringVarP(&metricsOutputFormat, "output", "o", "human", "Output format (human, json, yaml)")
-	cmd.Flags().BoolVarP(&metricsVerbose, "verbose", "v", false, "Verbose output")
-	cmd.Flags().StringVar(&metricsLLMProvider, "provider", "", "LLM provider (claude, openai). Defaults to auto-detect from env")
+	cmd.Flags().StringVarP(&metricsSelector, "selector", "l", "", "Label selector to analyze matching deployments, jobs and cronjobs instead of naming them (e.g. app=api)")
+	cmd.Flags().StringVarP(&metricsOutputFormat, "output", "o", "human", "Output format (human, json, yaml, markdown, table)")
+	cmd.Flags().BoolVarP(&metricsVerbose, "verbose", "v", false, "Trace every Kubernetes API call, Prometheus query and LLM request to stderr")
+	cmd.Flags().StringVar(&metricsLLMProvider, "provider", "", "LLM provider (claude, openai, gemini, ollama, azure, bedrock). Defaults to auto-detect from env")
 	cmd.Flags().StringVar(&metricsLLMModel, "model", "", "LLM model to use (overrides default)")
+	cmd.Flags().IntVar(&metricsConcurrency, "concurrency", k8s.DefaultConcurrency, "Maximum number of concurrent Kubernetes and Prometheus requests")
+	cmd.Flags().IntVar(&metricsMaxRetries, "max-retries", llm.DefaultMaxRetries, "Maximum retries for transient LLM API errors (429, 5xx, network)")
+	cmd.Flags().Float64Var(&metricsTemperature, "temperature", llm.DefaultTemperature, "Sampling temperature for the LLM (0 keeps answers deterministic)")
+	cmd.Flags().IntVar(&metricsMaxTokens, "max-tokens", 0, "Maximum tokens in the LLM response; raise it if large analyses are cut off (0 uses the default of 4000, unlimited for Ollama)")
+	cmd.Flags().BoolVar(&metricsNoRedact, "no-redact", false, "Send ConfigMap values, annotations and env var values to the LLM unmasked (trusted environments only)")
+	cmd.Flags().StringVar(&metricsRedactPattern, "redact-pattern", k8s.DefaultRedactPattern, "Regular expression of keys whose values are masked before reaching the LLM")
+	cmd.Flags().BoolVar(&metricsDryRun, "dry-run", false, "Print the AI analysis prompts and their estimated token counts instead of calling the LLM")
 
 	// Metrics-specific flags
 	cmd.Flags().BoolVar(&analyzeScaling, "analyze", false, "Perform scaling analysis based on metrics")
-	cmd.Flags().StringVar(&duration, "duration", "24h", "Duration for metrics analysis (1h, 6h, 24h, 7d, 30d)")
+	cmd.Flags().StringVar(&duration, "duration", "24h", "Duration for metrics analysis (e.g. 30m, 24h, 7d, 2w or 7d12h)")
+	cmd.Flags().StringVar(&metricsStart, "start", "", "Start of a fixed window to analyze, as an RFC3339 timestamp (overrides --duration, requires Prometheus)")
+	cmd.Flags().StringVar(&metricsEnd, "end", "", "End of the fixed window started with --start, as an RFC3339 timestamp (default now)")
+	cmd.Flags().DurationVar(&queryStep, "step", 0, "Prometheus query resolution, e.g. 5m (auto-selected from --duration if not set)")
+	cmd.Flags().StringVar(&queriesFile, "queries-file", "", "YAML file with custom Prometheus queries to merge with (or replace) the standard set")
+	cmd.Flags().BoolVar(&showQueries, "show-queries", false, "Print each executed PromQL query and how many data points it returned")
+	cmd.Flags().BoolVar(&metricsWatch, "watch", false, "Keep refreshing the charts until interrupted (AI analysis only runs on the first frame)")
+	cmd.Flags().DurationVar(&metricsWatchInterval, "interval", 30*time.Second, "Refresh interval for --watch")
+	cmd.Flags().StringVar(&metricsCompareContext, "compare-context", "", "Second kubeconfig context to compare the resource against, side by side")
+	cmd.Flags().StringVar(&metricsSaveSnapshot, "save", "", "Save the analysis result to a JSON snapshot file for later --compare runs")
+	cmd.Flags().StringVar(&metricsCompareSnapshot, "compare", "", "Show CPU, memory and replica deltas against a snapshot saved with --save")
+	cmd.Flags().StringVar(&metricsComparePrometheusURL, "compare-prometheus-url", "", "Prometheus URL for --compare-context (auto-detects if not provided, same auth flags apply)")
+	cmd.Flags().StringVar(&metricsWebhookURL, "webhook-url", "", "URL to POST the analysis results to as JSON (the same document as -o json)")
+	cmd.Flags().StringArrayVar(&metricsWebhookHeaders, "webhook-header", nil, "Header to send with --webhook-url, e.g. \"Authorization: Bearer $TOKEN\" (repeatable)")
 	cmd.Flags().BoolVar(&hpaAnalysis, "hpa-analysis", false, "Perform HPA-specific analysis")
 	cmd.Flags().BoolVar(&kedaAnalysis, "keda-analysis", false, "Perform KEDA-specific analysis")
+	cmd.Flags().StringVar(&mesh, "mesh", "", "Service mesh to read request and 5xx error rates from (istio, linkerd)")
+	cmd.Flags().BoolVar(&byContainer, "by-container", false, "Also chart CPU and memory per container, to tell the app from its sidecars (requires Prometheus)")
+	cmd.Flags().BoolVar(&allowScaleToZero, "allow-scale-to-zero", false, "Let KEDA recommendations scale to zero even if the workload was never idle (adds cold starts)")
+	cmd.Flags().Float64Var(&costPerPodHour, "cost-per-pod-hour", 0, "Cost of one pod per hour; adds an estimated monthly cost range to HPA/KEDA recommendations")
 	cmd.Flags().StringVar(&prometheusURL, "prometheus-url", "", "Prometheus server URL (auto-detects if not provided)")
+	cmd.Flags().StringVar(&metricsSource, "metrics-source", metrics.SourceAuto, "Where to read metrics from (prometheus, metrics-server, auto). auto falls back to metrics-server when Prometheus isn't found")
+	cmd.Flags().BoolVar(&metricsNoPrometheus, "no-prometheus", false, "Don't look for Prometheus: use metrics-server if available, otherwise analyze specs and events without metrics")
 	cmd.Flags().StringVar(&prometheusNamespace, "prometheus-namespace", "", "Prometheus namespace for auto-detection")
+	cmd.Flags().DurationVar(&prometheusQueryTimeout, "prometheus-query-timeout", metrics.DefaultQueryTimeout, "Timeout for each Prometheus query; a query that exceeds it is skipped with a warning")
+	cmd.Flags().StringSliceVar(&prometheusServiceNames, "prometheus-service-name", nil, "Service names to try during auto-detection, replacing the built-in Prometheus, VictoriaMetrics and Thanos names")
+	cmd.Flags().StringVar(&prometheusToken, "prometheus-token", "", "Bearer token for Prometheus (env: PROMETHEUS_TOKEN)")
+	cmd.Flags().StringVar(&prometheusCACert, "prometheus-ca-cert", "", "Path to a CA certificate used to verify Prometheus TLS (env: PROMETHEUS_CA_CERT)")
+	cmd.Flags().StringVar(&prometheusUsername, "prometheus-username", "", "Basic auth username for Prometheus (env: PROMETHEUS_USERNAME)")
+	cmd.Flags().StringVar(&prometheusPassword, "prometheus-password", "", "Basic auth password for Prometheus (env: PROMETHEUS_PASSWORD)")
+	cmd.Flags().BoolVar(&prometheusInsecureSkipVerify, "prometheus-insecure-skip-verify", false, "Skip Prometheus TLS certificate verification (env: PROMETHEUS_INSECURE_SKIP_VERIFY)")
+
+	registerCompletions(cmd, workloadResourceTypes)
 
 	return cmd
 }
 
 func runMetrics(cmd *cobra.Command, args []string) error {
+	configureOutput(cmd, metricsOutputFormat)
+	configureVerbose(metricsVerbose)
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
 	// Parse resource if provided
 	var targetResource string
 	if len(args) > 0 {
@@ -111,17 +255,116 @@ func runMetrics(cmd *cobra.Command, args []string) error {
 	}
 
 	// Validate inputs
-	if !metricsAllResources && len(metricsResources) == 0 {
-		return fmt.Errorf("either specify a resource, use -r flag, or use --all flag")
+	if !metricsAllResources && metricsSelector == "" && len(metricsResources) == 0 {
+		return fmt.Errorf("either specify a resource, use -r flag, -l flag, or --all flag")
+	}
+	if metricsSelector != "" && len(metricsResources) > 0 {
+		return fmt.Errorf("-l selects resources by label and can't be combined with a resource or -r")
+	}
+	if err := validateSelector(metricsSelector); err != nil {
+		return err
 	}
 
-	// Show what we're doing
-	printMetricsHeader(targetResource)
+	if metricsWatch {
+		if metricsDryRun {
+			return fmt.Errorf("--watch and --dry-run cannot be used together")
+		}
+		if metricsOutputFormat != "human" {
+			return fmt.Errorf("--watch only supports human output")
+		}
+		if metricsWatchInterval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+	}
 
-	// Create spinner for visual feedback
-	s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	s.Suffix = " Connecting to Kubernetes cluster..."
-	s.Start()
+	if metricsCompareContext != "" {
+		if metricsWatch || metricsDryRun {
+			return fmt.Errorf("--compare-context cannot be used with --watch or --dry-run")
+		}
+		if metricsOutputFormat != "human" {
+			return fmt.Errorf("--compare-context only supports human output")
+		}
+		if multiResourceRun() {
+			return fmt.Errorf("--compare-context compares a single resource, --all and -l are not supported")
+		}
+	}
+
+	if (metricsSaveSnapshot != "" || metricsCompareSnapshot != "") && (metricsWatch || metricsDryRun || metricsCompareContext != "") {
+		return fmt.Errorf("--save and --compare cannot be used with --watch, --dry-run or --compare-context")
+	}
+	if metricsCompareSnapshot != "" && metricsOutputFormat != "human" {
+		return fmt.Errorf("--compare only supports human output")
+	}
+	if (metricsSaveSnapshot != "" || metricsCompareSnapshot != "") && multiResourceRun() {
+		return fmt.Errorf("--save and --compare work on a single resource, --all and -l are not supported")
+	}
+
+	var err error
+	metricsStartTime, metricsEndTime, err = metricsTimeRange(metricsStart, metricsEnd)
+	if err != nil {
+		return err
+	}
+	if !metricsStartTime.IsZero() {
+		if metricsWatch {
+			return fmt.Errorf("--start and --end fix the window and cannot be used with --watch")
+		}
+		// Catch a window too large for the step before connecting to anything
+		if err := metrics.ValidateStep(queryStep, metricsStartTime, metricsEndTime); err != nil {
+			return fmt.Errorf("invalid --start/--end: %w", err)
+		}
+		// The window's length stands in for --duration in charts, prompts and snapshots
+		duration = metrics.FormatLookback(metricsEndTime.Sub(metricsStartTime))
+	}
+
+	if metricsWebhookURL != "" && (metricsWatch || metricsDryRun || metricsCompareContext != "") {
+		return fmt.Errorf("--webhook-url cannot be used with --watch, --dry-run or --compare-context")
+	}
+	webhookHeader, err := webhookOptions(metricsWebhookURL, metricsWebhookHeaders)
+	if err != nil {
+		return err
+	}
+
+	generation := llm.GenerationOptions{Temperature: metricsTemperature, MaxTokens: metricsMaxTokens}
+	if err := generation.Validate(); err != nil {
+		return fmt.Errorf("invalid LLM options: %w", err)
+	}
+
+	if costPerPodHour < 0 {
+		return fmt.Errorf("--cost-per-pod-hour can't be negative")
+	}
+
+	switch metricsSource {
+	case metrics.SourceAuto, metrics.SourcePrometheus, metrics.SourceMetricsServer:
+	default:
+		return fmt.Errorf("unsupported --metrics-source %q (supported: prometheus, metrics-server, auto)", metricsSource)
+	}
+	if metricsNoPrometheus && (metricsSource == metrics.SourcePrometheus || prometheusURL != "") {
+		return fmt.Errorf("--no-prometheus cannot be used with --metrics-source prometheus or --prometheus-url")
+	}
+
+	// Load the snapshot early so a bad file fails before any cluster work
+	var snapshot *metrics.AnalysisResult
+	if metricsCompareSnapshot != "" {
+		var err error
+		snapshot, err = metrics.LoadSnapshot(metricsCompareSnapshot)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Load custom queries early so a bad file fails before any cluster work
+	var customQueries []metrics.PrometheusQuery
+	if queriesFile != "" {
+		var err error
+		customQueries, err = metrics.LoadQueriesFile(queriesFile)
+		if err != nil {
+			return err
+		}
+	}
+	customQueries, err = metrics.WithMeshQueries(customQueries, mesh)
+	if err != nil {
+		return fmt.Errorf("invalid --mesh: %w", err)
+	}
 
 	// Expand home symbol in kubeconfig if needed
 	if strings.HasPrefix(metricsKubeconfig, "~/") {
@@ -130,43 +373,348 @@ func runMetrics(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Initialize K8s client
+	// Resolve the kube context before the header so it shows which cluster is analyzed
 	k8sClient, err := k8s.NewClient(metricsKubeconfig, metricsKubeContext)
 	if err != nil {
-		s.Stop()
 		return fmt.Errorf("failed to connect to cluster: %w", err)
 	}
-	s.Stop()
-	printSuccess("Connected to Kubernetes cluster")
+
+	// Show what we're doing
+	printMetricsHeader(targetResource, k8sClient)
+
+	// Create spinner for visual feedback
+	s := newSpinner()
+
+	// Fail fast on typos before spending time on Prometheus and the LLM
+	if !metricsAllResources {
+		s.Suffix = " Checking resources..."
+		s.Start()
+		for _, resource := range metricsResources {
+			if err := k8sClient.CheckResource(ctx, targetNamespace(metricsNamespace, metricsAllNamespaces), resource); err != nil {
+				s.Stop()
+				return err
+			}
+		}
+		s.Stop()
+	}
 
 	// Initialize Prometheus client with auto-detection (no spinner - we show detailed progress)
-	prometheusClient, err := metrics.NewPrometheusClient(prometheusURL, prometheusNamespace, metricsKubeconfig, k8sClient)
+	prometheusAuth := metrics.AuthConfig{
+		BearerToken:        prometheusToken,
+		CACertFile:         prometheusCACert,
+		InsecureSkipVerify: prometheusInsecureSkipVerify,
+		Username:           prometheusUsername,
+		Password:           prometheusPassword,
+	}.WithEnvDefaults()
+
+	source, prometheusClient, err := connectMetricsSource(ctx, k8sClient, prometheusAuth)
 	if err != nil {
-		return fmt.Errorf("failed to connect to Prometheus: %w", err)
+		return err
 	}
 
 	// Ensure cleanup of port-forward when function exits
-	defer prometheusClient.Close()
+	defer source.Close()
+
+	if prometheusClient == nil && metricsCompareContext != "" {
+		return fmt.Errorf("--compare-context requires Prometheus")
+	}
+	if prometheusClient == nil && !metricsStartTime.IsZero() {
+		return fmt.Errorf("--start and --end require Prometheus, metrics-server has no history")
+	}
+	if prometheusClient == nil && mesh != "" {
+		fmt.Fprintf(statusOutput, "⚠️  --mesh needs Prometheus, skipping %s request and error rates\n", mesh)
+	}
+	if prometheusClient == nil && byContainer {
+		fmt.Fprintln(statusOutput, "⚠️  --by-container needs Prometheus, skipping the per-container breakdown")
+	}
+
+	k8sClient.SetConcurrency(metricsConcurrency)
+	k8sClient.SetRefreshDiscoveryCache(refreshCache(cmd))
+	if err := configureRedaction(k8sClient, metricsNoRedact, metricsRedactPattern); err != nil {
+		return err
+	}
+	if prometheusClient != nil {
+		prometheusClient.SetConcurrency(metricsConcurrency)
+		prometheusClient.SetStep(queryStep)
+		prometheusClient.SetQueryTimeout(prometheusQueryTimeout)
+		prometheusClient.SetQueries(customQueries)
+		prometheusClient.SetShowQueries(showQueries)
+		prometheusClient.SetByContainer(byContainer)
+		if !metricsStartTime.IsZero() {
+			prometheusClient.SetTimeRange(metricsStartTime, metricsEndTime)
+		}
+	}
 
+	// Initialize LLM client using factory
+	s.Suffix = " Initializing AI client..."
+	s.Start()
+
+	llmClient, err := createLLM(cmd, metricsLLMProvider, metricsLLMModel)
+	if err != nil && !metricsDryRun {
+		s.Stop()
+		return fmt.Errorf("failed to initialize LLM client: %w", err)
+	}
+	s.Stop()
+
+	if llmClient != nil {
+		llmClient = responseCache(cmd, llmClient)
+		llm.SetMaxRetries(llmClient, metricsMaxRetries)
+		if err := llm.SetGenerationOptions(llmClient, generation); err != nil {
+			return fmt.Errorf("invalid LLM options: %w", err)
+		}
+		printSuccess("AI client initialized")
+
+		// Show LLM provider and model info
+		printLLMInfo(llmClient)
+		fmt.Fprintln(statusOutput)
+	}
+
+	metricsAnalyzer := metrics.NewAnalyzer(llmClient, prometheusClient, k8sClient)
+	metricsAnalyzer.SetCostPerPodHour(costPerPodHour)
+	metricsAnalyzer.SetMesh(mesh)
+	metricsAnalyzer.SetAllowScaleToZero(allowScaleToZero)
+
+	if metricsDryRun {
+		return dryRunMetrics(ctx, s, k8sClient, source, metricsAnalyzer)
+	}
+
+	if metricsWatch {
+		return watchMetrics(cmd, k8sClient, source, metricsAnalyzer)
+	}
+
+	if metricsCompareContext != "" {
+		return compareMetrics(ctx, cmd, s, k8sClient, prometheusClient, metricsAnalyzer, prometheusAuth, customQueries)
+	}
+
+	analyses, err := collectAndAnalyzeMetrics(ctx, s, k8sClient, source, metricsAnalyzer, true)
+	if err != nil {
+		return err
+	}
+	if len(analyses) == 0 {
+		return fmt.Errorf("no deployments, jobs or cronjobs found to analyze (--all and -l don't gather statefulsets or daemonsets, name them with -r)")
+	}
+
+	if metricsSaveSnapshot != "" {
+		if err := metrics.SaveSnapshot(metricsSaveSnapshot, onlyResult(analyses)); err != nil {
+			return err
+		}
+		printSuccess(fmt.Sprintf("Saved snapshot to %s", metricsSaveSnapshot))
+	}
+
+	// Display results
+	if err := displayMetricsResults(analyses, metricsOutputFormat); err != nil {
+		return err
+	}
+
+	if snapshot != nil {
+		displaySnapshotComparison(snapshot, onlyResult(analyses))
+	}
+
+	if metricsWebhookURL != "" {
+		_, ordered := orderedResults(analyses)
+		if err := notify.PostJSON(ctx, metricsWebhookURL, webhookHeader, structuredResults(ordered)); err != nil {
+			return fmt.Errorf("failed to post results to webhook: %w", err)
+		}
+		printSuccess("Results posted to webhook")
+	}
+	return nil
+}
+
+// displaySnapshotComparison prints how CPU, memory and replicas changed since a saved snapshot
+func displaySnapshotComparison(snapshot, analysis *metrics.AnalysisResult) {
+	if snapshot.ResourceName != analysis.ResourceName || snapshot.Namespace != analysis.Namespace {
+		fmt.Printf("⚠️  Snapshot is for %s/%s, comparing with %s/%s anyway\n", snapshot.Namespace, snapshot.ResourceName, analysis.Namespace, analysis.ResourceName)
+	}
+
+	rows := []formatter.DeltaRow{}
+	addRow := func(name, metric, unit string, value func(metrics.MetricSummary) float64) {
+		before, beforeOK := snapshot.MetricsSummary[metric]
+		after, afterOK := analysis.MetricsSummary[metric]
+		if !beforeOK || !afterOK {
+			return
+		}
+		rows = append(rows, formatter.DeltaRow{Name: name, Unit: unit, Before: value(before), After: value(after)})
+	}
+	average := func(m metrics.MetricSummary) float64 { return m.Average }
+	peak := func(m metrics.MetricSummary) float64 { return m.Peak }
+
+	addRow("CPU avg", "cpu_utilization", "%", average)
+	addRow("CPU peak", "cpu_utilization", "%", peak)
+	addRow("Memory avg", "memory_utilization", "MB", average)
+	addRow("Memory peak", "memory_utilization", "MB", peak)
+	addRow("Replicas avg", "pod_replicas", "", average)
+	addRow("Replicas peak", "pod_replicas", "", peak)
+
+	if len(rows) == 0 {
+		fmt.Println("⚠️  Snapshot has no metrics in common with this run")
+		return
+	}
+
+	before := snapshot.GeneratedAt.Local().Format("2006-01-02 15:04")
+	title := fmt.Sprintf("CHANGE SINCE SNAPSHOT (%s, %s window)", before, snapshot.Duration)
+	fmt.Print(formatter.CreateDeltaTable(title, "Before", "Now", rows))
+}
+
+// metricsTimeRange parses --start and --end. Both are zero when no fixed window was requested; a
+// --start without --end ends now.
+func metricsTimeRange(start, end string) (time.Time, time.Time, error) {
+	if start == "" {
+		if end != "" {
+			return time.Time{}, time.Time{}, fmt.Errorf("--end requires --start")
+		}
+		return time.Time{}, time.Time{}, nil
+	}
+
+	startTime, err := time.Parse(time.RFC3339, start)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --start %q: use an RFC3339 timestamp like 2026-10-14T14:00:00Z", start)
+	}
+	endTime := time.Now()
+	if end != "" {
+		if endTime, err = time.Parse(time.RFC3339, end); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --end %q: use an RFC3339 timestamp like 2026-10-14T15:00:00Z", end)
+		}
+	}
+
+	if !startTime.Before(endTime) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--start %s must be before --end %s", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339))
+	}
+	if endTime.Sub(startTime) < time.Minute {
+		return time.Time{}, time.Time{}, fmt.Errorf("the window from --start to --end must be at least 1m")
+	}
+	return startTime, endTime, nil
+}
+
+// windowLabel describes the analyzed window: the duration, plus its bounds for a fixed --start/--end window
+func windowLabel(duration string, start, end *time.Time) string {
+	if start == nil || end == nil {
+		return duration
+	}
+	return fmt.Sprintf("%s (%s to %s)", duration, start.UTC().Format(time.RFC3339), end.UTC().Format(time.RFC3339))
+}
+
+// connectMetricsSource connects to Prometheus or metrics-server according to --metrics-source.
+// The returned PrometheusClient is nil when metrics come from metrics-server or --no-prometheus.
+func connectMetricsSource(ctx context.Context, k8sClient *k8s.Client, prometheusAuth metrics.AuthConfig) (metrics.Source, *metrics.PrometheusClient, error) {
+	// Never touch Prometheus, and still run when metrics-server is missing too
+	if metricsNoPrometheus {
+		metricsServerClient, err := metrics.NewMetricsServerClient(ctx, k8sClient)
+		if err == nil {
+			printSuccess("Using metrics-server (current values only, no history)")
+			return metricsServerClient, nil, nil
+		}
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+		fmt.Fprintln(statusOutput, "⚠️  metrics-server not available, analyzing specs, autoscalers and events without metrics")
+		return metrics.NoMetricsSource{}, nil, nil
+	}
+
+	if metricsSource != metrics.SourceMetricsServer {
+		prometheusClient, err := metrics.NewPrometheusClient(ctx, prometheusURL, prometheusNamespace, prometheusServiceNames, k8sClient, prometheusAuth)
+		if err == nil {
+			return prometheusClient, prometheusClient, nil
+		}
+		// An explicit --prometheus-url means the user expects Prometheus, so don't fall back
+		if metricsSource == metrics.SourcePrometheus || prometheusURL != "" || ctx.Err() != nil {
+			return nil, nil, fmt.Errorf("failed to connect to Prometheus: %w", err)
+		}
+
+		metricsServerClient, msErr := metrics.NewMetricsServerClient(ctx, k8sClient)
+		if msErr != nil {
+			return nil, nil, fmt.Errorf("failed to connect to Prometheus: %w (metrics-server fallback: %v; use --no-prometheus to analyze without metrics)", err, msErr)
+		}
+		fmt.Fprintln(statusOutput, "⚠️  Prometheus not found, using metrics-server (current values only, no history)")
+		return metricsServerClient, nil, nil
+	}
+
+	metricsServerClient, err := metrics.NewMetricsServerClient(ctx, k8sClient)
+	if err != nil {
+		return nil, nil, err
+	}
+	printSuccess("Using metrics-server (current values only, no history)")
+	return metricsServerClient, nil, nil
+}
+
+// collectAndAnalyzeMetrics gathers the resources, collects their metrics and analyzes each of them.
+// AI analysis only runs when withAI is set so watch mode doesn't call the LLM on every frame.
+func collectAndAnalyzeMetrics(ctx context.Context, s *spinner.Spinner, k8sClient *k8s.Client, source metrics.Source, metricsAnalyzer *metrics.Analyzer, withAI bool) (map[string]*metrics.AnalysisResult, error) {
+	analysisRequest, err := collectMetrics(ctx, s, k8sClient, source, withAI)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Suffix = " Analyzing metrics with AI..."
+	s.Start()
+
+	analyses, err := withContext(ctx, func() (map[string]*metrics.AnalysisResult, error) {
+		return metricsAnalyzer.AnalyzeMetrics(ctx, analysisRequest)
+	})
+	if err != nil {
+		s.Stop()
+		return nil, fmt.Errorf("metrics analysis failed: %w", err)
+	}
+
+	s.Stop()
+	if !metricsWatch {
+		printSuccess("Metrics analysis complete")
+	}
+
+	return analyses, nil
+}
+
+// dryRunMetrics collects metrics like a normal run and prints the AI analysis prompts
+// instead of sending them to the LLM
+func dryRunMetrics(ctx context.Context, s *spinner.Spinner, k8sClient *k8s.Client, source metrics.Source, metricsAnalyzer *metrics.Analyzer) error {
+	analysisRequest, err := collectMetrics(ctx, s, k8sClient, source, true)
+	if err != nil {
+		return err
+	}
+
+	prompts, err := metricsAnalyzer.BuildPrompts(ctx, analysisRequest)
+	if err != nil {
+		return fmt.Errorf("failed to build prompts: %w", err)
+	}
+	fmt.Fprintln(statusOutput)
+	if len(prompts) == 0 {
+		fmt.Fprintln(statusOutput, "⚠️  No AI analysis requested (use --analyze, --hpa-analysis or --keda-analysis), nothing would be sent to the LLM")
+		return nil
+	}
+
+	keys := make([]string, 0, len(prompts))
+	for key := range prompts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		printDryRun(key, prompts[key])
+	}
+	return nil
+}
+
+// collectMetrics gathers the resources and their metrics into an analysis request
+func collectMetrics(ctx context.Context, s *spinner.Spinner, k8sClient *k8s.Client, source metrics.Source, withAI bool) (*metrics.AnalysisRequest, error) {
 	s.Suffix = " Gathering Kubernetes resources..."
 	s.Start()
 
 	// Determine which resources to analyze
 	var resourcesToAnalyze []string
-	if metricsAllResources {
+	if metricsAllResources || metricsSelector != "" {
 		resourcesToAnalyze = []string{} // Will be handled by GatherResources
 	} else {
 		resourcesToAnalyze = metricsResources
 	}
 
-	resourcesData, err := k8sClient.GatherResources(metricsNamespace, resourcesToAnalyze, metricsAllResources)
+	resourcesData, err := k8sClient.GatherResources(ctx, targetNamespace(metricsNamespace, metricsAllNamespaces), resourcesToAnalyze, metricsAllResources, metricsSelector)
 	if err != nil {
 		s.Stop()
-		return fmt.Errorf("failed to gather resources: %w", err)
+		return nil, fmt.Errorf("failed to gather resources: %w", err)
 	}
 
 	s.Stop()
-	printSuccess(fmt.Sprintf("Gathered %d resources", len(resourcesData)))
+	if !metricsWatch {
+		printSuccess(fmt.Sprintf("Gathered %d resources", len(resourcesData)))
+	}
 
 	s.Suffix = " Collecting metrics data..."
 	s.Start()
@@ -178,74 +726,236 @@ func runMetrics(cmd *cobra.Command, args []string) error {
 		resourcesList = append(resourcesList, resource)
 	}
 
-	metricsData, err := prometheusClient.GatherMetrics(resourcesList, duration)
-	if err != nil {
-		s.Stop()
-		return fmt.Errorf("failed to gather metrics: %w", err)
+	// Show query progress so a slow Prometheus can be told apart from a hang
+	if reporter, ok := source.(metrics.ProgressReporter); ok {
+		reporter.SetProgress(func(resource, query string, n, total int) {
+			s.Lock()
+			s.Suffix = fmt.Sprintf(" Collecting metrics for %s (%d/%d: %s)...", resource, n, total, query)
+			s.Unlock()
+		})
+		defer reporter.SetProgress(nil)
 	}
 
-	s.Stop()
-	printSuccess(fmt.Sprintf("Collected metrics for %s duration", duration))
-
-	s.Suffix = " Initializing AI client..."
-	s.Start()
-
-	// Initialize LLM client using factory
-	llmClient, err := llm.CreateFromEnv(metricsLLMProvider, metricsLLMModel)
+	metricsData, err := source.GatherMetrics(ctx, resourcesList, duration)
 	if err != nil {
 		s.Stop()
-		return fmt.Errorf("failed to initialize LLM client: %w", err)
+		return nil, fmt.Errorf("failed to gather metrics: %w", err)
 	}
 
 	s.Stop()
-	printSuccess("AI client initialized")
-
-	// Show LLM provider and model info
-	printLLMInfo(llmClient)
-	fmt.Println()
-
-	s.Suffix = " Analyzing metrics with AI..."
-	s.Start()
-
-	// Create metrics analyzer
-	metricsAnalyzer := metrics.NewAnalyzer(llmClient, prometheusClient, k8sClient)
+	if _, none := source.(metrics.NoMetricsSource); !metricsWatch && !none {
+		printSuccess(fmt.Sprintf("Collected metrics for %s duration", duration))
+	}
+	if showQueries {
+		printQueryDiagnostics(metricsData)
+	}
 
 	// Perform analysis based on flags
-	analysisRequest := &metrics.AnalysisRequest{
+	return &metrics.AnalysisRequest{
 		Resources:      resourcesList,
 		MetricsData:    metricsData,
 		Duration:       duration,
-		AnalyzeScaling: analyzeScaling,
-		HPAAnalysis:    hpaAnalysis,
-		KEDAAnalysis:   kedaAnalysis,
+		AnalyzeScaling: analyzeScaling && withAI,
+		HPAAnalysis:    hpaAnalysis && withAI,
+		KEDAAnalysis:   kedaAnalysis && withAI,
 		Namespace:      metricsNamespace,
-	}
+	}, nil
+}
 
-	analysis, err := metricsAnalyzer.AnalyzeMetrics(analysisRequest)
-	if err != nil {
-		s.Stop()
-		return fmt.Errorf("metrics analysis failed: %w", err)
+// printQueryDiagnostics lists the PromQL queries run for each resource, ready to paste into
+// the Prometheus UI, with the number of data points each returned
+func printQueryDiagnostics(metricsData map[string]*metrics.MetricsData) {
+	keys := make([]string, 0, len(metricsData))
+	for key := range metricsData {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fmt.Fprintf(statusOutput, "\n🔎 Queries for %s:\n", key)
+		for _, query := range metricsData[key].Queries {
+			switch {
+			case query.Error != "":
+				color.New(color.FgRed).Fprintf(statusOutput, "  ✗ %s: %s\n", query.Name, query.Error)
+			case query.Points == 0:
+				color.New(color.FgYellow).Fprintf(statusOutput, "  ⚠ %s: no data points\n", query.Name)
+			default:
+				color.New(color.FgGreen).Fprintf(statusOutput, "  ✓ %s: %d data points\n", query.Name, query.Points)
+			}
+			fmt.Fprintf(statusOutput, "    %s\n", query.Query)
+		}
 	}
+	fmt.Fprintln(statusOutput)
+}
 
-	s.Stop()
-	printSuccess("Metrics analysis complete")
+// watchMetrics redraws the charts every --interval until the command is interrupted.
+// The AI analysis from the first frame is kept on later frames instead of being re-run.
+func watchMetrics(cmd *cobra.Command, k8sClient *k8s.Client, source metrics.Source, metricsAnalyzer *metrics.Analyzer) error {
+	s := newSpinner()
+	// AI results of the first frame, by namespace/name
+	var first map[string]*metrics.AnalysisResult
+
+	for frame := 0; ; frame++ {
+		// Each frame gets its own --timeout so a long watch isn't cut short
+		ctx, cancel := commandContext(cmd)
+		analyses, err := collectAndAnalyzeMetrics(ctx, s, k8sClient, source, metricsAnalyzer, frame == 0)
+		cancel()
+		if err != nil {
+			if cmd.Context().Err() != nil {
+				return nil
+			}
+			return err
+		}
 
-	// Display results
-	displayMetricsResults(analysis, metricsOutputFormat)
+		if first == nil {
+			first = analyses
+		} else {
+			for key, analysis := range analyses {
+				if previous, ok := first[key]; ok {
+					analysis.Summary = previous.Summary
+					analysis.HPAConfig = previous.HPAConfig
+					analysis.KEDAConfig = previous.KEDAConfig
+					analysis.ResourceConfig = previous.ResourceConfig
+				}
+			}
+		}
 
-	return nil
+		clearScreen()
+		fmt.Printf("🔄 Refreshing every %s, last update %s (Ctrl-C to exit)\n", metricsWatchInterval, time.Now().Format("15:04:05"))
+		if err := displayMetricsResults(analyses, metricsOutputFormat); err != nil {
+			return err
+		}
+
+		select {
+		case <-cmd.Context().Done():
+			return nil
+		case <-time.After(metricsWatchInterval):
+		}
+	}
 }
 
-// displayMetricsResults displays the metrics analysis results
-func displayMetricsResults(analysis *metrics.AnalysisResult, outputFormat string) {
+// clearScreen moves the cursor home and clears the terminal
+func clearScreen() {
+	if color.NoColor {
+		return
+	}
+	fmt.Print("\033[H\033[2J")
+}
+
+// displayMetricsResults displays the analysis of each resource, ordered by namespace/name. JSON and
+// YAML output is shaped by structuredResults.
+func displayMetricsResults(analyses map[string]*metrics.AnalysisResult, outputFormat string) error {
+	keys, ordered := orderedResults(analyses)
+	structured := structuredResults(ordered)
+
 	switch outputFormat {
 	case "json":
-		displayMetricsJSON(analysis)
+		return displayMetricsJSON(structured)
 	case "yaml":
-		displayMetricsYAML(analysis)
+		return displayMetricsYAML(structured)
+	case "markdown", "md":
+		for i, analysis := range ordered {
+			if i > 0 {
+				fmt.Print("---\n\n")
+			}
+			displayMetricsMarkdown(analysis)
+		}
+	case "table":
+		displayMetricsTable(ordered)
 	default:
-		displayMetricsHuman(analysis)
+		for i, analysis := range ordered {
+			if len(ordered) > 1 {
+				printResourceSeparator(i+1, len(ordered), keys[i], analysis.ResourceType)
+			}
+			displayMetricsHuman(analysis)
+		}
+	}
+	return nil
+}
+
+// displayMetricsTable prints one aligned row per resource with its headline metrics, for scanning
+// a whole namespace at once. Metrics without data are shown as "-".
+func displayMetricsTable(ordered []*metrics.AnalysisResult) {
+	rows := make([][]string, 0, len(ordered))
+	for _, analysis := range ordered {
+		cpu, hasCPU := analysis.MetricsSummary["cpu_utilization"]
+		memory, hasMemory := analysis.MetricsSummary["memory_utilization"]
+
+		row := []string{analysis.Namespace, analysis.ResourceType + "/" + analysis.ResourceName, "-", "-", "-", "-", "-", "-", "-"}
+		if hasCPU {
+			row[2] = fmt.Sprintf("%.1f%%", cpu.Average)
+			row[3] = fmt.Sprintf("%.1f%%", cpu.Peak)
+			row[7] = tableTrend(cpu.Trend)
+		}
+		if hasMemory {
+			row[4] = fmt.Sprintf("%.0fMB", memory.Average)
+			row[5] = fmt.Sprintf("%.0fMB", memory.Peak)
+			row[8] = tableTrend(memory.Trend)
+		}
+		if replicas, ok := analysis.MetricsSummary["pod_replicas"]; ok {
+			row[6] = fmt.Sprintf("%.0f", replicas.Current)
+			if available, ok := analysis.MetricsSummary["pod_available"]; ok {
+				row[6] = fmt.Sprintf("%.0f/%.0f", available.Current, replicas.Current)
+			}
+		}
+		rows = append(rows, row)
 	}
+
+	fmt.Print(formatter.Table([]string{"NAMESPACE", "RESOURCE", "AVG CPU", "PEAK CPU", "AVG MEM", "PEAK MEM", "REPLICAS", "CPU TREND", "MEM TREND"}, rows))
+}
+
+// tableTrend returns a trend for a table cell, "-" when it is unknown
+func tableTrend(trend string) string {
+	if trend == "" {
+		return "-"
+	}
+	return trend
+}
+
+// orderedResults returns the keys and results of a run ordered by namespace/name
+func orderedResults(analyses map[string]*metrics.AnalysisResult) ([]string, []*metrics.AnalysisResult) {
+	keys := make([]string, 0, len(analyses))
+	for key := range analyses {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	ordered := make([]*metrics.AnalysisResult, 0, len(keys))
+	for _, key := range keys {
+		ordered = append(ordered, analyses[key])
+	}
+	return keys, ordered
+}
+
+// structuredResults is the JSON and YAML document for ordered results: a single object when one
+// resource was named, and a list for --all, -l or several -r however many resources matched
+func structuredResults(ordered []*metrics.AnalysisResult) interface{} {
+	if !multiResourceRun() && len(ordered) == 1 {
+		return ordered[0]
+	}
+	return ordered
+}
+
+// multiResourceRun reports whether the run may analyze more than one resource
+func multiResourceRun() bool {
+	return metricsAllResources || metricsSelector != "" || len(metricsResources) != 1
+}
+
+// printResourceSeparator introduces each resource when several are displayed in one run
+func printResourceSeparator(n, total int, key, resourceType string) {
+	magenta := color.New(color.FgMagenta, color.Bold)
+	fmt.Println()
+	magenta.Println(strings.Repeat("━", 60))
+	magenta.Printf("  [%d/%d] %s (%s)\n", n, total, key, resourceType)
+	magenta.Println(strings.Repeat("━", 60))
+}
+
+// onlyResult returns the result of a run limited to a single resource
+func onlyResult(analyses map[string]*metrics.AnalysisResult) *metrics.AnalysisResult {
+	for _, analysis := range analyses {
+		return analysis
+	}
+	return nil
 }
 
 // displayMetricsHuman displays results in human-readable format with enhanced charts
@@ -257,7 +967,7 @@ func displayMetricsHuman(analysis *metrics.AnalysisResult) {
 
 	// Resource information
 	fmt.Printf("📦 Resource: %s/%s (%s)\n", analysis.Namespace, analysis.ResourceName, analysis.ResourceType)
-	fmt.Printf("📅 Duration: %s\n", analysis.Duration)
+	fmt.Printf("📅 Duration: %s\n", windowLabel(analysis.Duration, analysis.Start, analysis.End))
 	fmt.Println()
 
 	// Display metrics charts
@@ -277,12 +987,83 @@ func displayMetricsHuman(analysis *metrics.AnalysisResult) {
 		} else {
 			fmt.Println("⚠️  No Memory metrics data available")
 		}
+
+		// Network I/O Charts
+		if receiveMetric, exists := analysis.MetricsSummary["network_receive"]; exists && len(receiveMetric.Values) > 0 {
+			receiveChart := formatter.CreateEnhancedLineChart(receiveMetric.Values, receiveMetric.Timestamps, "Network Receive", "MB/s", analysis.Duration)
+			fmt.Print(receiveChart)
+		}
+		if transmitMetric, exists := analysis.MetricsSummary["network_transmit"]; exists && len(transmitMetric.Values) > 0 {
+			transmitChart := formatter.CreateEnhancedLineChart(transmitMetric.Values, transmitMetric.Timestamps, "Network Transmit", "MB/s", analysis.Duration)
+			fmt.Print(transmitChart)
+		}
+
+		// Service mesh traffic charts
+		if requestMetric, exists := analysis.MetricsSummary[metrics.RequestRateMetric]; exists && len(requestMetric.Values) > 0 {
+			requestChart := formatter.CreateEnhancedLineChart(requestMetric.Values, requestMetric.Timestamps, "Request Rate", "req/s", analysis.Duration)
+			fmt.Print(requestChart)
+			if errorMetric, exists := analysis.MetricsSummary[metrics.ErrorRateMetric]; exists && len(errorMetric.Values) > 0 {
+				errorChart := formatter.CreateEnhancedLineChart(errorMetric.Values, errorMetric.Timestamps, "Error Rate (5xx)", "%", analysis.Duration)
+				fmt.Print(errorChart)
+			}
+		} else if mesh != "" {
+			fmt.Printf("⚠️  No %s traffic metrics found for this workload; is it part of the mesh?\n", mesh)
+		}
+
+		// Disk Charts
+		if fsMetric, exists := analysis.MetricsSummary["filesystem_usage"]; exists && len(fsMetric.Values) > 0 {
+			fsChart := formatter.CreateEnhancedLineChart(fsMetric.Values, fsMetric.Timestamps, "Filesystem", "MB", analysis.Duration)
+			fmt.Print(fsChart)
+		}
+		if pvcMetric, exists := analysis.MetricsSummary["pvc_usage"]; exists && len(pvcMetric.Values) > 0 {
+			title := "PVC Usage"
+			if capacity, ok := analysis.MetricsSummary["pvc_capacity"]; ok && capacity.Current > 0 {
+				title = fmt.Sprintf("PVC Usage (capacity %.0f MB)", capacity.Current)
+			}
+			pvcChart := formatter.CreateEnhancedLineChart(pvcMetric.Values, pvcMetric.Timestamps, title, "MB", analysis.Duration)
+			fmt.Print(pvcChart)
+		}
+		if readMetric, exists := analysis.MetricsSummary["disk_read"]; exists && len(readMetric.Values) > 0 {
+			readChart := formatter.CreateEnhancedLineChart(readMetric.Values, readMetric.Timestamps, "Disk Read", "MB/s", analysis.Duration)
+			fmt.Print(readChart)
+		}
+		if writeMetric, exists := analysis.MetricsSummary["disk_write"]; exists && len(writeMetric.Values) > 0 {
+			writeChart := formatter.CreateEnhancedLineChart(writeMetric.Values, writeMetric.Timestamps, "Disk Write", "MB/s", analysis.Duration)
+			fmt.Print(writeChart)
+		}
+
+		// Custom metrics from --queries-file, sorted by name so runs render the same way
+		var customNames []string
+		for name, metric := range analysis.MetricsSummary {
+			if !metrics.IsStandardQuery(name) && !metrics.IsMeshMetric(name) && len(metric.Values) > 0 {
+				customNames = append(customNames, name)
+			}
+		}
+		sort.Strings(customNames)
+		for _, name := range customNames {
+			metric := analysis.MetricsSummary[name]
+			customChart := formatter.CreateEnhancedLineChart(metric.Values, metric.Timestamps, name, metric.Unit, analysis.Duration)
+			fmt.Print(customChart)
+		}
+
+		displayContainerCharts(analysis)
 	} else {
 		fmt.Println("⚠️  No metrics summary data available")
 	}
 
-	// Replica Scaling Chart
-	if len(analysis.ScalingEvents) > 0 {
+	// Replica Scaling Chart (DaemonSets show desired vs ready vs available pods instead, and a bare
+	// pod has no replicas to chart)
+	switch {
+	case analysis.ResourceType == "DaemonSet":
+		desired := analysis.MetricsSummary["pod_replicas"]
+		ready := analysis.MetricsSummary["pod_ready"]
+		available := analysis.MetricsSummary["pod_available"]
+		if len(desired.Values) > 0 {
+			fmt.Print(formatter.CreateDaemonSetChart(desired.Values, ready.Values, available.Values, desired.Timestamps, "Daemon Pods"))
+		} else {
+			fmt.Println("⚠️  No daemon pod data available")
+		}
+	case analysis.ResourceType != "Pod" && len(analysis.ScalingEvents) > 0:
 		replicas := make([]int, len(analysis.ScalingEvents))
 		timestamps := make([]time.Time, len(analysis.ScalingEvents))
 
@@ -293,10 +1074,18 @@ func displayMetricsHuman(analysis *metrics.AnalysisResult) {
 
 		replicaChart := formatter.CreateReplicaBarChart(replicas, timestamps, "Replica Scaling Events")
 		fmt.Print(replicaChart)
-	} else {
+		displayScalingReasons(analysis.ScalingEvents)
+	case analysis.ResourceType != "Pod":
 		fmt.Println("⚠️  No scaling events data available")
 	}
 
+	// Jobs and CronJobs also chart completed and failed pods
+	for _, chart := range []struct{ name, title string }{{"job_succeeded", "Succeeded Pods"}, {"job_failed", "Failed Pods"}} {
+		if metric, exists := analysis.MetricsSummary[chart.name]; exists && len(metric.Values) > 0 {
+			fmt.Print(formatter.CreateEnhancedLineChart(metric.Values, metric.Timestamps, chart.title, metric.Unit, analysis.Duration))
+		}
+	}
+
 	// Only show AI Analysis and Recommendations when --analyze flag is used
 	if analyzeScaling {
 		// AI Analysis
@@ -355,6 +1144,29 @@ func displayMetricsHuman(analysis *metrics.AnalysisResult) {
 			}
 		}
 
+		// Requests and limits recommendations
+		if config := analysis.ResourceConfig; config != nil {
+			green := color.New(color.FgGreen, color.Bold)
+			green.Println("📐 RESOURCE RECOMMENDATIONS")
+			fmt.Println(strings.Repeat("=", 40))
+			fmt.Printf("  Containers: %s\n", strings.Join(config.Containers, ", "))
+			if config.CPURequest != "" {
+				fmt.Printf("  CPU: request %s, limit %s%s\n", config.CPURequest, config.CPULimit, currentResources(config.CurrentCPURequest, config.CurrentCPULimit))
+			}
+			if config.MemoryRequest != "" {
+				fmt.Printf("  Memory: request %s, limit %s%s\n", config.MemoryRequest, config.MemoryLimit, currentResources(config.CurrentMemoryRequest, config.CurrentMemoryLimit))
+			}
+			fmt.Printf("  Reasoning: %s\n", config.Reasoning)
+			fmt.Println()
+
+			if config.PatchYAML != "" {
+				fmt.Println("  Patch (save as resources-patch.yaml):")
+				fmt.Printf("```yaml\n%s\n```\n", config.PatchYAML)
+				fmt.Printf("  Apply with: %s\n", config.Command)
+				fmt.Println()
+			}
+		}
+
 		// General recommendations
 		if len(analysis.Recommendations) > 0 {
 			cyan.Println("💡 RECOMMENDATIONS")
@@ -395,29 +1207,242 @@ func displayMetricsHuman(analysis *metrics.AnalysisResult) {
 }
 
 // displayMetricsJSON displays results in JSON format
-func displayMetricsJSON(analysis *metrics.AnalysisResult) {
-	fmt.Println("JSON output not implemented yet")
+func displayMetricsJSON(analysis interface{}) error {
+	output, err := json.MarshalIndent(analysis, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(output))
+	return nil
 }
 
-// displayMetricsYAML displays results in YAML format
-func displayMetricsYAML(analysis *metrics.AnalysisResult) {
-	fmt.Println("YAML output not implemented yet")
+// displayMetricsYAML displays results in YAML format, using the same field names as JSON
+func displayMetricsYAML(analysis interface{}) error {
+	output, err := yaml.Marshal(analysis)
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(output))
+	return nil
 }
 
-func printMetricsHeader(resource string) {
+// displayContainerCharts charts CPU and memory for each container with --by-container
+func displayContainerCharts(analysis *metrics.AnalysisResult) {
+	if len(analysis.ContainerMetrics) == 0 {
+		return
+	}
+
 	cyan := color.New(color.FgCyan, color.Bold)
+	cyan.Printf("🧩 PER-CONTAINER BREAKDOWN (%d containers)\n", len(analysis.ContainerMetrics))
+	fmt.Println(strings.Repeat("=", 60))
+	for _, container := range containerNames(analysis.ContainerMetrics) {
+		if cpu, ok := analysis.ContainerMetrics[container]["cpu_utilization"]; ok && len(cpu.Values) > 0 {
+			fmt.Print(formatter.CreateEnhancedLineChart(cpu.Values, cpu.Timestamps, "CPU: "+container, "%", analysis.Duration))
+		}
+		if memory, ok := analysis.ContainerMetrics[container]["memory_utilization"]; ok && len(memory.Values) > 0 {
+			fmt.Print(formatter.CreateEnhancedLineChart(memory.Values, memory.Timestamps, "Memory: "+container, "MB", analysis.Duration))
+		}
+	}
+}
+
+// containerNames returns the containers of a per-container breakdown in alphabetical order
+func containerNames(containers map[string]map[string]metrics.MetricSummary) []string {
+	names := make([]string, 0, len(containers))
+	for name := range containers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// displayMetricsMarkdown displays results as Markdown, with the recommended manifests in fenced blocks
+func displayMetricsMarkdown(analysis *metrics.AnalysisResult) {
+	var md strings.Builder
+
+	fmt.Fprintf(&md, "# Metrics Analysis: %s/%s\n\n", analysis.Namespace, analysis.ResourceName)
+	fmt.Fprintf(&md, "- **Resource type:** %s\n", analysis.ResourceType)
+	fmt.Fprintf(&md, "- **Duration:** %s\n", windowLabel(analysis.Duration, analysis.Start, analysis.End))
+	fmt.Fprintf(&md, "- **Generated:** %s\n\n", analysis.GeneratedAt.Format(time.RFC3339))
+
+	if len(analysis.MetricsSummary) > 0 {
+		md.WriteString("## Metrics\n\n")
+		names := make([]string, 0, len(analysis.MetricsSummary))
+		for name := range analysis.MetricsSummary {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		rows := make([][]string, 0, len(names))
+		for _, name := range names {
+			m := analysis.MetricsSummary[name]
+			rows = append(rows, []string{
+				name, m.Unit,
+				fmt.Sprintf("%.2f", m.Average), fmt.Sprintf("%.2f", m.P50), fmt.Sprintf("%.2f", m.P95),
+				fmt.Sprintf("%.2f", m.P99), fmt.Sprintf("%.2f", m.Peak), fmt.Sprintf("%.2f", m.Minimum),
+				fmt.Sprintf("%.2f", m.Current), m.Trend, m.Utilization,
+			})
+		}
+		md.WriteString(formatter.MarkdownTable([]string{"Metric", "Unit", "Avg", "P50", "P95", "P99", "Peak", "Min", "Current", "Trend", "Utilization"}, rows))
+		md.WriteString("\n")
+	}
+
+	if len(analysis.ContainerMetrics) > 0 {
+		md.WriteString("## Per-Container Breakdown\n\n")
+		var rows [][]string
+		for _, container := range containerNames(analysis.ContainerMetrics) {
+			for _, name := range []string{"cpu_utilization", "memory_utilization"} {
+				if m, ok := analysis.ContainerMetrics[container][name]; ok {
+					rows = append(rows, []string{
+						container, name, m.Unit,
+						fmt.Sprintf("%.2f", m.Average), fmt.Sprintf("%.2f", m.P95), fmt.Sprintf("%.2f", m.Peak), m.Trend,
+					})
+				}
+			}
+		}
+		md.WriteString(formatter.MarkdownTable([]string{"Container", "Metric", "Unit", "Avg", "P95", "Peak", "Trend"}, rows))
+		md.WriteString("\n")
+	}
+
+	if analysis.Summary != "" {
+		md.WriteString("## AI Analysis\n\n")
+		fmt.Fprintf(&md, "%s\n\n", strings.TrimSpace(analysis.Summary))
+	}
+
+	if analysis.HPAConfig != nil {
+		md.WriteString("## HPA Recommendation\n\n")
+		fmt.Fprintf(&md, "- **Min/Max replicas:** %d/%d\n", analysis.HPAConfig.MinReplicas, analysis.HPAConfig.MaxReplicas)
+		if analysis.HPAConfig.TargetCPU > 0 {
+			fmt.Fprintf(&md, "- **Target CPU:** %d%%\n", analysis.HPAConfig.TargetCPU)
+		}
+		if analysis.HPAConfig.TargetMemory > 0 {
+			fmt.Fprintf(&md, "- **Target memory:** %d%%\n", analysis.HPAConfig.TargetMemory)
+		}
+		fmt.Fprintf(&md, "- **Reasoning:** %s\n\n", analysis.HPAConfig.Reasoning)
+		if analysis.HPAConfig.YAMLConfig != "" {
+			md.WriteString(formatter.MarkdownCodeBlock("yaml", analysis.HPAConfig.YAMLConfig))
+			md.WriteString("\n")
+		}
+	}
+
+	if analysis.KEDAConfig != nil {
+		md.WriteString("## KEDA Recommendation\n\n")
+		fmt.Fprintf(&md, "- **Min/Max replicas:** %d/%d\n", analysis.KEDAConfig.MinReplicas, analysis.KEDAConfig.MaxReplicas)
+		fmt.Fprintf(&md, "- **Polling interval:** %ds\n", analysis.KEDAConfig.PollingInterval)
+		fmt.Fprintf(&md, "- **Cooldown period:** %ds\n", analysis.KEDAConfig.CooldownPeriod)
+		fmt.Fprintf(&md, "- **Reasoning:** %s\n\n", analysis.KEDAConfig.Reasoning)
+		if analysis.KEDAConfig.YAMLConfig != "" {
+			md.WriteString(formatter.MarkdownCodeBlock("yaml", analysis.KEDAConfig.YAMLConfig))
+			md.WriteString("\n")
+		}
+	}
+
+	if config := analysis.ResourceConfig; config != nil {
+		md.WriteString("## Resource Recommendations\n\n")
+		fmt.Fprintf(&md, "- **Containers:** %s\n", strings.Join(config.Containers, ", "))
+		if config.CPURequest != "" {
+			fmt.Fprintf(&md, "- **CPU:** request %s, limit %s%s\n", config.CPURequest, config.CPULimit, currentResources(config.CurrentCPURequest, config.CurrentCPULimit))
+		}
+		if config.MemoryRequest != "" {
+			fmt.Fprintf(&md, "- **Memory:** request %s, limit %s%s\n", config.MemoryRequest, config.MemoryLimit, currentResources(config.CurrentMemoryRequest, config.CurrentMemoryLimit))
+		}
+		fmt.Fprintf(&md, "- **Reasoning:** %s\n\n", config.Reasoning)
+		if config.PatchYAML != "" {
+			md.WriteString(formatter.MarkdownCodeBlock("yaml", config.PatchYAML))
+			fmt.Fprintf(&md, "Apply with `%s`.\n\n", config.Command)
+		}
+	}
+
+	if len(analysis.Recommendations) > 0 {
+		md.WriteString("## Recommendations\n\n")
+		for _, rec := range analysis.Recommendations {
+			fmt.Fprintf(&md, "### %s\n\n", rec.Title)
+			fmt.Fprintf(&md, "**Priority:** %s\n\n", rec.Priority)
+			if rec.Description != "" {
+				fmt.Fprintf(&md, "%s\n\n", rec.Description)
+			}
+			if rec.Command != "" {
+				md.WriteString(formatter.MarkdownCodeBlock("bash", rec.Command))
+				md.WriteString("\n")
+			}
+			if rec.Reasoning != "" {
+				fmt.Fprintf(&md, "_%s_\n\n", rec.Reasoning)
+			}
+		}
+	}
+
+	fmt.Print(md.String())
+}
+
+// currentResources describes a container's current request and limit for comparison, or
+// returns "" when neither is known
+func currentResources(request, limit string) string {
+	if request == "" && limit == "" {
+		return ""
+	}
+	if request == "" {
+		request = "none"
+	}
+	if limit == "" {
+		limit = "none"
+	}
+	return fmt.Sprintf(" (currently %s / %s)", request, limit)
+}
+
+// displayScalingReasons lists the latest replica changes under the replica chart with what caused
+// them, when Kubernetes events explain at least one
+func displayScalingReasons(events []metrics.ScalingEvent) {
+	changes, previous := metrics.ScalingChanges(events)
+	explained := false
+	for _, change := range changes {
+		if change.Reason != metrics.ScalingReasonUnknown {
+			explained = true
+		}
+	}
+	if !explained {
+		return
+	}
+
+	const maxShown = 10
+	if len(changes) > maxShown {
+		changes, previous = changes[len(changes)-maxShown:], previous[len(previous)-maxShown:]
+	}
+	fmt.Println(color.HiBlackString("Scaling Reasons:"))
+	for i, change := range changes {
+		arrow := color.GreenString("↑")
+		if change.Replicas < previous[i] {
+			arrow = color.RedString("↓")
+		}
+		reason := change.Reason
+		if reason == metrics.ScalingReasonUnknown {
+			reason = color.HiBlackString("unknown (no Kubernetes event)")
+		}
+		fmt.Printf("  %s %s  %d → %d  %s\n", arrow, change.Timestamp.Local().Format("Jan 2 15:04"), previous[i], change.Replicas, reason)
+	}
 	fmt.Println()
-	cyan.Println("📊 Kubernetes AI Metrics Analyzer")
+}
+
+func printMetricsHeader(resource string, k8sClient *k8s.Client) {
+	cyan := color.New(color.FgCyan, color.Bold)
+	fmt.Fprintln(statusOutput)
+	cyan.Fprintln(statusOutput, "📊 Kubernetes AI Metrics Analyzer")
 	if resource != "" {
-		fmt.Printf("📦 Resource: %s\n", resource)
+		fmt.Fprintf(statusOutput, "📦 Resource: %s\n", resource)
 	}
-	fmt.Printf("📍 Namespace: %s\n", metricsNamespace)
-	fmt.Printf("📅 Duration: %s\n", duration)
-
-	if metricsAllResources {
-		fmt.Println("📊 Scope: all deployments")
+	printContext(k8sClient)
+	printNamespace(metricsNamespace, metricsAllNamespaces)
+	if metricsStartTime.IsZero() {
+		fmt.Fprintf(statusOutput, "📅 Duration: %s\n", duration)
 	} else {
-		fmt.Printf("📊 Resources: %s\n", strings.Join(metricsResources, ", "))
+		fmt.Fprintf(statusOutput, "📅 Duration: %s\n", windowLabel(duration, &metricsStartTime, &metricsEndTime))
+	}
+
+	switch {
+	case metricsSelector != "":
+		fmt.Fprintf(statusOutput, "📊 Scope: resources matching %s\n", metricsSelector)
+	case metricsAllResources:
+		fmt.Fprintln(statusOutput, "📊 Scope: all deployments")
+	default:
+		fmt.Fprintf(statusOutput, "📊 Resources: %s\n", strings.Join(metricsResources, ", "))
 	}
 
 	// Show analysis flags
@@ -432,8 +1457,8 @@ func printMetricsHeader(resource string) {
 		analyses = append(analyses, "KEDA")
 	}
 	if len(analyses) > 0 {
-		fmt.Printf("🔍 Analysis: %s\n", strings.Join(analyses, ", "))
+		fmt.Fprintf(statusOutput, "🔍 Analysis: %s\n", strings.Join(analyses, ", "))
 	}
 
-	fmt.Println()
+	fmt.Fprintln(statusOutput)
 }
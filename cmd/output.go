@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/fatih/color"
+	"github.com/helmcode/kubectl-ai/pkg/k8s"
+	"github.com/helmcode/kubectl-ai/pkg/llm"
+	"github.com/helmcode/kubectl-ai/pkg/metrics"
+	"github.com/spf13/cobra"
+)
+
+// statusOutput receives headers, progress messages and spinners. It is moved to stderr
+// when stdout carries JSON or YAML so the output stays parseable.
+var statusOutput io.Writer = color.Output
+
+// configureOutput disables colors when stdout isn't a terminal, NO_COLOR is set or
+// --no-color is passed, and moves status output to stderr for machine-readable formats
+func configureOutput(cmd *cobra.Command, outputFormat string) {
+	noColor, err := cmd.Flags().GetBool("no-color")
+	if (err == nil && noColor) || os.Getenv("NO_COLOR") != "" || !isTerminal(os.Stdout) {
+		color.NoColor = true
+	}
+
+	if outputFormat != "human" {
+		statusOutput = color.Error
+		metrics.ProgressOutput = color.Error
+	}
+}
+
+// verboseEnabled is set by --verbose, see configureVerbose
+var verboseEnabled bool
+
+// configureVerbose traces every Kubernetes API call, Prometheus query and LLM request to stderr.
+// It must run before the clients are created.
+func configureVerbose(enabled bool) {
+	if !enabled {
+		return
+	}
+	verboseEnabled = true
+	k8s.VerboseOutput = os.Stderr
+	metrics.VerboseOutput = os.Stderr
+	llm.VerboseOutput = os.Stderr
+}
+
+// newSpinner creates the progress spinner, writing wherever status output goes
+func newSpinner() *spinner.Spinner {
+	s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
+	if statusOutput != color.Output {
+		s.WriterFile = os.Stderr
+		s.Writer = os.Stderr
+	}
+	if verboseEnabled {
+		// The spinner would redraw over the trace lines
+		s.Disable()
+	}
+	return s
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/helmcode/kubectl-ai/pkg/k8s"
+	"github.com/helmcode/kubectl-ai/pkg/metrics"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/util/homedir"
+)
+
+var (
+	// Common flags (similar to metrics command)
+	recommendKubeconfig  string
+	recommendNamespace   string
+	recommendKubeContext string
+	recommendConcurrency int
+
+	// Recommend-specific flags
+	recommendType             string
+	recommendDuration         string
+	recommendStep             time.Duration
+	recommendAllowScaleToZero bool
+
+	// Prometheus connection flags
+	recommendPrometheusURL                string
+	recommendPrometheusNamespace          string
+	recommendPrometheusServiceNames       []string
+	recommendPrometheusQueryTimeout       time.Duration
+	recommendPrometheusToken              string
+	recommendPrometheusCACert             string
+	recommendPrometheusInsecureSkipVerify bool
+	recommendPrometheusUsername           string
+	recommendPrometheusPassword           string
+)
+
+func NewRecommendCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recommend RESOURCE... [flags]",
+		Short: "Print a recommended HPA or KEDA manifest based on Prometheus metrics",
+		Long: `Collect Prometheus metrics for a workload and print only the recommended
+autoscaler manifest, ready to pipe into kubectl apply. Progress messages go
+to stderr so stdout is always plain YAML.
+
+Examples:
+  # Print a recommended HPA for a deployment
+  kubectl ai recommend deploy/api -n production
+
+  # Apply a KEDA ScaledObject based on the last 7 days of metrics
+  kubectl ai recommend deploy/worker --type keda --duration 7d | kubectl apply -f -
+
+  # Recommend HPAs for several workloads at once (separated by ---)
+  kubectl ai recommend deploy/api statefulset/postgres -n production`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: resourceCompletion(workloadResourceTypes),
+		RunE:              runRecommend,
+	}
+
+	// Common flags (similar to metrics command)
+	if home := homedir.HomeDir(); home != "" {
+		cmd.Flags().StringVar(&recommendKubeconfig, "kubeconfig", "~/.kube/config", "Path to kubeconfig file")
+	}
+
+	cmd.Flags().StringVarP(&recommendNamespace, "namespace", "n", "default", "Kubernetes namespace")
+	cmd.Flags().StringVar(&recommendKubeContext, "context", "", "Kubeconfig context (overrides current-context)")
+	cmd.Flags().IntVar(&recommendConcurrency, "concurrency", k8s.DefaultConcurrency, "Maximum number of concurrent Kubernetes and Prometheus requests")
+
+	// Recommend-specific flags
+	cmd.Flags().StringVar(&recommendType, "type", "hpa", "Manifest to emit (hpa, keda)")
+	cmd.Flags().StringVar(&recommendDuration, "duration", "24h", "Duration of metrics to base the recommendation on (e.g. 30m, 24h, 7d, 2w or 7d12h)")
+	cmd.Flags().DurationVar(&recommendStep, "step", 0, "Prometheus query resolution, e.g. 5m (auto-selected from --duration if not set)")
+	cmd.Flags().BoolVar(&recommendAllowScaleToZero, "allow-scale-to-zero", false, "Let --type keda scale to zero even if the workload was never idle (adds cold starts)")
+	cmd.Flags().StringVar(&recommendPrometheusURL, "prometheus-url", "", "Prometheus server URL (auto-detects if not provided)")
+	cmd.Flags().StringVar(&recommendPrometheusNamespace, "prometheus-namespace", "", "Prometheus namespace for auto-detection")
+	cmd.Flags().DurationVar(&recommendPrometheusQueryTimeout, "prometheus-query-timeout", metrics.DefaultQueryTimeout, "Timeout for each Prometheus query; a query that exceeds it is skipped with a warning")
+	cmd.Flags().StringSliceVar(&recommendPrometheusServiceNames, "prometheus-service-name", nil, "Service names to try during auto-detection, replacing the built-in Prometheus, VictoriaMetrics and Thanos names")
+	cmd.Flags().StringVar(&recommendPrometheusToken, "prometheus-token", "", "Bearer token for Prometheus (env: PROMETHEUS_TOKEN)")
+	cmd.Flags().StringVar(&recommendPrometheusCACert, "prometheus-ca-cert", "", "Path to a CA certificate used to verify Prometheus TLS (env: PROMETHEUS_CA_CERT)")
+	cmd.Flags().StringVar(&recommendPrometheusUsername, "prometheus-username", "", "Basic auth username for Prometheus (env: PROMETHEUS_USERNAME)")
+	cmd.Flags().StringVar(&recommendPrometheusPassword, "prometheus-password", "", "Basic auth password for Prometheus (env: PROMETHEUS_PASSWORD)")
+	cmd.Flags().BoolVar(&recommendPrometheusInsecureSkipVerify, "prometheus-insecure-skip-verify", false, "Skip Prometheus TLS certificate verification (env: PROMETHEUS_INSECURE_SKIP_VERIFY)")
+
+	registerCompletions(cmd, nil)
+
+	return cmd
+}
+
+func runRecommend(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	if recommendType != "hpa" && recommendType != "keda" {
+		return fmt.Errorf("unsupported --type %q (supported: hpa, keda)", recommendType)
+	}
+
+	// Keep stdout plain YAML so it can be piped into kubectl apply
+	configureOutput(cmd, "yaml")
+
+	// Expand home symbol in kubeconfig if needed
+	if strings.HasPrefix(recommendKubeconfig, "~/") {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			recommendKubeconfig = filepath.Join(homeDir, recommendKubeconfig[2:])
+		}
+	}
+
+	// Initialize K8s client
+	k8sClient, err := k8s.NewClient(recommendKubeconfig, recommendKubeContext)
+	if err != nil {
+		return fmt.Errorf("failed to connect to cluster: %w", err)
+	}
+	k8sClient.SetConcurrency(recommendConcurrency)
+	k8sClient.SetRefreshDiscoveryCache(refreshCache(cmd))
+
+	prometheusAuth := metrics.AuthConfig{
+		BearerToken:        recommendPrometheusToken,
+		CACertFile:         recommendPrometheusCACert,
+		InsecureSkipVerify: recommendPrometheusInsecureSkipVerify,
+		Username:           recommendPrometheusUsername,
+		Password:           recommendPrometheusPassword,
+	}.WithEnvDefaults()
+
+	prometheusClient, err := metrics.NewPrometheusClient(ctx, recommendPrometheusURL, recommendPrometheusNamespace, recommendPrometheusServiceNames, k8sClient, prometheusAuth)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Prometheus: %w", err)
+	}
+
+	// Ensure cleanup of port-forward when function exits
+	defer prometheusClient.Close()
+
+	prometheusClient.SetConcurrency(recommendConcurrency)
+	prometheusClient.SetStep(recommendStep)
+	prometheusClient.SetQueryTimeout(recommendPrometheusQueryTimeout)
+
+	resourcesData, err := k8sClient.GatherResources(ctx, recommendNamespace, args, false, "")
+	if err != nil {
+		return fmt.Errorf("failed to gather resources: %w", err)
+	}
+
+	resourcesList := make([]interface{}, 0, len(resourcesData))
+	for _, resource := range resourcesData {
+		resourcesList = append(resourcesList, resource)
+	}
+
+	metricsData, err := prometheusClient.GatherMetrics(ctx, resourcesList, recommendDuration)
+	if err != nil {
+		return fmt.Errorf("failed to gather metrics: %w", err)
+	}
+	if len(metricsData) == 0 {
+		return fmt.Errorf("no workloads found to recommend scaling for")
+	}
+
+	// The recommendation is rule-based, no LLM is needed
+	metricsAnalyzer := metrics.NewAnalyzer(nil, prometheusClient, k8sClient)
+	metricsAnalyzer.SetAllowScaleToZero(recommendAllowScaleToZero)
+
+	keys := make([]string, 0, len(metricsData))
+	for key := range metricsData {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	manifests := make([]string, 0, len(keys))
+	for _, key := range keys {
+		// DaemonSets, Jobs, CronJobs and bare pods have no replica count to scale
+		if data := metricsData[key]; !metrics.SupportsAutoscaling(data.ResourceType) {
+			fmt.Fprintf(statusOutput, "⚠️  Skipping %s %s/%s, it can't be autoscaled\n", data.ResourceType, data.Namespace, data.ResourceName)
+			continue
+		}
+		manifest, err := metricsAnalyzer.RecommendManifest(ctx, metricsData[key], recommendType)
+		if err != nil {
+			return err
+		}
+		manifests = append(manifests, manifest)
+	}
+	if len(manifests) == 0 {
+		return fmt.Errorf("none of the workloads found can be autoscaled")
+	}
+
+	fmt.Println(strings.Join(manifests, "\n---\n"))
+	return nil
+}
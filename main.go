@@ -1,10 +1,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/helmcode/kubectl-ai/cmd"
+	"github.com/helmcode/kubectl-ai/pkg/llm"
 	"github.com/spf13/cobra"
 )
 
@@ -13,8 +17,13 @@ var (
 )
 
 func main() {
+	// Cancel in-flight work on Ctrl-C so commands can clean up (e.g. port-forwards)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
 	rootCmd := newRootCmd()
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -29,13 +38,26 @@ configuration issues, performance problems, and provide recommendations.`,
 		SilenceUsage: true,
 	}
 
-	// Disable automatic 'completion' command added by cobra
+	rootCmd.PersistentFlags().Duration("timeout", cmd.DefaultTimeout, "Maximum time to wait for the command to complete (0 disables the timeout)")
+	rootCmd.PersistentFlags().Bool("no-color", false, "Disable colored output (also disabled when NO_COLOR is set or stdout is not a terminal)")
+	rootCmd.PersistentFlags().Bool("refresh-cache", false, "Ignore the persisted API discovery cache and rediscover cluster resources")
+	rootCmd.PersistentFlags().Bool("cache", false, "Reuse cached LLM responses to identical prompts instead of calling the API again (env: KUBECTL_AI_CACHE)")
+	rootCmd.PersistentFlags().Bool("no-cache", false, "Ignore cached LLM responses and make a fresh call, updating the cache when it is enabled")
+	rootCmd.PersistentFlags().Duration("cache-ttl", llm.DefaultCacheTTL, "How long cached LLM responses are reused")
+	rootCmd.PersistentFlags().String("openai-base-url", "", "Base URL of an OpenAI-compatible API such as LiteLLM, OpenRouter, vLLM or Groq, for --provider openai (env: OPENAI_BASE_URL)")
+
+	// Use our own 'completion' command, limited to the shells we support
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 
 	// Add subcommands
 	rootCmd.AddCommand(
 		cmd.NewDebugCmd(),
 		cmd.NewMetricsCmd(),
+		cmd.NewLogsCmd(),
+		cmd.NewExplainCmd(),
+		cmd.NewDiffCmd(),
+		cmd.NewRecommendCmd(),
+		cmd.NewCompletionCmd(),
 		newVersionCmd(),
 	)
 
@@ -1,8 +1,11 @@
 package analyzer
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/helmcode/kubectl-ai/pkg/k8s"
 	"github.com/helmcode/kubectl-ai/pkg/llm"
 	"github.com/helmcode/kubectl-ai/pkg/model"
 	"github.com/helmcode/kubectl-ai/pkg/parser"
@@ -11,6 +14,13 @@ import (
 
 type Analyzer struct {
 	llm llm.LLM
+
+	// Context window used to size prompts (0 uses the provider's default)
+	maxContextTokens int
+
+	// The debug prompt the last analysis was answered for, which may have been trimmed further
+	// than BuildPrompt's after the provider rejected it as too long
+	lastPrompt string
 }
 
 func New(apiKey string) *Analyzer {
@@ -40,16 +50,299 @@ func NewWithLLM(l llm.LLM) *Analyzer {
 	return &Analyzer{llm: l}
 }
 
+// SetMaxContextTokens overrides the context window used to size prompts (0 uses the provider's default)
+func (a *Analyzer) SetMaxContextTokens(n int) {
+	a.maxContextTokens = n
+}
+
+// promptBudget returns the number of tokens the debug prompt may use
+func (a *Analyzer) promptBudget() int {
+	contextTokens := a.maxContextTokens
+	if contextTokens <= 0 {
+		contextTokens = llm.MaxContextTokens(a.llm)
+	}
+	return llm.PromptTokenBudget(contextTokens, llm.ResponseReserveTokens(a.llm))
+}
+
+// BuildPrompt returns the debug prompt Analyze would send, trimmed to the context window
+func (a *Analyzer) BuildPrompt(problem string, resources map[string]interface{}) (string, error) {
+	return prompts.BuildDebugPromptWithLimit(problem, resources, a.promptBudget())
+}
+
 func (a *Analyzer) Analyze(problem string, resources map[string]interface{}) (*model.Analysis, error) {
-	prompt, err := prompts.BuildDebugPrompt(problem, resources)
+	prompt, err := a.BuildPrompt(problem, resources)
+	if err != nil {
+		return nil, err
+	}
+
+	prompt, rawResp, err := a.sendFitting(problem, resources, prompt, func(prompt string) (string, error) {
+		return llm.ChatJSON(a.llm, a.promptMessages(prompt))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("LLM chat: %w", err)
+	}
+	a.lastPrompt = prompt
+
+	analysis, err := a.parseResponse(prompt, rawResp, problem)
+	if err != nil {
+		return nil, err
+	}
+	flagTerminations(analysis, resources)
+	flagRestarts(analysis, resources)
+	return analysis, nil
+}
+
+// AnalyzeLogs asks the LLM to explain the given pod logs, keeping only the most recent ones when
+// they would overflow the context window
+func (a *Analyzer) AnalyzeLogs(podName string, pod interface{}, logs string) (*model.Analysis, error) {
+	prompt, err := prompts.BuildLogsPromptWithLimit(podName, pod, logs, a.promptBudget())
+	if err != nil {
+		return nil, err
+	}
+
+	rawResp, err := llm.ChatJSON(a.llm, []llm.Message{{Role: llm.RoleUser, Content: prompt}})
+	if err != nil {
+		return nil, fmt.Errorf("LLM chat: %w", err)
+	}
+
+	return a.parseResponse(prompt, rawResp, fmt.Sprintf("Log analysis for pod %s", podName))
+}
+
+// Explain asks the LLM for a plain-English description of a resource and its related objects
+func (a *Analyzer) Explain(resource string, resources map[string]interface{}) (*model.Analysis, error) {
+	prompt, err := prompts.BuildExplainPrompt(resource, resources)
+	if err != nil {
+		return nil, err
+	}
+
+	rawResp, err := llm.ChatJSON(a.llm, []llm.Message{{Role: llm.RoleUser, Content: prompt}})
+	if err != nil {
+		return nil, fmt.Errorf("LLM chat: %w", err)
+	}
+
+	return a.parseResponse(prompt, rawResp, fmt.Sprintf("Explanation of %s", resource))
+}
+
+// AnalyzeDiff asks the LLM what changed between two versions of a resource and whether the change
+// explains the symptom, which may be empty
+func (a *Analyzer) AnalyzeDiff(subject, from, to, symptom string, changes interface{}) (*model.Analysis, error) {
+	prompt, err := prompts.BuildDiffPrompt(subject, from, to, symptom, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	rawResp, err := llm.ChatJSON(a.llm, []llm.Message{{Role: llm.RoleUser, Content: prompt}})
+	if err != nil {
+		return nil, fmt.Errorf("LLM chat: %w", err)
+	}
+
+	problem := symptom
+	if problem == "" {
+		problem = fmt.Sprintf("Changes to %s from %s to %s", subject, from, to)
+	}
+	return a.parseResponse(prompt, rawResp, problem)
+}
+
+// promptMessages returns the conversation that sends prompt. For LLMs with prompt caching the
+// fixed debug instructions go in the system prompt, so they are cached and reused by the JSON
+// retry and follow-up questions; other LLMs get the prompt as a single user message.
+func (a *Analyzer) promptMessages(prompt string) []llm.Message {
+	if llm.CachesPrompts(a.llm) {
+		if system, user := prompts.SplitDebugPrompt(prompt); system != "" {
+			return []llm.Message{
+				{Role: llm.RoleSystem, Content: system},
+				{Role: llm.RoleUser, Content: user},
+			}
+		}
+	}
+	return []llm.Message{{Role: llm.RoleUser, Content: prompt}}
+}
+
+// CanStream reports whether the underlying LLM supports streaming responses
+func (a *Analyzer) CanStream() bool {
+	_, ok := a.llm.(llm.Streamer)
+	return ok
+}
+
+// AnalyzeStream works like Analyze but forwards response chunks to out as they
+// arrive. out is closed once the LLM finishes. It falls back to a single
+// non-streaming call when the LLM does not support streaming.
+func (a *Analyzer) AnalyzeStream(problem string, resources map[string]interface{}, out chan<- string) (*model.Analysis, error) {
+	prompt, err := a.BuildPrompt(problem, resources)
 	if err != nil {
+		close(out)
 		return nil, err
 	}
 
-	rawResp, err := a.llm.Chat(prompt)
+	send := func(prompt string) (string, error) {
+		if !a.CanStream() {
+			rawResp, err := llm.ChatJSON(a.llm, a.promptMessages(prompt))
+			if err == nil {
+				out <- rawResp
+			}
+			return rawResp, err
+		}
+
+		// Tee the chunks so we can parse the full response once streaming ends
+		chunks := make(chan string)
+		done := make(chan string)
+		go func() {
+			var full strings.Builder
+			for chunk := range chunks {
+				full.WriteString(chunk)
+				out <- chunk
+			}
+			done <- full.String()
+		}()
+
+		streamErr := llm.ChatStreamMessages(a.llm, a.promptMessages(prompt), chunks)
+		return <-done, streamErr
+	}
+
+	prompt, rawResp, err := a.sendFitting(problem, resources, prompt, send)
+	close(out)
 	if err != nil {
 		return nil, fmt.Errorf("LLM chat: %w", err)
 	}
+	a.lastPrompt = prompt
+
+	analysis, err := a.parseResponse(prompt, rawResp, problem)
+	if err != nil {
+		return nil, err
+	}
+	flagTerminations(analysis, resources)
+	flagRestarts(analysis, resources)
+	return analysis, nil
+}
+
+// sendFitting sends the debug prompt and, when the provider rejects it as too long for the context
+// window because the ~4 characters per token estimate undercounted, retries once with the prompt
+// rebuilt for half the budget. It returns the prompt that was answered along with the reply.
+func (a *Analyzer) sendFitting(problem string, resources map[string]interface{}, prompt string, send func(prompt string) (string, error)) (string, string, error) {
+	rawResp, err := send(prompt)
+	if !errors.Is(err, llm.ErrContextLengthExceeded) {
+		return prompt, rawResp, err
+	}
+	prompt, err = prompts.BuildDebugPromptWithLimit(problem, resources, a.promptBudget()/2)
+	if err != nil {
+		return "", "", err
+	}
+	rawResp, err = send(prompt)
+	return prompt, rawResp, err
+}
+
+// flagTerminations makes sure every OOMKilled or crashed container gathered with the resources is
+// reported as a high severity issue, adding the ones the model didn't mention
+func flagTerminations(analysis *model.Analysis, resources map[string]interface{}) {
+	terminations, ok := resources[k8s.TerminationsKey].([]k8s.ContainerTermination)
+	if !ok {
+		return
+	}
+
+	for _, t := range terminations {
+		if mentioned(analysis.Issues, t.Pod, t.Reason) {
+			continue
+		}
+		description := fmt.Sprintf("Container %s was %s (exit code %d) and restarted %d times", t.Container, t.Reason, t.ExitCode, t.Restarts)
+		if t.Reason == k8s.ReasonError {
+			description = fmt.Sprintf("Container %s exited with an error (exit code %d) and restarted %d times", t.Container, t.ExitCode, t.Restarts)
+		}
+		if t.MemoryLimit != "" && t.Reason == k8s.ReasonOOMKilled {
+			description += fmt.Sprintf("; its memory limit is %s", t.MemoryLimit)
+		}
+		field := "lastState.terminated"
+		if t.Current {
+			field = "state.terminated"
+		}
+		analysis.Issues = append(analysis.Issues, model.Issue{
+			Component:   "pod/" + t.Pod,
+			Severity:    "high",
+			Description: description,
+			Evidence:    fmt.Sprintf("containerStatuses[%s].%s.reason: %s", t.Container, field, t.Reason),
+		})
+	}
+
+	// Crashing or OOMKilled containers make the whole analysis at least high severity
+	if len(terminations) > 0 {
+		raiseSeverity(analysis, "high")
+	}
+}
+
+// flagRestarts makes sure every crash-looping or frequently restarting container is reported,
+// as a critical issue for a restart storm and a high severity one otherwise
+func flagRestarts(analysis *model.Analysis, resources map[string]interface{}) {
+	restarts, ok := resources[k8s.RestartsKey].([]k8s.ContainerRestarts)
+	if !ok {
+		return
+	}
+
+	for _, r := range restarts {
+		severity := "high"
+		if r.Storm {
+			severity = "critical"
+		}
+		raiseSeverity(analysis, severity)
+
+		if r.WaitingReason != "" && mentioned(analysis.Issues, r.Pod, r.WaitingReason) {
+			continue
+		}
+		if r.WaitingReason == "" && mentioned(analysis.Issues, r.Pod, "restart") {
+			continue
+		}
+
+		description := fmt.Sprintf("Container %s restarted %d times (about %.1f per hour)", r.Container, r.Restarts, r.RestartsPerHour)
+		if r.WaitingReason != "" {
+			description += " and is in " + r.WaitingReason
+		}
+		evidence := fmt.Sprintf("containerStatuses[%s].restartCount: %d", r.Container, r.Restarts)
+		if r.WaitingReason != "" {
+			evidence += fmt.Sprintf(", state.waiting.reason: %s", r.WaitingReason)
+		}
+		analysis.Issues = append(analysis.Issues, model.Issue{
+			Component:   "pod/" + r.Pod,
+			Severity:    severity,
+			Description: description,
+			Evidence:    evidence,
+		})
+	}
+}
+
+// raiseSeverity raises the overall severity of the analysis to at least severity
+func raiseSeverity(analysis *model.Analysis, severity string) {
+	level, _ := model.SeverityLevel(analysis.Severity)
+	if target, _ := model.SeverityLevel(severity); level < target {
+		analysis.Severity = severity
+	}
+}
+
+// mentioned reports whether an issue already covers the pod and the given reason
+func mentioned(issues []model.Issue, pod, reason string) bool {
+	for _, issue := range issues {
+		text := strings.ToLower(issue.Component + " " + issue.Description + " " + issue.Evidence)
+		if strings.Contains(text, strings.ToLower(pod)) && strings.Contains(text, strings.ToLower(reason)) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseResponse parses the JSON analysis in rawResp. When the model answered with something
+// else, it re-asks once for JSON only, and falls back to a text analysis of the original
+// response if the retry doesn't parse either.
+func (a *Analyzer) parseResponse(prompt, rawResp, problem string) (*model.Analysis, error) {
+	if analysis, err := parser.ParseDebugJSON(rawResp, problem); err == nil {
+		return analysis, nil
+	}
+
+	retryResp, err := llm.ChatJSON(a.llm, append(a.promptMessages(prompt),
+		llm.Message{Role: llm.RoleAssistant, Content: rawResp},
+		llm.Message{Role: llm.RoleUser, Content: prompts.BuildJSONRetryPrompt()},
+	))
+	if err == nil {
+		if analysis, err := parser.ParseDebugJSON(retryResp, problem); err == nil {
+			return analysis, nil
+		}
+	}
 
 	return parser.ParseDebugResponse(rawResp, problem)
 }
@@ -0,0 +1,54 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/helmcode/kubectl-ai/pkg/llm"
+	"github.com/helmcode/kubectl-ai/pkg/model"
+	"github.com/helmcode/kubectl-ai/pkg/prompts"
+)
+
+// Conversation continues a debug analysis with follow-up questions, keeping the
+// gathered resources and every previous answer in the LLM's context
+type Conversation struct {
+	llm      llm.LLM
+	messages []llm.Message
+}
+
+// StartConversation seeds a conversation with the debug prompt and the analysis the LLM returned for it.
+// The analysis is replayed as the JSON the prompt asked for, so follow-ups see the root cause, issues
+// and commands, and the assistant turn is never empty, which Claude and Bedrock reject. The prompt
+// is the one the analysis was answered for, so a prompt that had to be trimmed to fit stays trimmed.
+func (a *Analyzer) StartConversation(problem string, resources map[string]interface{}, analysis *model.Analysis) (*Conversation, error) {
+	prompt := a.lastPrompt
+	if prompt == "" {
+		var err error
+		if prompt, err = a.BuildPrompt(problem, resources); err != nil {
+			return nil, err
+		}
+	}
+	answer, err := json.Marshal(analysis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode analysis: %w", err)
+	}
+
+	return &Conversation{
+		llm:      a.llm,
+		messages: append(a.promptMessages(prompt), llm.Message{Role: llm.RoleAssistant, Content: string(answer)}),
+	}, nil
+}
+
+// Ask sends a follow-up question and returns the answer. The exchange is only added to
+// the history when the LLM answers, so a failed question can simply be asked again.
+func (c *Conversation) Ask(question string) (string, error) {
+	messages := append(c.messages, llm.Message{Role: llm.RoleUser, Content: prompts.BuildFollowUpPrompt(question)})
+
+	answer, err := llm.ChatMessages(c.llm, messages)
+	if err != nil {
+		return "", fmt.Errorf("LLM chat: %w", err)
+	}
+
+	c.messages = append(messages, llm.Message{Role: llm.RoleAssistant, Content: answer})
+	return answer, nil
+}
@@ -0,0 +1,109 @@
+package formatter
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+const (
+	// AnomalySigma is how many standard deviations above the mean a point must be to be an anomaly
+	AnomalySigma = 3.0
+
+	// minAnomalyPoints is the shortest series checked. With fewer points a single spike can't
+	// reach 3σ, and the deviation says little anyway.
+	minAnomalyPoints = 12
+
+	// maxShownAnomalies caps the anomalies listed under a chart
+	maxShownAnomalies = 5
+)
+
+// Anomaly is a run of consecutive points above mean + AnomalySigma standard deviations
+type Anomaly struct {
+	Start time.Time
+	End   time.Time
+	Peak  float64
+	Sigma float64 // How many standard deviations the peak is above the mean
+}
+
+// DetectAnomalies returns the spikes in a series: runs of points more than AnomalySigma
+// standard deviations above its mean. Timestamps are optional; without one per value the
+// anomalies have zero times.
+func DetectAnomalies(values []float64, timestamps []time.Time) []Anomaly {
+	if len(values) < minAnomalyPoints {
+		return nil
+	}
+
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	stddev := math.Sqrt(variance / float64(len(values)))
+	if stddev == 0 {
+		return nil
+	}
+
+	timeAt := func(i int) time.Time {
+		if len(timestamps) != len(values) {
+			return time.Time{}
+		}
+		return timestamps[i]
+	}
+
+	var anomalies []Anomaly
+	threshold := mean + AnomalySigma*stddev
+	for i := 0; i < len(values); i++ {
+		if values[i] <= threshold {
+			continue
+		}
+		anomaly := Anomaly{Start: timeAt(i), End: timeAt(i), Peak: values[i]}
+		for i+1 < len(values) && values[i+1] > threshold {
+			i++
+			anomaly.End = timeAt(i)
+			anomaly.Peak = math.Max(anomaly.Peak, values[i])
+		}
+		anomaly.Sigma = (anomaly.Peak - mean) / stddev
+		anomalies = append(anomalies, anomaly)
+	}
+	return anomalies
+}
+
+// FormatAnomalyTime formats when an anomaly happened, as a single time or a range
+func FormatAnomalyTime(anomaly Anomaly, layout string) string {
+	if anomaly.Start.IsZero() {
+		return "unknown time"
+	}
+	if anomaly.End.Equal(anomaly.Start) {
+		return anomaly.Start.Format(layout)
+	}
+	return anomaly.Start.Format(layout) + " to " + anomaly.End.Format(layout)
+}
+
+// anomalyStatistics lists the anomalies of a series for the statistics block under its chart
+func anomalyStatistics(values []float64, timestamps []time.Time, unit string) string {
+	anomalies := DetectAnomalies(values, timestamps)
+	if len(anomalies) == 0 {
+		return ""
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("  Anomalies (> mean + %.0fσ):\n", AnomalySigma))
+	for i, anomaly := range anomalies {
+		if i == maxShownAnomalies {
+			result.WriteString(color.HiBlackString("    ... and %d more\n", len(anomalies)-maxShownAnomalies))
+			break
+		}
+		result.WriteString(fmt.Sprintf("    ⚡ %s  %s %s\n", FormatAnomalyTime(anomaly, "Jan 2 15:04"),
+			color.RedString("%.2f%s", anomaly.Peak, unit), color.HiBlackString("(%.1fσ)", anomaly.Sigma)))
+	}
+	return result.String()
+}
@@ -0,0 +1,107 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/helmcode/kubectl-ai/pkg/model"
+)
+
+// displayMarkdown renders the analysis as Markdown for runbooks and wikis
+func displayMarkdown(analysis *model.Analysis) {
+	var md strings.Builder
+
+	md.WriteString("# Kubernetes AI Analysis\n\n")
+	if analysis.Problem != "" {
+		fmt.Fprintf(&md, "**Problem:** %s\n\n", analysis.Problem)
+	}
+	fmt.Fprintf(&md, "**Severity:** %s\n\n", strings.ToUpper(analysis.Severity))
+
+	md.WriteString("## Root Cause\n\n")
+	fmt.Fprintf(&md, "%s\n\n", analysis.RootCause)
+
+	if len(analysis.Issues) > 0 {
+		md.WriteString("## Issues\n\n")
+		rows := make([][]string, 0, len(analysis.Issues))
+		for i, issue := range analysis.Issues {
+			rows = append(rows, []string{fmt.Sprint(i + 1), issue.Severity, issue.Component, issue.Description, issue.Evidence})
+		}
+		md.WriteString(MarkdownTable([]string{"#", "Severity", "Component", "Description", "Evidence"}, rows))
+		md.WriteString("\n")
+	}
+
+	if analysis.QuickFix != "" {
+		md.WriteString("## Quick Fix\n\n")
+		md.WriteString(MarkdownCodeBlock("bash", analysis.QuickFix))
+		md.WriteString("\n")
+	}
+
+	if len(analysis.Commands) > 0 {
+		md.WriteString("## Commands\n\n")
+		for i, command := range analysis.Commands {
+			fmt.Fprintf(&md, "%d. %s", i+1, command.Description)
+			if command.Destructive {
+				md.WriteString(" **(destructive, review before running)**")
+			}
+			md.WriteString("\n\n")
+			md.WriteString(MarkdownCodeBlock("bash", command.Command))
+			md.WriteString("\n")
+		}
+	}
+
+	if len(analysis.Suggestions) > 0 {
+		md.WriteString("## Suggestions\n\n")
+		for i, suggestion := range analysis.Suggestions {
+			fmt.Fprintf(&md, "### %d. %s\n\n", i+1, suggestion.Action)
+			if suggestion.Priority != "" {
+				fmt.Fprintf(&md, "**Priority:** %s\n\n", suggestion.Priority)
+			}
+			if suggestion.Command != "" {
+				md.WriteString(MarkdownCodeBlock("bash", suggestion.Command))
+				md.WriteString("\n")
+			}
+			if suggestion.Explanation != "" {
+				fmt.Fprintf(&md, "%s\n\n", suggestion.Explanation)
+			}
+		}
+	}
+
+	if analysis.FullAnalysis != "" {
+		md.WriteString("## Detailed Analysis\n\n")
+		fmt.Fprintf(&md, "%s\n", analysis.FullAnalysis)
+	}
+
+	fmt.Print(md.String())
+}
+
+// MarkdownTable renders a GitHub-flavored Markdown table
+func MarkdownTable(headers []string, rows [][]string) string {
+	var table strings.Builder
+
+	table.WriteString("| " + strings.Join(headers, " | ") + " |\n")
+	table.WriteString("|" + strings.Repeat(" --- |", len(headers)) + "\n")
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = markdownCell(cell)
+		}
+		table.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	return table.String()
+}
+
+// MarkdownCodeBlock wraps code in a fenced block, using a longer fence if the code contains one
+func MarkdownCodeBlock(language, code string) string {
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return fmt.Sprintf("%s%s\n%s\n%s\n", fence, language, strings.TrimRight(code, "\n"), fence)
+}
+
+// markdownCell escapes a value for use in a table cell, which can't contain pipes or line breaks
+func markdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	value = strings.ReplaceAll(value, "\r\n", "<br>")
+	return strings.ReplaceAll(value, "\n", "<br>")
+}
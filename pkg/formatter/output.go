@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -20,6 +21,8 @@ func DisplayResults(analysis *model.Analysis, format string) error {
 		return displayJSON(analysis)
 	case "yaml":
 		return displayYAML(analysis)
+	case "markdown", "md":
+		displayMarkdown(analysis)
 	case "human":
 		fallthrough
 	default:
@@ -80,6 +83,17 @@ func displayHuman(analysis *model.Analysis) {
 		fmt.Printf("   %s\n\n", color.GreenString(analysis.QuickFix))
 	}
 
+	if len(analysis.Commands) > 0 {
+		green.Println("🛠️  COMMANDS:")
+		for i, command := range analysis.Commands {
+			fmt.Printf("   %d. %s\n", i+1, command.Description)
+			if command.Destructive {
+				fmt.Printf("      %s\n", color.RedString("⚠️  Destructive, review before running"))
+			}
+			fmt.Printf("      %s\n\n", color.GreenString(command.Command))
+		}
+	}
+
 	if len(analysis.Suggestions) > 0 {
 		cyan.Println("💡 SUGGESTIONS:")
 		for i, suggestion := range analysis.Suggestions {
@@ -103,7 +117,7 @@ func displayHuman(analysis *model.Analysis) {
 		fmt.Println()
 	}
 	fmt.Println(strings.Repeat("─", 80))
-	fmt.Printf("💡 %s\n", color.HiBlackString("Run with -o json or -o yaml for machine-readable output"))
+	fmt.Printf("💡 %s\n", color.HiBlackString("Run with -o json or -o yaml for machine-readable output, or -o markdown for runbooks"))
 }
 
 func getSeverityColor(severity string) *color.Color {
@@ -288,16 +302,37 @@ func CreateEnhancedLineChart(values []float64, timestamps []time.Time, title str
 
 	avg := sum / float64(len(values))
 
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
 	result.WriteString("\n")
 	result.WriteString(color.HiBlackString("Statistics:\n"))
 	result.WriteString(fmt.Sprintf("  Average: %s\n", color.YellowString("%.2f%s", avg, unit)))
 	result.WriteString(fmt.Sprintf("  Minimum: %s\n", color.GreenString("%.2f%s", min, unit)))
+	result.WriteString(fmt.Sprintf("  P50:     %s\n", color.YellowString("%.2f%s", Percentile(sorted, 50), unit)))
+	result.WriteString(fmt.Sprintf("  P95:     %s\n", color.YellowString("%.2f%s", Percentile(sorted, 95), unit)))
+	result.WriteString(fmt.Sprintf("  P99:     %s\n", color.YellowString("%.2f%s", Percentile(sorted, 99), unit)))
 	result.WriteString(fmt.Sprintf("  Maximum: %s\n", color.RedString("%.2f%s", max, unit)))
+	result.WriteString(anomalyStatistics(values, timestamps, unit))
 	result.WriteString("\n")
 
 	return result.String()
 }
 
+// Percentile returns the p-th percentile of sorted values, interpolating linearly between ranks
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(rank)
+	if lower >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	fraction := rank - float64(lower)
+	return sorted[lower] + fraction*(sorted[lower+1]-sorted[lower])
+}
+
 // CreateReplicaBarChart creates a bar chart for replica scaling events
 func CreateReplicaBarChart(replicas []int, timestamps []time.Time, title string) string {
 	if len(replicas) == 0 {
@@ -374,6 +409,76 @@ func CreateReplicaBarChart(replicas []int, timestamps []time.Time, title string)
 	return result.String()
 }
 
+// CreateDaemonSetChart plots desired, ready and available daemon pods on the same chart
+func CreateDaemonSetChart(desired, ready, available []float64, timestamps []time.Time, title string) string {
+	if len(desired) == 0 {
+		return ""
+	}
+
+	var result strings.Builder
+
+	// Title
+	green := color.New(color.FgGreen, color.Bold)
+	result.WriteString(green.Sprintf("🖥️  %s\n", title))
+	result.WriteString(strings.Repeat("─", 60) + "\n")
+
+	series := [][]float64{desired}
+	legends := []string{"desired"}
+	colors := []asciigraph.AnsiColor{asciigraph.Blue}
+	if len(ready) > 0 {
+		series = append(series, ready)
+		legends = append(legends, "ready")
+		colors = append(colors, asciigraph.Green)
+	}
+	if len(available) > 0 {
+		series = append(series, available)
+		legends = append(legends, "available")
+		colors = append(colors, asciigraph.Yellow)
+	}
+
+	options := []asciigraph.Option{
+		asciigraph.Height(8),
+		asciigraph.Width(60),
+		asciigraph.SeriesLegends(legends...),
+		asciigraph.Caption("Daemon Pods Over Time"),
+	}
+	if !color.NoColor {
+		options = append(options, asciigraph.SeriesColors(colors...))
+	}
+	graph := asciigraph.PlotMany(series, options...)
+	result.WriteString(graph + "\n")
+
+	// Add enhanced X-axis
+	if len(timestamps) > 0 {
+		result.WriteString("\n")
+		result.WriteString(createEnhancedXAxis(timestamps, "replica", 60))
+	}
+
+	// Coverage summary based on the latest values
+	result.WriteString("\n")
+	result.WriteString(color.HiBlackString("Node Coverage:\n"))
+	currentDesired := desired[len(desired)-1]
+	result.WriteString(fmt.Sprintf("  Desired: %s\n", color.BlueString("%.0f", currentDesired)))
+	if len(ready) > 0 {
+		result.WriteString(fmt.Sprintf("  Ready: %s\n", coverageColor(ready[len(ready)-1], currentDesired)))
+	}
+	if len(available) > 0 {
+		result.WriteString(fmt.Sprintf("  Available: %s\n", coverageColor(available[len(available)-1], currentDesired)))
+	}
+
+	result.WriteString("\n")
+
+	return result.String()
+}
+
+// coverageColor highlights pod counts that fall short of the desired count
+func coverageColor(value, desired float64) string {
+	if value < desired {
+		return color.RedString("%.0f", value)
+	}
+	return color.GreenString("%.0f", value)
+}
+
 // CreateMetricsSummaryDisplay creates a clean summary display for metrics
 func CreateMetricsSummaryDisplay(cpuValues []float64, memoryValues []float64, cpuTimestamps []time.Time, memoryTimestamps []time.Time, duration string) string {
 	var result strings.Builder
@@ -531,3 +636,139 @@ func createEnhancedXAxis(timestamps []time.Time, duration string, width int) str
 
 	return result.String()
 }
+
+// ansiEscape matches SGR color sequences so they don't count toward column width
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// visibleWidth approximates the terminal width of a line, ignoring color codes and
+// counting emoji as two columns
+func visibleWidth(line string) int {
+	width := 0
+	var prev rune
+	for _, r := range ansiEscape.ReplaceAllString(line, "") {
+		switch {
+		case r == '\uFE0F':
+			// Emoji presentation selector widens a text symbol such as ⚠ to two columns
+			if prev < 0x1F000 {
+				width++
+			}
+		case r >= 0x1F000:
+			width += 2
+		default:
+			width++
+		}
+		prev = r
+	}
+	return width
+}
+
+// SideBySide renders two blocks of text in columns, padding the left block to its widest line
+func SideBySide(left, right string) string {
+	leftLines := strings.Split(strings.TrimRight(left, "\n"), "\n")
+	rightLines := strings.Split(strings.TrimRight(right, "\n"), "\n")
+
+	columnWidth := 0
+	for _, line := range leftLines {
+		if w := visibleWidth(line); w > columnWidth {
+			columnWidth = w
+		}
+	}
+
+	var result strings.Builder
+	for i := 0; i < len(leftLines) || i < len(rightLines); i++ {
+		var l, r string
+		if i < len(leftLines) {
+			l = leftLines[i]
+		}
+		if i < len(rightLines) {
+			r = rightLines[i]
+		}
+		result.WriteString(l)
+		result.WriteString(strings.Repeat(" ", columnWidth-visibleWidth(l)+4))
+		result.WriteString(r)
+		result.WriteString("\n")
+	}
+	return result.String()
+}
+
+// DeltaRow is one before/after measurement in a delta table
+type DeltaRow struct {
+	Name   string
+	Unit   string
+	Before float64
+	After  float64
+}
+
+// CreateDeltaTable renders before/after values with their percentage change
+func CreateDeltaTable(title, beforeLabel, afterLabel string, rows []DeltaRow) string {
+	var result strings.Builder
+
+	cyan := color.New(color.FgCyan, color.Bold)
+	result.WriteString(cyan.Sprintf("📐 %s\n", title))
+	result.WriteString(strings.Repeat("─", 60) + "\n")
+	result.WriteString(fmt.Sprintf("%-20s %14s %14s %9s\n", "Metric", beforeLabel, afterLabel, "Change"))
+
+	for _, row := range rows {
+		before := fmt.Sprintf("%.2f%s", row.Before, row.Unit)
+		after := fmt.Sprintf("%.2f%s", row.After, row.Unit)
+		result.WriteString(fmt.Sprintf("%-20s %14s %14s %9s\n", row.Name, before, after, formatChange(row.Before, row.After)))
+	}
+	result.WriteString("\n")
+
+	return result.String()
+}
+
+// formatChange returns the percentage change from before to after, padded for table alignment
+func formatChange(before, after float64) string {
+	if before == 0 {
+		if after == 0 {
+			return "0.0%"
+		}
+		return "n/a"
+	}
+
+	change := (after - before) / before * 100
+	text := fmt.Sprintf("%+.1f%%", change)
+	switch {
+	case change > 0:
+		return color.YellowString("%9s", "↑"+text)
+	case change < 0:
+		return color.CyanString("%9s", "↓"+text)
+	default:
+		return text
+	}
+}
+
+// Table renders rows as plain, fixed-width columns separated by three spaces like kubectl's
+// tables, without colors so the output stays easy to grep and cut
+func Table(headers []string, rows [][]string) string {
+	widths := make([]int, len(headers))
+	for i, header := range headers {
+		widths[i] = len(header)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if i < len(widths) && len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+
+	var table strings.Builder
+	writeRow := func(cells []string) {
+		var line strings.Builder
+		for i, cell := range cells {
+			if i == len(cells)-1 {
+				line.WriteString(cell)
+				break
+			}
+			line.WriteString(fmt.Sprintf("%-*s   ", widths[i], cell))
+		}
+		table.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+	}
+	writeRow(headers)
+	for _, row := range rows {
+		writeRow(row)
+	}
+	return table.String()
+}
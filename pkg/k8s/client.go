@@ -3,14 +3,26 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"golang.org/x/sync/errgroup"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
@@ -24,21 +36,75 @@ type Client struct {
 	discovery discovery.DiscoveryInterface
 	config    *rest.Config
 
+	// Name of the kubeconfig context in use, "in-cluster" when running in a pod
+	contextName string
+
 	// Cache for discovered resources
 	resourceCache map[string]*metav1.APIResource
 	gvrCache      map[string]schema.GroupVersionResource
 	cacheMutex    sync.RWMutex
+
+	// Discovery results persisted across invocations ("" dir disables it)
+	discoveryCacheDir string
+	discoveryCacheTTL time.Duration
+	refreshDiscovery  bool
+
+	// Number of log lines to gather per related pod container (0 disables logs)
+	logTailLines int64
+
+	// Gather Normal events too, not only Warnings
+	allEvents bool
+
+	// Maximum number of concurrent API calls while gathering resources
+	concurrency int
+
+	// Maximum number of objects gathered with all or a selector (0 disables the limit)
+	maxResources int
+
+	// Keys whose values are masked before gathered resources are returned (nil disables it)
+	redactPattern *regexp.Regexp
 }
 
+const (
+	// DefaultConcurrency is the default number of concurrent API calls while gathering
+	DefaultConcurrency = 8
+
+	// DefaultMaxResources caps the objects gathered for --all or -l, keeping prompts affordable
+	DefaultMaxResources = 200
+
+	// DefaultLogTailLines is the number of log lines gathered per container when logs are enabled
+	DefaultLogTailLines = 50
+
+	// Limits that keep gathered logs within a reasonable prompt size
+	maxLogPods           = 5
+	maxLogBytesPerStream = 4000
+
+	// podSuffixPattern matches the random suffix Kubernetes appends to generated pod names
+	podSuffixPattern = "[a-z0-9]{5}"
+
+	// maxListItems caps each list call across all namespaces so gathering stays bounded on huge clusters
+	maxListItems = 500
+
+	// maxEvents caps the events sent to the LLM to the most recent ones
+	maxEvents = 30
+
+	// eventPageSize is how many events each list call returns while paging through a namespace
+	eventPageSize = 500
+)
+
 // NewClient creates a new Kubernetes client with discovery capabilities
-// If contextName is not empty, it will be used instead of the current context in kubeconfig.
+// If contextName is not empty, it will be used instead of the current context in kubeconfig, even
+// when running in a pod.
 func NewClient(kubeconfig string, contextName string) (*Client, error) {
 	var config *rest.Config
 	var err error
 
-	// Try in-cluster config first
-	config, err = rest.InClusterConfig()
-	if err != nil {
+	// Try in-cluster config first, unless a context was asked for
+	resolvedContext := "in-cluster"
+	if contextName == "" {
+		config, _ = rest.InClusterConfig()
+	}
+	if config == nil {
 		// Fall back to kubeconfig with optional context override
 		loadingRules := &clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig}
 		overrides := &clientcmd.ConfigOverrides{}
@@ -50,6 +116,18 @@ func NewClient(kubeconfig string, contextName string) (*Client, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to create config: %w", err)
 		}
+		resolvedContext = contextName
+		if resolvedContext == "" {
+			if rawConfig, err := cfg.RawConfig(); err == nil {
+				resolvedContext = rawConfig.CurrentContext
+			}
+		}
+	}
+
+	if VerboseOutput != nil {
+		config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return &tracingTransport{next: rt}
+		})
 	}
 
 	// Create clientset
@@ -75,8 +153,14 @@ func NewClient(kubeconfig string, contextName string) (*Client, error) {
 		dynamic:       dynamicClient,
 		discovery:     discoveryClient,
 		config:        config,
+		contextName:   resolvedContext,
 		resourceCache: make(map[string]*metav1.APIResource),
 		gvrCache:      make(map[string]schema.GroupVersionResource),
+		concurrency:   DefaultConcurrency,
+		redactPattern: regexp.MustCompile(DefaultRedactPattern),
+
+		discoveryCacheDir: DefaultDiscoveryCacheDir(),
+		discoveryCacheTTL: DefaultDiscoveryCacheTTL,
 	}, nil
 }
 
@@ -85,8 +169,47 @@ func (c *Client) GetClientset() *kubernetes.Clientset {
 	return c.clientset
 }
 
+// GetContextName returns the kubeconfig context the client connects with
+func (c *Client) GetContextName() string {
+	return c.contextName
+}
+
+// GetServer returns the URL of the API server the client talks to
+func (c *Client) GetServer() string {
+	return c.config.Host
+}
+
+// GetDynamicClient returns the dynamic client for querying CRDs
+func (c *Client) GetDynamicClient() dynamic.Interface {
+	return c.dynamic
+}
+
+// SetLogTailLines enables gathering the last n log lines of related pods (0 disables it)
+func (c *Client) SetLogTailLines(n int64) {
+	c.logTailLines = n
+}
+
+// SetIncludeNormalEvents gathers Normal events along with Warnings
+func (c *Client) SetIncludeNormalEvents(include bool) {
+	c.allEvents = include
+}
+
+// SetConcurrency sets the maximum number of concurrent API calls while gathering resources
+func (c *Client) SetConcurrency(n int) {
+	if n < 1 {
+		n = 1
+	}
+	c.concurrency = n
+}
+
+// SetMaxResources caps how many objects are gathered with all or a selector (0 disables the cap).
+// Quotas and limit ranges don't count against it.
+func (c *Client) SetMaxResources(n int) {
+	c.maxResources = n
+}
+
 // discoverResource finds any resource type in the cluster
-func (c *Client) discoverResource(resourceType string) (*metav1.APIResource, schema.GroupVersionResource, error) {
+func (c *Client) discoverResource(ctx context.Context, resourceType string) (*metav1.APIResource, schema.GroupVersionResource, error) {
 	// Check cache first
 	c.cacheMutex.RLock()
 	if apiResource, ok := c.resourceCache[resourceType]; ok {
@@ -96,17 +219,67 @@ func (c *Client) discoverResource(resourceType string) (*metav1.APIResource, sch
 	}
 	c.cacheMutex.RUnlock()
 
-	// Get all available resources
-	resourceList, err := c.discovery.ServerPreferredResources()
+	// Persisted discovery results are tried first; a type missing from them may be a
+	// newly installed CRD, so fall back to live discovery before giving up
+	if resourceList := c.loadDiscoveryCache(); resourceList != nil {
+		if apiResource, gvr, ok := c.findResource(resourceList, resourceType); ok {
+			return apiResource, gvr, nil
+		}
+	}
+
+	resourceList, err := c.serverPreferredResources(ctx)
+	if err != nil {
+		return nil, schema.GroupVersionResource{}, err
+	}
+	if apiResource, gvr, ok := c.findResource(resourceList, resourceType); ok {
+		return apiResource, gvr, nil
+	}
+
+	return nil, schema.GroupVersionResource{}, fmt.Errorf("resource type '%s' not found in cluster", resourceType)
+}
+
+// serverPreferredResources runs live discovery and persists the results
+func (c *Client) serverPreferredResources(ctx context.Context) ([]*metav1.APIResourceList, error) {
+	// Discovery doesn't accept a context, so run it in the background and stop
+	// waiting if the context is cancelled.
+	type discoveryResult struct {
+		resources []*metav1.APIResourceList
+		err       error
+	}
+	discovered := make(chan discoveryResult, 1)
+	go func() {
+		resources, err := c.discovery.ServerPreferredResources()
+		discovered <- discoveryResult{resources: resources, err: err}
+	}()
+
+	var resourceList []*metav1.APIResourceList
+	var err error
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-discovered:
+		resourceList, err = res.resources, res.err
+	}
 	if err != nil {
 		// Even with errors, we might have partial results
 		if resourceList == nil {
-			return nil, schema.GroupVersionResource{}, fmt.Errorf("failed to discover resources: %w", err)
+			return nil, fmt.Errorf("failed to discover resources: %w", err)
 		}
+		// Don't persist partial results, the next run should retry the failed groups
+		return resourceList, nil
 	}
 
+	c.saveDiscoveryCache(resourceList)
+	return resourceList, nil
+}
+
+// findResource searches discovery results for a resource type and caches the match in memory
+func (c *Client) findResource(resourceList []*metav1.APIResourceList, resourceType string) (*metav1.APIResource, schema.GroupVersionResource, bool) {
 	// Search through all API groups
 	for _, group := range resourceList {
+		if group == nil {
+			continue
+		}
 		gv, err := schema.ParseGroupVersion(group.GroupVersion)
 		if err != nil {
 			continue
@@ -131,58 +304,158 @@ func (c *Client) discoverResource(resourceType string) (*metav1.APIResource, sch
 				c.gvrCache[resourceType] = gvr
 				c.cacheMutex.Unlock()
 
-				return &resource, gvr, nil
+				return &resource, gvr, true
 			}
 		}
 	}
 
-	return nil, schema.GroupVersionResource{}, fmt.Errorf("resource type '%s' not found in cluster", resourceType)
+	return nil, schema.GroupVersionResource{}, false
 }
 
-// GatherResources collects the specified Kubernetes resources
-func (c *Client) GatherResources(namespace string, resources []string, all bool) (map[string]interface{}, error) {
+// GatherResources collects the specified Kubernetes resources. A non-empty label selector lists
+// the matching resources like all does, instead of gathering resources by name.
+func (c *Client) GatherResources(ctx context.Context, namespace string, resources []string, all bool, selector string) (map[string]interface{}, error) {
 	result := make(map[string]interface{})
 
-	if all {
-		// Get all resources in namespace
-		if err := c.gatherAllResources(namespace, result); err != nil {
+	if all || selector != "" {
+		// Get all resources in namespace, optionally only those matching the selector
+		if err := c.gatherAllResources(ctx, namespace, selector, result); err != nil {
 			return nil, err
 		}
 	} else {
-		// Get specific resources
+		// Get specific resources in parallel, each into its own map to avoid sharing
+		var mu sync.Mutex
+		g := new(errgroup.Group)
+		g.SetLimit(c.concurrency)
+
 		for _, resource := range resources {
-			if err := c.gatherResource(namespace, resource, result); err != nil {
-				// Don't fail completely if one resource fails
-				fmt.Printf("Warning: failed to gather %s: %v\n", resource, err)
-			}
+			g.Go(func() error {
+				if ctx.Err() != nil {
+					return nil
+				}
+				gathered := make(map[string]interface{})
+				if err := c.gatherResource(ctx, namespace, resource, gathered); err != nil {
+					// Don't fail completely if one resource fails
+					if ctx.Err() == nil {
+						fmt.Fprintf(os.Stderr, "Warning: failed to gather %s: %v\n", resource, err)
+					}
+					return nil
+				}
+
+				mu.Lock()
+				for key, value := range gathered {
+					result[key] = value
+				}
+				mu.Unlock()
+				return nil
+			})
 		}
+		g.Wait()
+
+		// Quotas and limit ranges apply to every workload in the namespace
+		c.gatherNamespacePolicies(ctx, namespace, result)
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	// Flag quotas close to their limit so a rejected pod creation can be connected to them
+	if warnings := quotaHeadroom(result); len(warnings) > 0 {
+		result[QuotaHeadroomKey] = warnings
+	}
+
+	// Call out OOM kills and crashes, which are easy to miss deep in a pod's container statuses
+	if terminations := containerTerminations(result); len(terminations) > 0 {
+		result[TerminationsKey] = terminations
+	}
+
+	// Same for crash loops and restart storms, which only show up as a restart count
+	if restarts := containerRestarts(result, time.Now()); len(restarts) > 0 {
+		result[RestartsKey] = restarts
 	}
 
 	// Always add events
-	events, err := c.getEvents(namespace)
+	events, err := c.getEvents(ctx, namespace)
 	if err == nil && len(events.Items) > 0 {
 		result["events"] = events
 	}
 
+	// Drop noise and mask sensitive values before anything leaves the client
+	for _, obj := range result {
+		sanitizeObject(obj)
+		c.Redact(obj)
+	}
+
 	return result, nil
 }
 
-func (c *Client) gatherResource(namespace, resource string, result map[string]interface{}) error {
+// splitResource parses type/name or namespace/type/name into its namespace, lowercased type and name
+func splitResource(namespace, resource string) (string, string, string, error) {
 	parts := strings.Split(resource, "/")
+
+	// namespace/type/name selects the namespace explicitly, which is required across all namespaces
+	if len(parts) == 3 {
+		namespace = parts[0]
+		parts = parts[1:]
+	}
 	if len(parts) != 2 {
-		return fmt.Errorf("invalid resource format: %s (expected type/name)", resource)
+		return "", "", "", fmt.Errorf("invalid resource format: %s (expected type/name or namespace/type/name)", resource)
 	}
+	if namespace == metav1.NamespaceAll {
+		return "", "", "", fmt.Errorf("resource %s needs a namespace when gathering across all namespaces (use namespace/type/name)", resource)
+	}
+
+	return namespace, strings.ToLower(parts[0]), parts[1], nil
+}
 
-	resourceType := strings.ToLower(parts[0])
-	resourceName := parts[1]
+// CheckResource verifies that a resource exists with a single Get and nothing else, so a typo
+// fails before any expensive work
+func (c *Client) CheckResource(ctx context.Context, namespace, resource string) error {
+	namespace, resourceType, resourceName, err := splitResource(namespace, resource)
+	if err != nil {
+		return err
+	}
+
+	apiResource, gvr, err := c.discoverResource(ctx, resourceType)
+	if err != nil {
+		return fmt.Errorf("unknown resource type %q in %s: %w", resourceType, resource, err)
+	}
+
+	kind := apiResource.SingularName
+	if kind == "" {
+		kind = resourceType
+	}
+	if apiResource.Namespaced {
+		_, err = c.dynamic.Resource(gvr).Namespace(namespace).Get(ctx, resourceName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("%s/%s not found in namespace %s", kind, resourceName, namespace)
+		}
+	} else {
+		_, err = c.dynamic.Resource(gvr).Get(ctx, resourceName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("%s/%s not found", kind, resourceName)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get %s/%s: %w", kind, resourceName, err)
+	}
+	return nil
+}
+
+func (c *Client) gatherResource(ctx context.Context, namespace, resource string, result map[string]interface{}) error {
+	namespace, resourceType, resourceName, err := splitResource(namespace, resource)
+	if err != nil {
+		return err
+	}
 
 	// Try native resources first (for performance)
-	if err := c.gatherNativeResource(namespace, resourceType, resourceName, resource, result); err == nil {
+	if err := c.gatherNativeResource(ctx, namespace, resourceType, resourceName, resource, result); err == nil {
 		return nil
 	}
 
 	// If not a native resource, use discovery
-	apiResource, gvr, err := c.discoverResource(resourceType)
+	apiResource, gvr, err := c.discoverResource(ctx, resourceType)
 	if err != nil {
 		return fmt.Errorf("failed to discover resource type %s: %w", resourceType, err)
 	}
@@ -190,9 +463,9 @@ func (c *Client) gatherResource(namespace, resource string, result map[string]in
 	// Get the resource using dynamic client
 	var obj *unstructured.Unstructured
 	if apiResource.Namespaced {
-		obj, err = c.dynamic.Resource(gvr).Namespace(namespace).Get(context.TODO(), resourceName, metav1.GetOptions{})
+		obj, err = c.dynamic.Resource(gvr).Namespace(namespace).Get(ctx, resourceName, metav1.GetOptions{})
 	} else {
-		obj, err = c.dynamic.Resource(gvr).Get(context.TODO(), resourceName, metav1.GetOptions{})
+		obj, err = c.dynamic.Resource(gvr).Get(ctx, resourceName, metav1.GetOptions{})
 	}
 
 	if err != nil {
@@ -201,52 +474,69 @@ func (c *Client) gatherResource(namespace, resource string, result map[string]in
 
 	result[resource] = obj
 
-	// If it's a workload, try to get related pods
-	if hasSelector(obj) {
-		pods, err := c.getPodsForWorkload(namespace, obj)
-		if err == nil && len(pods.Items) > 0 {
-			result[resource+"_pods"] = pods
-		}
+	// If it's a workload, including known CRDs like Argo Rollouts, try to get related pods
+	pods, err := c.getPodsForWorkload(ctx, namespace, obj)
+	if err == nil && len(pods.Items) > 0 {
+		result[resource+"_pods"] = pods
+		c.addPodLogs(ctx, namespace, pods.Items, resource, result)
 	}
 
 	return nil
 }
 
 // gatherNativeResource handles built-in Kubernetes resources with typed clients
-func (c *Client) gatherNativeResource(namespace, resourceType, resourceName, fullResource string, result map[string]interface{}) error {
+func (c *Client) gatherNativeResource(ctx context.Context, namespace, resourceType, resourceName, fullResource string, result map[string]interface{}) error {
 	switch resourceType {
 	case "deployment", "deploy", "deployments":
-		deploy, err := c.clientset.AppsV1().Deployments(namespace).Get(context.TODO(), resourceName, metav1.GetOptions{})
+		deploy, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, resourceName, metav1.GetOptions{})
 		if err != nil {
 			return err
 		}
 		result[fullResource] = deploy
 
 		// Get related pods
-		pods, err := c.getPodsForDeployment(namespace, deploy)
+		pods, err := c.getPodsForDeployment(ctx, namespace, deploy)
 		if err == nil {
 			result[fullResource+"_pods"] = pods
+			c.addPodLogs(ctx, namespace, pods.Items, fullResource, result)
+		}
+		c.addPVCs(ctx, namespace, claimNames(deploy.Spec.Template.Spec), fullResource, result)
+
+		// Include the rollout history so a symptom can be tied to the revision that introduced it
+		history, err := c.deploymentHistory(ctx, namespace, deploy)
+		if err == nil && len(history) > 0 {
+			result[fullResource+"_history"] = history
 		}
 		return nil
 
 	case "pod", "pods", "po":
-		pod, err := c.clientset.CoreV1().Pods(namespace).Get(context.TODO(), resourceName, metav1.GetOptions{})
+		pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, resourceName, metav1.GetOptions{})
 		if err != nil {
 			return err
 		}
 		result[fullResource] = pod
+		c.addPodLogs(ctx, namespace, []corev1.Pod{*pod}, fullResource, result)
+		c.addPVCs(ctx, namespace, claimNames(pod.Spec), fullResource, result)
 		return nil
 
 	case "service", "services", "svc":
-		service, err := c.clientset.CoreV1().Services(namespace).Get(context.TODO(), resourceName, metav1.GetOptions{})
+		service, err := c.clientset.CoreV1().Services(namespace).Get(ctx, resourceName, metav1.GetOptions{})
 		if err != nil {
 			return err
 		}
 		result[fullResource] = service
+
+		// Show whether the service has ready backends, ExternalName services have none by design
+		if service.Spec.Type != corev1.ServiceTypeExternalName {
+			endpoints, err := c.serviceEndpoints(ctx, namespace, service.Name)
+			if err == nil {
+				result[fullResource+"_endpoints"] = endpoints
+			}
+		}
 		return nil
 
 	case "configmap", "configmaps", "cm":
-		cm, err := c.clientset.CoreV1().ConfigMaps(namespace).Get(context.TODO(), resourceName, metav1.GetOptions{})
+		cm, err := c.clientset.CoreV1().ConfigMaps(namespace).Get(ctx, resourceName, metav1.GetOptions{})
 		if err != nil {
 			return err
 		}
@@ -254,7 +544,7 @@ func (c *Client) gatherNativeResource(namespace, resourceType, resourceName, ful
 		return nil
 
 	case "secret", "secrets":
-		secret, err := c.clientset.CoreV1().Secrets(namespace).Get(context.TODO(), resourceName, metav1.GetOptions{})
+		secret, err := c.clientset.CoreV1().Secrets(namespace).Get(ctx, resourceName, metav1.GetOptions{})
 		if err != nil {
 			return err
 		}
@@ -264,120 +554,711 @@ func (c *Client) gatherNativeResource(namespace, resourceType, resourceName, ful
 		return nil
 
 	case "statefulset", "statefulsets", "sts":
-		sts, err := c.clientset.AppsV1().StatefulSets(namespace).Get(context.TODO(), resourceName, metav1.GetOptions{})
+		sts, err := c.clientset.AppsV1().StatefulSets(namespace).Get(ctx, resourceName, metav1.GetOptions{})
 		if err != nil {
 			return err
 		}
 		result[fullResource] = sts
+
+		// Get related pods
+		pods, err := c.getPodsForStatefulSet(ctx, namespace, sts)
+		if err == nil {
+			result[fullResource+"_pods"] = pods
+			c.addPodLogs(ctx, namespace, pods.Items, fullResource, result)
+		}
+		claims := append(claimNames(sts.Spec.Template.Spec), statefulSetClaimNames(sts)...)
+		c.addPVCs(ctx, namespace, claims, fullResource, result)
 		return nil
 
 	case "daemonset", "daemonsets", "ds":
-		ds, err := c.clientset.AppsV1().DaemonSets(namespace).Get(context.TODO(), resourceName, metav1.GetOptions{})
+		ds, err := c.clientset.AppsV1().DaemonSets(namespace).Get(ctx, resourceName, metav1.GetOptions{})
 		if err != nil {
 			return err
 		}
 		result[fullResource] = ds
+
+		// Get related pods
+		pods, err := c.getPodsForDaemonSet(ctx, namespace, ds)
+		if err == nil {
+			result[fullResource+"_pods"] = pods
+			c.addPodLogs(ctx, namespace, pods.Items, fullResource, result)
+		}
+		c.addPVCs(ctx, namespace, claimNames(ds.Spec.Template.Spec), fullResource, result)
+		return nil
+
+	case "job", "jobs":
+		job, err := c.clientset.BatchV1().Jobs(namespace).Get(ctx, resourceName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		result[fullResource] = job
+		result[fullResource+"_status"] = jobStatus(job)
+
+		// Get related pods, failed attempts included, so the LLM can see why they failed
+		pods, err := c.getPodsForJob(ctx, namespace, job)
+		if err == nil {
+			result[fullResource+"_pods"] = pods
+			c.addPodLogs(ctx, namespace, pods.Items, fullResource, result)
+		}
+		c.addPVCs(ctx, namespace, claimNames(job.Spec.Template.Spec), fullResource, result)
+		return nil
+
+	case "cronjob", "cronjobs", "cj":
+		cronJob, err := c.clientset.BatchV1().CronJobs(namespace).Get(ctx, resourceName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		result[fullResource] = cronJob
+
+		// Summarize recent runs and include the pods of the latest one
+		status, jobs, err := c.cronJobStatus(ctx, namespace, cronJob)
+		if err == nil {
+			result[fullResource+"_status"] = status
+			if len(jobs) > 0 {
+				pods, err := c.getPodsForJob(ctx, namespace, &jobs[0])
+				if err == nil {
+					result[fullResource+"_pods"] = pods
+					c.addPodLogs(ctx, namespace, pods.Items, fullResource, result)
+				}
+			}
+		}
 		return nil
 
 	case "ingress", "ingresses", "ing":
-		ing, err := c.clientset.NetworkingV1().Ingresses(namespace).Get(context.TODO(), resourceName, metav1.GetOptions{})
+		ing, err := c.clientset.NetworkingV1().Ingresses(namespace).Get(ctx, resourceName, metav1.GetOptions{})
 		if err != nil {
 			return err
 		}
 		result[fullResource] = ing
+
+		// Trace each backend through its Service and endpoints to the pods, so a broken hop is explicit
+		result[fullResource+"_path"] = c.ingressPath(ctx, namespace, ing)
+		return nil
+
+	case "netpol", "netpols", "networkpolicy", "networkpolicies":
+		policy, err := c.clientset.NetworkingV1().NetworkPolicies(namespace).Get(ctx, resourceName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		result[fullResource] = policy
+
+		// List the pods the policy applies to, so its effect doesn't have to be inferred from labels
+		selected, err := c.networkPolicyPods(ctx, namespace, policy)
+		if err == nil {
+			result[fullResource+"_selected_pods"] = selected
+		}
 		return nil
 
 	case "hpa", "horizontalpodautoscaler", "horizontalpodautoscalers":
-		hpa, err := c.clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).Get(context.TODO(), resourceName, metav1.GetOptions{})
+		hpa, err := c.clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).Get(ctx, resourceName, metav1.GetOptions{})
 		if err != nil {
 			return err
 		}
 		result[fullResource] = hpa
 		return nil
 
+	case "pvc", "pvcs", "persistentvolumeclaim", "persistentvolumeclaims":
+		pvc, err := c.clientset.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, resourceName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		result[fullResource] = pvc
+
+		// Include the StorageClass, which decides how and when the claim gets bound
+		if pvc.Spec.StorageClassName != nil {
+			if sc := c.storageClassSummary(ctx, *pvc.Spec.StorageClassName); sc != nil {
+				result[fullResource+"_storageclass"] = sc
+			}
+		}
+		return nil
+
+	case "quota", "resourcequota", "resourcequotas":
+		quota, err := c.clientset.CoreV1().ResourceQuotas(namespace).Get(ctx, resourceName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		result[fullResource] = quota
+		return nil
+
+	case "limits", "limitrange", "limitranges":
+		limitRange, err := c.clientset.CoreV1().LimitRanges(namespace).Get(ctx, resourceName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		result[fullResource] = limitRange
+		return nil
+
+	case "pdb", "pdbs", "poddisruptionbudget", "poddisruptionbudgets":
+		pdb, err := c.clientset.PolicyV1().PodDisruptionBudgets(namespace).Get(ctx, resourceName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		result[fullResource] = pdb
+
+		// Summarize whether the budget currently allows evictions
+		budget, err := c.pdbBudget(ctx, namespace, pdb)
+		if err == nil {
+			result[fullResource+"_budget"] = budget
+		}
+		return nil
+
 	default:
 		return fmt.Errorf("not a native resource")
 	}
 }
 
-func (c *Client) gatherAllResources(namespace string, result map[string]interface{}) error {
-	// Get deployments
-	deployments, err := c.clientset.AppsV1().Deployments(namespace).List(context.TODO(), metav1.ListOptions{})
-	if err == nil && len(deployments.Items) > 0 {
-		result["deployments"] = deployments
+// gatherAllResources lists the common resource kinds of a namespace in parallel.
+// With metav1.NamespaceAll the lists span every namespace and are stored per namespace as "<namespace>/<kind>".
+// A label selector filters every kind except quotas and limit ranges, which apply to the whole namespace.
+func (c *Client) gatherAllResources(ctx context.Context, namespace, selector string, result map[string]interface{}) error {
+	var mu sync.Mutex
+	store := func(key string, value interface{}) {
+		mu.Lock()
+		result[key] = value
+		mu.Unlock()
 	}
 
-	// Get pods
-	pods, err := c.clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
-	if err == nil && len(pods.Items) > 0 {
-		result["pods"] = pods
+	listers := []struct {
+		kind          string
+		namespaceWide bool
+		list          func(opts metav1.ListOptions) (runtime.Object, error)
+	}{
+		{"deployments", false, func(opts metav1.ListOptions) (runtime.Object, error) {
+			return c.clientset.AppsV1().Deployments(namespace).List(ctx, opts)
+		}},
+		{"pods", false, func(opts metav1.ListOptions) (runtime.Object, error) {
+			return c.clientset.CoreV1().Pods(namespace).List(ctx, opts)
+		}},
+		{"services", false, func(opts metav1.ListOptions) (runtime.Object, error) {
+			return c.clientset.CoreV1().Services(namespace).List(ctx, opts)
+		}},
+		{"configmaps", false, func(opts metav1.ListOptions) (runtime.Object, error) {
+			return c.clientset.CoreV1().ConfigMaps(namespace).List(ctx, opts)
+		}},
+		{"jobs", false, func(opts metav1.ListOptions) (runtime.Object, error) {
+			return c.clientset.BatchV1().Jobs(namespace).List(ctx, opts)
+		}},
+		{"cronjobs", false, func(opts metav1.ListOptions) (runtime.Object, error) {
+			return c.clientset.BatchV1().CronJobs(namespace).List(ctx, opts)
+		}},
+		{"ingresses", false, func(opts metav1.ListOptions) (runtime.Object, error) {
+			return c.clientset.NetworkingV1().Ingresses(namespace).List(ctx, opts)
+		}},
+		{"networkpolicies", false, func(opts metav1.ListOptions) (runtime.Object, error) {
+			return c.clientset.NetworkingV1().NetworkPolicies(namespace).List(ctx, opts)
+		}},
+		{"hpas", false, func(opts metav1.ListOptions) (runtime.Object, error) {
+			return c.clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, opts)
+		}},
+		{"poddisruptionbudgets", false, func(opts metav1.ListOptions) (runtime.Object, error) {
+			return c.clientset.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, opts)
+		}},
+		{"persistentvolumeclaims", false, func(opts metav1.ListOptions) (runtime.Object, error) {
+			return c.clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, opts)
+		}},
+		{"resourcequotas", true, func(opts metav1.ListOptions) (runtime.Object, error) {
+			return c.clientset.CoreV1().ResourceQuotas(namespace).List(ctx, opts)
+		}},
+		{"limitranges", true, func(opts metav1.ListOptions) (runtime.Object, error) {
+			return c.clientset.CoreV1().LimitRanges(namespace).List(ctx, opts)
+		}},
 	}
 
-	// Get services
-	services, err := c.clientset.CoreV1().Services(namespace).List(context.TODO(), metav1.ListOptions{})
-	if err == nil && len(services.Items) > 0 {
-		result["services"] = services
+	var capped atomic.Bool
+
+	g := new(errgroup.Group)
+	g.SetLimit(c.concurrency)
+
+	for _, lister := range listers {
+		g.Go(func() error {
+			// Only cluster-wide lists are bounded by maxListItems
+			opts := metav1.ListOptions{}
+			if namespace == metav1.NamespaceAll {
+				opts.Limit = maxListItems
+			}
+			byMaxResources := false
+			if !lister.namespaceWide {
+				opts.LabelSelector = selector
+				// No single list needs more items than the whole cap allows
+				if c.maxResources > 0 && (opts.Limit == 0 || int64(c.maxResources) < opts.Limit) {
+					opts.Limit = int64(c.maxResources)
+					byMaxResources = true
+				}
+			}
+			list, err := lister.list(opts)
+			if err != nil {
+				return nil
+			}
+			if byMaxResources {
+				if hasMore(list) {
+					capped.Store(true)
+				}
+			} else {
+				warnIfTruncated(list, lister.kind)
+			}
+
+			if namespace != metav1.NamespaceAll {
+				if meta.LenList(list) > 0 {
+					store(lister.kind, list)
+				}
+				return nil
+			}
+
+			byNamespace, err := splitListByNamespace(list)
+			if err != nil {
+				return nil
+			}
+			for ns, nsList := range byNamespace {
+				store(ns+"/"+lister.kind, nsList)
+			}
+			return nil
+		})
+	}
+
+	// Individual list failures are tolerated, so there is no error to report
+	g.Wait()
+
+	// Budgets count the gathered pods, so they are summarized before --max-resources trims them
+	addDisruptionBudgets(result)
+	if c.maxResources > 0 {
+		var kinds []string
+		for _, lister := range listers {
+			if !lister.namespaceWide {
+				kinds = append(kinds, lister.kind)
+			}
+		}
+		if capResources(result, kinds, c.maxResources) > 0 || capped.Load() {
+			fmt.Fprintf(os.Stderr, "Warning: stopped gathering at %d resources (--max-resources). Narrow the analysis with -r or -l, or raise --max-resources\n", c.maxResources)
+		}
 	}
 
-	// Get configmaps
-	configmaps, err := c.clientset.CoreV1().ConfigMaps(namespace).List(context.TODO(), metav1.ListOptions{})
-	if err == nil && len(configmaps.Items) > 0 {
-		result["configmaps"] = configmaps
+	addNetworkPolicySelections(result)
+	c.addServiceEndpoints(ctx, namespace, result)
+	return nil
+}
+
+// warnIfTruncated warns when a list hit maxListItems and more items were left on the server
+func warnIfTruncated(list runtime.Object, kind string) {
+	if hasMore(list) {
+		fmt.Fprintf(os.Stderr, "Warning: only the first %d %s were gathered\n", maxListItems, kind)
 	}
+}
+
+// hasMore reports whether a list call hit its limit with more items left on the server
+func hasMore(list runtime.Object) bool {
+	listMeta, err := meta.ListAccessor(list)
+	return err == nil && listMeta.GetContinue() != ""
+}
 
-	// Get ingresses
-	ingresses, err := c.clientset.NetworkingV1().Ingresses(namespace).List(context.TODO(), metav1.ListOptions{})
-	if err == nil && len(ingresses.Items) > 0 {
-		result["ingresses"] = ingresses
+// capResources trims the gathered lists of kinds to max items in total and returns how many items
+// it dropped. The budget is split fairly across kinds, so a kind with many items (e.g. pods) can't
+// crowd out the workloads they belong to: kinds that need less than an equal share keep all their
+// items and the rest share what's left. Within a kind, namespaces are filled in order.
+func capResources(result map[string]interface{}, kinds []string, max int) int {
+	rank := make(map[string]int, len(kinds))
+	for i, kind := range kinds {
+		rank[kind] = i
+	}
+	kindOf := func(key string) string {
+		return key[strings.LastIndex(key, "/")+1:]
 	}
 
-	// Get HPAs
-	hpas, err := c.clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(context.TODO(), metav1.ListOptions{})
-	if err == nil && len(hpas.Items) > 0 {
-		result["hpas"] = hpas
+	keysByKind := make(map[string][]string)
+	counts := make(map[string]int)
+	for key, value := range result {
+		kind := kindOf(key)
+		if _, ok := rank[kind]; !ok {
+			continue
+		}
+		list, ok := value.(runtime.Object)
+		if !ok {
+			continue
+		}
+		keysByKind[kind] = append(keysByKind[kind], key)
+		counts[kind] += meta.LenList(list)
 	}
 
-	return nil
+	// Hand out the budget from the smallest kind up, each taking at most an equal share of what's left
+	present := make([]string, 0, len(keysByKind))
+	for kind := range keysByKind {
+		present = append(present, kind)
+	}
+	sort.Slice(present, func(i, j int) bool {
+		if ci, cj := counts[present[i]], counts[present[j]]; ci != cj {
+			return ci < cj
+		}
+		return rank[present[i]] < rank[present[j]]
+	})
+	budget, dropped := max, 0
+	for i, kind := range present {
+		share := min(counts[kind], budget/(len(present)-i))
+		budget -= share
+
+		keys := keysByKind[kind]
+		sort.Strings(keys)
+		for _, key := range keys {
+			list := result[key].(runtime.Object)
+			items, err := meta.ExtractList(list)
+			if err != nil {
+				continue
+			}
+			if len(items) <= share {
+				share -= len(items)
+				continue
+			}
+			dropped += len(items) - share
+			if share == 0 {
+				delete(result, key)
+				continue
+			}
+			if err := meta.SetList(list, items[:share]); err != nil {
+				delete(result, key)
+			}
+			share = 0
+		}
+	}
+	return dropped
 }
 
-func (c *Client) getPodsForDeployment(namespace string, deployment *appsv1.Deployment) (*corev1.PodList, error) {
+// splitListByNamespace splits a cluster-wide list into one list of the same type per namespace
+func splitListByNamespace(list runtime.Object) (map[string]runtime.Object, error) {
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+
+	grouped := make(map[string][]runtime.Object)
+	for _, item := range items {
+		accessor, err := meta.Accessor(item)
+		if err != nil {
+			continue
+		}
+		grouped[accessor.GetNamespace()] = append(grouped[accessor.GetNamespace()], item)
+	}
+
+	result := make(map[string]runtime.Object, len(grouped))
+	for ns, nsItems := range grouped {
+		nsList := list.DeepCopyObject()
+		if err := meta.SetList(nsList, nsItems); err != nil {
+			return nil, err
+		}
+		result[ns] = nsList
+	}
+	return result, nil
+}
+
+// getPodsForDeployment returns the pods owned by the deployment's ReplicaSets. The label
+// selector alone can also match pods of other workloads that share labels.
+func (c *Client) getPodsForDeployment(ctx context.Context, namespace string, deployment *appsv1.Deployment) (*corev1.PodList, error) {
 	labelSelector := metav1.LabelSelector{MatchLabels: deployment.Spec.Selector.MatchLabels}
 	listOptions := metav1.ListOptions{
 		LabelSelector: metav1.FormatLabelSelector(&labelSelector),
 	}
-	return c.clientset.CoreV1().Pods(namespace).List(context.TODO(), listOptions)
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	replicaSets, err := c.ownedReplicaSets(ctx, namespace, deployment)
+	if err != nil {
+		// Fall back to the label selector alone, e.g. when ReplicaSets can't be listed
+		return pods, nil
+	}
+	owners := make(map[types.UID]bool, len(replicaSets))
+	for _, rs := range replicaSets {
+		owners[rs.UID] = true
+	}
+
+	owned := pods.Items[:0]
+	for _, pod := range pods.Items {
+		if ref := metav1.GetControllerOf(&pod); ref != nil && owners[ref.UID] {
+			owned = append(owned, pod)
+		}
+	}
+	pods.Items = owned
+	return pods, nil
 }
 
-// getPodsForWorkload gets pods for any workload with a label selector
-func (c *Client) getPodsForWorkload(namespace string, obj *unstructured.Unstructured) (*corev1.PodList, error) {
-	// Extract selector from the unstructured object
-	selector, found, err := unstructured.NestedMap(obj.Object, "spec", "selector", "matchLabels")
-	if err != nil || !found {
-		return nil, fmt.Errorf("no selector found")
+// ownedReplicaSets returns the ReplicaSets controlled by a deployment, including old revisions
+func (c *Client) ownedReplicaSets(ctx context.Context, namespace string, deployment *appsv1.Deployment) ([]appsv1.ReplicaSet, error) {
+	labelSelector := metav1.LabelSelector{MatchLabels: deployment.Spec.Selector.MatchLabels}
+	listOptions := metav1.ListOptions{
+		LabelSelector: metav1.FormatLabelSelector(&labelSelector),
+	}
+	list, err := c.clientset.AppsV1().ReplicaSets(namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, err
 	}
 
-	// Convert to label selector
-	labels := make(map[string]string)
-	for k, v := range selector {
-		if str, ok := v.(string); ok {
-			labels[k] = str
+	var owned []appsv1.ReplicaSet
+	for _, rs := range list.Items {
+		if ref := metav1.GetControllerOf(&rs); ref != nil && ref.UID == deployment.UID {
+			owned = append(owned, rs)
 		}
 	}
+	return owned, nil
+}
+
+// PodNamePattern returns a regular expression matching exactly the pod names a workload creates,
+// including pods of older Deployment revisions that may still appear in metrics history.
+// Deployment pods are named <deployment>-<replicaset hash>-<suffix>, so the hashes come from
+// the ReplicaSets the deployment owns; this keeps deployment "api" from matching "api-worker".
+func (c *Client) PodNamePattern(ctx context.Context, namespace, kind, name string) (string, error) {
+	quoted := regexp.QuoteMeta(name)
 
-	labelSelector := metav1.LabelSelector{MatchLabels: labels}
+	switch kind {
+	case "Deployment":
+		deployment, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		replicaSets, err := c.ownedReplicaSets(ctx, namespace, deployment)
+		if err != nil {
+			return "", err
+		}
+
+		var hashes []string
+		for _, rs := range replicaSets {
+			if hash := strings.TrimPrefix(rs.Name, name+"-"); hash != rs.Name {
+				hashes = append(hashes, regexp.QuoteMeta(hash))
+			}
+		}
+		if len(hashes) == 0 {
+			return "", fmt.Errorf("no ReplicaSets found for deployment %s/%s", namespace, name)
+		}
+		sort.Strings(hashes)
+		return fmt.Sprintf("%s-(%s)-%s", quoted, strings.Join(hashes, "|"), podSuffixPattern), nil
+
+	case "StatefulSet":
+		// StatefulSet pods have stable ordinal names
+		return quoted + "-[0-9]+", nil
+
+	case "DaemonSet", "Job":
+		return quoted + "-" + podSuffixPattern, nil
+
+	case "CronJob":
+		// CronJob pods are named <cronjob>-<scheduled time>-<suffix> through their Job
+		return quoted + "-[0-9]+-" + podSuffixPattern, nil
+
+	case "Pod":
+		return quoted, nil
+
+	default:
+		return "", fmt.Errorf("unsupported workload kind %s", kind)
+	}
+}
+
+func (c *Client) getPodsForStatefulSet(ctx context.Context, namespace string, sts *appsv1.StatefulSet) (*corev1.PodList, error) {
+	labelSelector := metav1.LabelSelector{MatchLabels: sts.Spec.Selector.MatchLabels}
 	listOptions := metav1.ListOptions{
 		LabelSelector: metav1.FormatLabelSelector(&labelSelector),
 	}
+	return c.clientset.CoreV1().Pods(namespace).List(ctx, listOptions)
+}
 
-	return c.clientset.CoreV1().Pods(namespace).List(context.TODO(), listOptions)
+func (c *Client) getPodsForDaemonSet(ctx context.Context, namespace string, ds *appsv1.DaemonSet) (*corev1.PodList, error) {
+	labelSelector := metav1.LabelSelector{MatchLabels: ds.Spec.Selector.MatchLabels}
+	listOptions := metav1.ListOptions{
+		LabelSelector: metav1.FormatLabelSelector(&labelSelector),
+	}
+	return c.clientset.CoreV1().Pods(namespace).List(ctx, listOptions)
 }
 
-func (c *Client) getEvents(namespace string) (*corev1.EventList, error) {
-	return c.clientset.CoreV1().Events(namespace).List(context.TODO(), metav1.ListOptions{})
+// GetPodLogs returns the logs of a pod using the given log options
+func (c *Client) GetPodLogs(ctx context.Context, namespace, podName string, options *corev1.PodLogOptions) (string, error) {
+	raw, err := c.clientset.CoreV1().Pods(namespace).GetLogs(podName, options).DoRaw(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get logs for pod %s: %w", podName, err)
+	}
+	return string(raw), nil
+}
+
+// getPodLogs returns the last tailLines lines of logs for a pod container
+func (c *Client) getPodLogs(ctx context.Context, namespace, podName, container string, tailLines int64) (string, error) {
+	return c.GetPodLogs(ctx, namespace, podName, &corev1.PodLogOptions{
+		Container: container,
+		TailLines: &tailLines,
+	})
+}
+
+// addPodLogs stores recent logs of the given pods under "<resource>_logs" when log gathering is enabled
+func (c *Client) addPodLogs(ctx context.Context, namespace string, pods []corev1.Pod, resource string, result map[string]interface{}) {
+	if c.logTailLines <= 0 || len(pods) == 0 {
+		return
+	}
+
+	logs := make(map[string]string)
+	for i, pod := range pods {
+		if i >= maxLogPods {
+			break
+		}
+		for _, container := range pod.Spec.Containers {
+			podLogs, err := c.getPodLogs(ctx, namespace, pod.Name, container.Name, c.logTailLines)
+			if err != nil || strings.TrimSpace(podLogs) == "" {
+				continue
+			}
+			logs[pod.Name+"/"+container.Name] = TruncateLogs(podLogs, maxLogBytesPerStream)
+		}
+	}
+
+	if len(logs) > 0 {
+		result[resource+"_logs"] = logs
+	}
+}
+
+func (c *Client) getEvents(ctx context.Context, namespace string) (*corev1.EventList, error) {
+	opts := metav1.ListOptions{Limit: eventPageSize}
+	if !c.allEvents {
+		// Normal events (scheduled, pulled, started...) rarely explain a problem but flood busy namespaces
+		opts.FieldSelector = fields.OneTermEqualSelector("type", corev1.EventTypeWarning).String()
+	}
+	// The API server doesn't sort events by time, so every page is read, but only the most recent
+	// events seen so far are kept between pages. That bounds memory to a page plus maxEvents on
+	// busy clusters; a repeat of an event already dropped is counted from where it reappears.
+	events := &corev1.EventList{}
+	for {
+		page, err := c.clientset.CoreV1().Events(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		events.Items = mostRecentEvents(append(events.Items, page.Items...))
+		if page.Continue == "" {
+			break
+		}
+		opts.Continue = page.Continue
+	}
+	return events, nil
+}
+
+// mostRecentEvents dedupes events and keeps the maxEvents most recent, oldest first so they read
+// as a timeline
+func mostRecentEvents(items []corev1.Event) []corev1.Event {
+	items = dedupeEvents(items)
+	sort.SliceStable(items, func(i, j int) bool {
+		return eventTime(&items[i]).Before(eventTime(&items[j]))
+	})
+	if len(items) > maxEvents {
+		items = items[len(items)-maxEvents:]
+	}
+	return items
+}
+
+// dedupeEvents collapses events with the same reason, message and involved object into one, summing
+// their counts and widening their first/last timestamps. Kubernetes aggregates some repeats itself,
+// but e.g. events from different recorders or after a series expired are listed separately.
+func dedupeEvents(items []corev1.Event) []corev1.Event {
+	type eventKey struct {
+		reason, message       string
+		kind, namespace, name string
+	}
+
+	index := make(map[eventKey]int, len(items))
+	deduped := make([]corev1.Event, 0, len(items))
+	for _, event := range items {
+		key := eventKey{
+			reason:    event.Reason,
+			message:   event.Message,
+			kind:      event.InvolvedObject.Kind,
+			namespace: event.InvolvedObject.Namespace,
+			name:      event.InvolvedObject.Name,
+		}
+		i, seen := index[key]
+		if !seen {
+			index[key] = len(deduped)
+			deduped = append(deduped, event)
+			continue
+		}
+
+		merged := &deduped[i]
+		first, last := eventFirstTime(merged), eventTime(merged)
+		if t := eventFirstTime(&event); t.Before(first) {
+			first = t
+		}
+		if t := eventTime(&event); t.After(last) {
+			last = t
+		}
+		merged.Count = eventCount(merged) + eventCount(&event)
+		merged.FirstTimestamp = metav1.NewTime(first)
+		merged.LastTimestamp = metav1.NewTime(last)
+	}
+	return deduped
+}
+
+// eventCount returns how many times an event occurred, counting a series' observations
+func eventCount(event *corev1.Event) int32 {
+	switch {
+	case event.Count > 0:
+		return event.Count
+	case event.Series != nil && event.Series.Count > 0:
+		return event.Series.Count
+	default:
+		return 1
+	}
+}
+
+// eventFirstTime returns when an event first occurred
+func eventFirstTime(event *corev1.Event) time.Time {
+	switch {
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+// eventTime returns when an event last occurred. Events from the events.k8s.io API only set
+// eventTime, and some only have a creation timestamp.
+func eventTime(event *corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
 }
 
 // Helper functions
 
+// sanitizeObject strips managedFields and the last-applied-configuration annotation, which
+// only bloat prompts, from an object or every item of a list
+func sanitizeObject(obj interface{}) {
+	runtimeObj, ok := obj.(runtime.Object)
+	if !ok {
+		return
+	}
+
+	if meta.IsListType(runtimeObj) {
+		items, err := meta.ExtractList(runtimeObj)
+		if err != nil {
+			return
+		}
+		for _, item := range items {
+			sanitizeObject(item)
+		}
+		return
+	}
+
+	accessor, err := meta.Accessor(runtimeObj)
+	if err != nil {
+		return
+	}
+	accessor.SetManagedFields(nil)
+	if annotations := accessor.GetAnnotations(); annotations != nil {
+		if _, ok := annotations[lastAppliedAnnotation]; ok {
+			delete(annotations, lastAppliedAnnotation)
+			accessor.SetAnnotations(annotations)
+		}
+	}
+}
+
 func containsStringIgnoreCase(slice []string, str string) bool {
 	for _, s := range slice {
 		if strings.EqualFold(s, str) {
@@ -387,7 +1268,15 @@ func containsStringIgnoreCase(slice []string, str string) bool {
 	return false
 }
 
-func hasSelector(obj *unstructured.Unstructured) bool {
-	_, found, _ := unstructured.NestedMap(obj.Object, "spec", "selector")
-	return found
+// TruncateLogs keeps the most recent part of the logs when they exceed maxBytes
+func TruncateLogs(logs string, maxBytes int) string {
+	if len(logs) <= maxBytes {
+		return logs
+	}
+	truncated := logs[len(logs)-maxBytes:]
+	// Start at a line boundary so the first line isn't cut in half
+	if idx := strings.Index(truncated, "\n"); idx >= 0 && idx < len(truncated)-1 {
+		truncated = truncated[idx+1:]
+	}
+	return "[... earlier logs truncated ...]\n" + truncated
 }
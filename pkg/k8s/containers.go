@@ -0,0 +1,61 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ContainerNames returns the names of the containers in a workload's pod template, init
+// containers excluded, in the order they are declared
+func (c *Client) ContainerNames(ctx context.Context, namespace, kind, name string) ([]string, error) {
+	var spec corev1.PodSpec
+	switch kind {
+	case "Deployment":
+		deployment, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		spec = deployment.Spec.Template.Spec
+	case "StatefulSet":
+		sts, err := c.clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		spec = sts.Spec.Template.Spec
+	case "DaemonSet":
+		ds, err := c.clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		spec = ds.Spec.Template.Spec
+	case "Job":
+		job, err := c.clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		spec = job.Spec.Template.Spec
+	case "CronJob":
+		cronJob, err := c.clientset.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		spec = cronJob.Spec.JobTemplate.Spec.Template.Spec
+	case "Pod":
+		pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		spec = pod.Spec
+	default:
+		return nil, fmt.Errorf("unsupported workload kind %s", kind)
+	}
+
+	names := make([]string, 0, len(spec.Containers))
+	for _, container := range spec.Containers {
+		names = append(names, container.Name)
+	}
+	return names, nil
+}
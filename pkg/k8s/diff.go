@@ -0,0 +1,355 @@
+package k8s
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Kinds of FieldChange
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// FieldChange is one difference between two versions of a manifest. List items that have a
+// name, such as containers and env vars, are addressed by it: containers[api].image.
+type FieldChange struct {
+	Path   string      `json:"path"`
+	Change string      `json:"change"`
+	Old    interface{} `json:"old,omitempty"`
+	New    interface{} `json:"new,omitempty"`
+}
+
+// ignoredDiffPaths are set by the API server on every write and say nothing about the change
+var ignoredDiffPaths = map[string]bool{
+	"status":                     true,
+	"metadata.resourceVersion":   true,
+	"metadata.uid":               true,
+	"metadata.generation":        true,
+	"metadata.managedFields":     true,
+	"metadata.creationTimestamp": true,
+	"metadata.selfLink":          true,
+	"metadata.annotations[deployment.kubernetes.io/revision]": true,
+	"metadata.annotations[" + lastAppliedAnnotation + "]":     true,
+	"metadata.labels.pod-template-hash":                       true,
+}
+
+// DiffManifests compares two manifests field by field. Values of env vars, annotations and data keys
+// matching redact (e.g. DB_PASSWORD) are masked after comparing, so a changed secret still shows up
+// as changed without its value. A nil redact pattern disables that masking, but the values of a
+// Secret are always masked.
+func DiffManifests(old, new map[string]interface{}, redact *regexp.Regexp) []FieldChange {
+	// Strip the last-applied annotation before diffing: it holds the whole object, Secret values
+	// included, and would otherwise show up inside an added or removed annotations map
+	old, new = sanitizedCopy(old), sanitizedCopy(new)
+
+	var changes []FieldChange
+	diffValues("", old, new, &changes)
+
+	if old["kind"] == "Secret" || new["kind"] == "Secret" {
+		for i := range changes {
+			maskSecretChange(&changes[i])
+		}
+	}
+
+	if redact != nil {
+		for i := range changes {
+			if sensitivePath(changes[i].Path, redact) {
+				if changes[i].Old != nil {
+					changes[i].Old = RedactedValue
+				}
+				if changes[i].New != nil {
+					changes[i].New = RedactedValue
+				}
+				continue
+			}
+			// A whole added or removed container, env list or annotation map may hold sensitive values
+			changes[i].Old = redactNested(changes[i].Old, redact)
+			changes[i].New = redactNested(changes[i].New, redact)
+		}
+	}
+	return changes
+}
+
+// DiffManifestSets compares two sets of manifests, e.g. two renderings of a chart, pairing objects
+// by kind/name. Paths are prefixed with kind/name, and an object found in only one set is reported
+// whole as added or removed. Secret values are masked whether the Secret is in one set or both, and
+// the last-applied annotation, which would repeat them in clear, is stripped first.
+func DiffManifestSets(old, new []Manifest, redact *regexp.Regexp) ([]FieldChange, error) {
+	oldObjects, oldKeys, err := manifestsByKey(old)
+	if err != nil {
+		return nil, err
+	}
+	newObjects, newKeys, err := manifestsByKey(new)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := oldKeys
+	for _, key := range newKeys {
+		if _, ok := oldObjects[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	var changes []FieldChange
+	for _, key := range keys {
+		oldObject, inOld := oldObjects[key]
+		newObject, inNew := newObjects[key]
+		switch {
+		case !inNew:
+			changes = append(changes, FieldChange{Path: key, Change: ChangeRemoved, Old: wholeObject(oldObject, redact)})
+		case !inOld:
+			changes = append(changes, FieldChange{Path: key, Change: ChangeAdded, New: wholeObject(newObject, redact)})
+		default:
+			// DiffManifests masks the values of paired Secrets while keeping their changes
+			for _, change := range DiffManifests(oldObject, newObject, redact) {
+				change.Path = key + "." + change.Path
+				changes = append(changes, change)
+			}
+		}
+	}
+	return changes, nil
+}
+
+// manifestsByKey indexes manifests by kind/name, keeping their order
+func manifestsByKey(manifests []Manifest) (map[string]map[string]interface{}, []string, error) {
+	objects := make(map[string]map[string]interface{}, len(manifests))
+	keys := make([]string, 0, len(manifests))
+	for _, manifest := range manifests {
+		key, err := ManifestKey(manifest)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, ok := objects[key]; ok {
+			return nil, nil, fmt.Errorf("%s: %s is defined more than once", manifest.File, key)
+		}
+		objects[key] = manifest.Object
+		keys = append(keys, key)
+	}
+	return objects, keys, nil
+}
+
+// wholeObject prepares an added or removed object for the prompt: Secret values are always
+// masked, and other sensitive values when redact is set
+func wholeObject(object map[string]interface{}, redact *regexp.Regexp) interface{} {
+	obj := &unstructured.Unstructured{Object: sanitizedCopy(object)}
+	if obj.GetKind() == "Secret" {
+		maskSecret(obj)
+	}
+	if redact == nil {
+		return obj.Object
+	}
+	return redactNested(obj.Object, redact)
+}
+
+// sanitizedCopy returns a copy of a manifest without managedFields or the last-applied annotation,
+// the same stripping ResourcesFromManifests does
+func sanitizedCopy(object map[string]interface{}) map[string]interface{} {
+	if object == nil {
+		return nil
+	}
+	obj := (&unstructured.Unstructured{Object: object}).DeepCopy()
+	sanitizeObject(obj)
+	return obj.Object
+}
+
+func diffValues(path string, old, new interface{}, changes *[]FieldChange) {
+	if ignoredDiffPaths[path] {
+		return
+	}
+
+	switch {
+	case old == nil && new == nil:
+		return
+	case old == nil:
+		*changes = append(*changes, FieldChange{Path: path, Change: ChangeAdded, New: new})
+		return
+	case new == nil:
+		*changes = append(*changes, FieldChange{Path: path, Change: ChangeRemoved, Old: old})
+		return
+	}
+
+	oldMap, oldIsMap := old.(map[string]interface{})
+	newMap, newIsMap := new.(map[string]interface{})
+	if oldIsMap && newIsMap {
+		keys := make(map[string]bool)
+		for key := range oldMap {
+			keys[key] = true
+		}
+		for key := range newMap {
+			keys[key] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for key := range keys {
+			sorted = append(sorted, key)
+		}
+		sort.Strings(sorted)
+
+		for _, key := range sorted {
+			diffValues(joinPath(path, key), oldMap[key], newMap[key], changes)
+		}
+		return
+	}
+
+	oldList, oldIsList := old.([]interface{})
+	newList, newIsList := new.([]interface{})
+	if oldIsList && newIsList {
+		if oldNamed, ok := namedItems(oldList); ok {
+			if newNamed, ok := namedItems(newList); ok {
+				diffNamedLists(path, oldList, newList, oldNamed, newNamed, changes)
+				return
+			}
+		}
+	}
+
+	if !reflect.DeepEqual(old, new) {
+		*changes = append(*changes, FieldChange{Path: path, Change: ChangeChanged, Old: old, New: new})
+	}
+}
+
+// diffNamedLists matches list items by name, so reordering or inserting a container doesn't
+// report every later item as changed
+func diffNamedLists(path string, oldList, newList []interface{}, oldNamed, newNamed map[string]interface{}, changes *[]FieldChange) {
+	var names []string
+	seen := make(map[string]bool)
+	for _, list := range [][]interface{}{oldList, newList} {
+		for _, item := range list {
+			name := item.(map[string]interface{})["name"].(string)
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	for _, name := range names {
+		diffValues(fmt.Sprintf("%s[%s]", path, name), oldNamed[name], newNamed[name], changes)
+	}
+}
+
+// namedItems indexes a list of objects by their unique name field, if they all have one
+func namedItems(list []interface{}) (map[string]interface{}, bool) {
+	named := make(map[string]interface{}, len(list))
+	for _, item := range list {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		name, ok := object["name"].(string)
+		if !ok || name == "" {
+			return nil, false
+		}
+		if _, duplicate := named[name]; duplicate {
+			return nil, false
+		}
+		named[name] = object
+	}
+	return named, true
+}
+
+// joinPath appends a map key to a path. Keys that aren't plain identifiers, such as label and
+// annotation keys, are bracketed: metadata.labels[app.kubernetes.io/name].
+func joinPath(path, key string) string {
+	if strings.ContainsAny(key, "./") {
+		return fmt.Sprintf("%s[%s]", path, key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// secretDataFields hold the values of a Secret
+var secretDataFields = []string{"data", "stringData", "binaryData"}
+
+// maskSecretChange masks the values of a change to a Secret's data, keeping the keys and the
+// change type so a rotated value still shows up as changed
+func maskSecretChange(change *FieldChange) {
+	for _, field := range secretDataFields {
+		if change.Path != field && !strings.HasPrefix(change.Path, field+"[") && !strings.HasPrefix(change.Path, field+".") {
+			continue
+		}
+		change.Old = maskedValues(change.Old)
+		change.New = maskedValues(change.New)
+		return
+	}
+}
+
+// maskedValues replaces a value, or every value of a whole data map, with RedactedValue
+func maskedValues(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		masked := make(map[string]interface{}, len(v))
+		for key := range v {
+			masked[key] = RedactedValue
+		}
+		return masked
+	default:
+		return RedactedValue
+	}
+}
+
+// sensitiveKey captures env var names (env[NAME]) and annotation and data keys along a path. The
+// field must start a path segment so metadata isn't read as data, and a bracketed key is captured
+// whole, since keys such as tls.key contain dots.
+var sensitiveKey = regexp.MustCompile(`(?:^|[.\]])(?:env|annotations|data|binaryData|stringData)(?:\[([^\]]+)\]|\.([^.\[\]]+))`)
+
+// sensitivePath reports whether the path goes through an env var, annotation or data key matching redact
+func sensitivePath(path string, redact *regexp.Regexp) bool {
+	for _, match := range sensitiveKey.FindAllStringSubmatch(path, -1) {
+		if redact.MatchString(match[1] + match[2]) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactNested masks matching env var values and annotation and data values inside an added or
+// removed value, returning a masked copy
+func redactNested(value interface{}, redact *regexp.Regexp) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		masked := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			switch key {
+			case "annotations", "data", "binaryData", "stringData":
+				if values, ok := item.(map[string]interface{}); ok {
+					maskedValues := make(map[string]interface{}, len(values))
+					for k, val := range values {
+						if redact.MatchString(k) {
+							val = RedactedValue
+						}
+						maskedValues[k] = val
+					}
+					masked[key] = maskedValues
+					continue
+				}
+			}
+			masked[key] = redactNested(item, redact)
+		}
+		// Env vars are {name, value} pairs
+		if name, ok := v["name"].(string); ok && redact.MatchString(name) {
+			if _, hasValue := v["value"]; hasValue {
+				masked["value"] = RedactedValue
+			}
+		}
+		return masked
+	case []interface{}:
+		masked := make([]interface{}, len(v))
+		for i, item := range v {
+			masked[i] = redactNested(item, redact)
+		}
+		return masked
+	default:
+		return value
+	}
+}
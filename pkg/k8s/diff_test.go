@@ -0,0 +1,103 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func secretManifest(values map[string]interface{}) Manifest {
+	return Manifest{File: "secret.yaml", Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "db"},
+		"data":       values,
+	}}
+}
+
+func TestDiffManifestSetsMasksSecrets(t *testing.T) {
+	old := []Manifest{secretManifest(map[string]interface{}{"DATABASE_URL": "old-url", "tls.key": "old-key"})}
+	applied := []Manifest{lastApplied(secretManifest(map[string]interface{}{"DATABASE_URL": "new-url", "tls.key": "new-key"}))}
+	tests := []struct {
+		name   string
+		redact *regexp.Regexp
+		old    []Manifest
+		new    []Manifest
+	}{
+		{"paired", regexp.MustCompile(DefaultRedactPattern), old, []Manifest{secretManifest(map[string]interface{}{"DATABASE_URL": "new-url", "tls.key": "new-key"})}},
+		{"paired without redaction", nil, old, []Manifest{secretManifest(map[string]interface{}{"DATABASE_URL": "new-url", "tls.key": "new-key"})}},
+		{"paired with last-applied annotation", nil, old, applied},
+		{"removed", nil, old, nil},
+		{"added with last-applied annotation", regexp.MustCompile(DefaultRedactPattern), nil, applied},
+		{"removed with last-applied annotation", nil, applied, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := DiffManifestSets(tt.old, tt.new, tt.redact)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(changes) == 0 {
+				t.Fatal("expected the Secret to show up as changed")
+			}
+			for _, change := range changes {
+				for _, value := range []string{"old-url", "new-url", "old-key", "new-key"} {
+					if strings.Contains(fmt.Sprint(change.Old, change.New), value) {
+						t.Errorf("%s leaks %q", change.Path, value)
+					}
+				}
+			}
+		})
+	}
+}
+
+// lastApplied adds the annotation kubectl apply leaves on an object, holding the object in clear
+func lastApplied(manifest Manifest) Manifest {
+	applied, err := json.Marshal(manifest.Object)
+	if err != nil {
+		panic(err)
+	}
+	manifest.Object["metadata"].(map[string]interface{})["annotations"] = map[string]interface{}{
+		lastAppliedAnnotation: string(applied),
+	}
+	return manifest
+}
+
+func TestDiffManifestsKeepsSecretChanges(t *testing.T) {
+	old := secretManifest(map[string]interface{}{"tls.key": "a", "same": "x"}).Object
+	new := secretManifest(map[string]interface{}{"tls.key": "b", "same": "x"}).Object
+
+	changes := DiffManifests(old, new, nil)
+	if len(changes) != 1 || changes[0].Path != "data[tls.key]" || changes[0].Change != ChangeChanged {
+		t.Fatalf("expected only data[tls.key] to change, got %+v", changes)
+	}
+	if changes[0].Old != RedactedValue || changes[0].New != RedactedValue {
+		t.Errorf("expected masked values, got %v -> %v", changes[0].Old, changes[0].New)
+	}
+}
+
+func TestDiffManifestsStripsLastAppliedAnnotation(t *testing.T) {
+	old := secretManifest(map[string]interface{}{"tls.key": "a"}).Object
+	new := secretManifest(map[string]interface{}{"tls.key": "a"}).Object
+	new["metadata"] = map[string]interface{}{
+		"name": "db",
+		"annotations": map[string]interface{}{
+			lastAppliedAnnotation: `{"kind":"Secret","stringData":{"tls.key":"clear-value"}}`,
+			"team":                "payments",
+		},
+	}
+
+	changes := DiffManifests(old, new, regexp.MustCompile(DefaultRedactPattern))
+	if len(changes) != 1 || changes[0].Path != "metadata.annotations" || changes[0].Change != ChangeAdded {
+		t.Fatalf("expected only metadata.annotations to be added, got %+v", changes)
+	}
+	if strings.Contains(fmt.Sprint(changes[0].New), "clear-value") {
+		t.Errorf("metadata.annotations leaks the last-applied Secret: %v", changes[0].New)
+	}
+	if _, ok := new["metadata"].(map[string]interface{})["annotations"].(map[string]interface{})[lastAppliedAnnotation]; !ok {
+		t.Error("expected the caller's manifest to be left untouched")
+	}
+}
@@ -0,0 +1,108 @@
+package k8s
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/homedir"
+)
+
+// DefaultDiscoveryCacheTTL is how long persisted discovery results are reused
+const DefaultDiscoveryCacheTTL = 10 * time.Minute
+
+// unsafeCacheChars matches characters that can't appear in a cache file name
+var unsafeCacheChars = regexp.MustCompile(`[^\w.-]`)
+
+// discoveryCacheFile is the on-disk format of persisted discovery results
+type discoveryCacheFile struct {
+	Host      string                    `json:"host"`
+	Timestamp time.Time                 `json:"timestamp"`
+	Resources []*metav1.APIResourceList `json:"resources"`
+}
+
+// DefaultDiscoveryCacheDir returns ~/.kube/cache/kubectl-ai, or "" when there is no home directory
+func DefaultDiscoveryCacheDir() string {
+	home := homedir.HomeDir()
+	if home == "" {
+		return ""
+	}
+	return filepath.Join(home, ".kube", "cache", "kubectl-ai")
+}
+
+// SetRefreshDiscoveryCache forces live discovery, replacing any persisted results
+func (c *Client) SetRefreshDiscoveryCache(refresh bool) {
+	c.refreshDiscovery = refresh
+}
+
+// discoveryCachePath returns the cache file for the client's cluster, keyed by host like kubectl does
+func (c *Client) discoveryCachePath() string {
+	if c.discoveryCacheDir == "" || c.config == nil || c.config.Host == "" {
+		return ""
+	}
+	host := strings.TrimPrefix(strings.TrimPrefix(c.config.Host, "https://"), "http://")
+	host = unsafeCacheChars.ReplaceAllString(host, "_")
+	return filepath.Join(c.discoveryCacheDir, host+".json")
+}
+
+// loadDiscoveryCache returns persisted discovery results, or nil when they are missing,
+// expired, unreadable or were written for another cluster
+func (c *Client) loadDiscoveryCache() []*metav1.APIResourceList {
+	path := c.discoveryCachePath()
+	if path == "" || c.refreshDiscovery {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var cached discoveryCacheFile
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil
+	}
+	if cached.Host != c.config.Host || time.Since(cached.Timestamp) > c.discoveryCacheTTL {
+		return nil
+	}
+	return cached.Resources
+}
+
+// saveDiscoveryCache persists discovery results. Failures are ignored since the cache is
+// only an optimization.
+func (c *Client) saveDiscoveryCache(resources []*metav1.APIResourceList) {
+	path := c.discoveryCachePath()
+	if path == "" {
+		return
+	}
+
+	data, err := json.Marshal(discoveryCacheFile{
+		Host:      c.config.Host,
+		Timestamp: time.Now(),
+		Resources: resources,
+	})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return
+	}
+
+	// Write to a temp file and rename so concurrent invocations never read a partial file
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
@@ -0,0 +1,183 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxListedAddresses caps how many addresses of each kind are listed per Service
+const maxListedAddresses = 20
+
+// EndpointSummary counts the backends of a Service by readiness, answering whether it has
+// anything to route traffic to
+type EndpointSummary struct {
+	Ready             int      `json:"ready"`
+	NotReady          int      `json:"notReady"`
+	Terminating       int      `json:"terminating,omitempty"`
+	Ports             []string `json:"ports,omitempty"`
+	ReadyAddresses    []string `json:"readyAddresses,omitempty"`
+	NotReadyAddresses []string `json:"notReadyAddresses,omitempty"`
+	Warning           string   `json:"warning,omitempty"`
+}
+
+// serviceEndpoints summarizes the EndpointSlices of a Service, falling back to the legacy
+// Endpoints object on clusters without the discovery.k8s.io API
+func (c *Client) serviceEndpoints(ctx context.Context, namespace, service string) (*EndpointSummary, error) {
+	slices, err := c.clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + service,
+	})
+	if err == nil {
+		return summarizeEndpointSlices(slices.Items), nil
+	}
+
+	endpoints, epErr := c.clientset.CoreV1().Endpoints(namespace).Get(ctx, service, metav1.GetOptions{})
+	if epErr != nil {
+		return nil, err
+	}
+	return summarizeEndpoints(endpoints), nil
+}
+
+// addServiceEndpoints summarizes the endpoints of every gathered Service list under
+// "[<namespace>/]services_endpoints", keyed by Service name
+func (c *Client) addServiceEndpoints(ctx context.Context, namespace string, result map[string]interface{}) {
+	var serviceKeys []string
+	for key, value := range result {
+		if _, ok := value.(*corev1.ServiceList); ok && (key == "services" || strings.HasSuffix(key, "/services")) {
+			serviceKeys = append(serviceKeys, key)
+		}
+	}
+	if len(serviceKeys) == 0 {
+		return
+	}
+
+	// One list call covers every Service, across namespaces if needed
+	slices, err := c.clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return
+	}
+	byService := make(map[string][]discoveryv1.EndpointSlice)
+	for _, slice := range slices.Items {
+		name := slice.Labels[discoveryv1.LabelServiceName]
+		if name != "" {
+			byService[slice.Namespace+"/"+name] = append(byService[slice.Namespace+"/"+name], slice)
+		}
+	}
+
+	for _, key := range serviceKeys {
+		services := result[key].(*corev1.ServiceList)
+		summaries := make(map[string]*EndpointSummary, len(services.Items))
+		for _, svc := range services.Items {
+			// ExternalName services have no endpoints by design
+			if svc.Spec.Type == corev1.ServiceTypeExternalName {
+				continue
+			}
+			summaries[svc.Name] = summarizeEndpointSlices(byService[svc.Namespace+"/"+svc.Name])
+		}
+		result[strings.TrimSuffix(key, "services")+"services_endpoints"] = summaries
+	}
+}
+
+// summarizeEndpointSlices counts ready, not ready and terminating endpoints across slices
+func summarizeEndpointSlices(slices []discoveryv1.EndpointSlice) *EndpointSummary {
+	summary := &EndpointSummary{}
+	ports := make(map[string]bool)
+	for _, slice := range slices {
+		for _, port := range slice.Ports {
+			if port.Port != nil {
+				ports[endpointPortString(port.Name, *port.Port, port.Protocol)] = true
+			}
+		}
+		for _, endpoint := range slice.Endpoints {
+			address := endpointAddress(endpoint.Addresses, endpoint.TargetRef)
+			switch {
+			case endpoint.Conditions.Terminating != nil && *endpoint.Conditions.Terminating:
+				summary.Terminating++
+			case endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready:
+				// A nil ready condition means ready per the EndpointSlice API
+				summary.Ready++
+				summary.ReadyAddresses = appendCapped(summary.ReadyAddresses, address)
+			default:
+				summary.NotReady++
+				summary.NotReadyAddresses = appendCapped(summary.NotReadyAddresses, address)
+			}
+		}
+	}
+	summary.Ports = sortedKeys(ports)
+	summary.warn()
+	return summary
+}
+
+// summarizeEndpoints counts the addresses of a legacy Endpoints object
+func summarizeEndpoints(endpoints *corev1.Endpoints) *EndpointSummary {
+	summary := &EndpointSummary{}
+	ports := make(map[string]bool)
+	for _, subset := range endpoints.Subsets {
+		for _, port := range subset.Ports {
+			ports[endpointPortString(&port.Name, port.Port, &port.Protocol)] = true
+		}
+		for _, address := range subset.Addresses {
+			summary.Ready++
+			summary.ReadyAddresses = appendCapped(summary.ReadyAddresses, endpointAddress([]string{address.IP}, address.TargetRef))
+		}
+		for _, address := range subset.NotReadyAddresses {
+			summary.NotReady++
+			summary.NotReadyAddresses = appendCapped(summary.NotReadyAddresses, endpointAddress([]string{address.IP}, address.TargetRef))
+		}
+	}
+	summary.Ports = sortedKeys(ports)
+	summary.warn()
+	return summary
+}
+
+// warn spells out the missing backends, the usual cause of "connection refused" from a Service
+func (s *EndpointSummary) warn() {
+	switch {
+	case s.Ready == 0 && s.NotReady == 0 && s.Terminating == 0:
+		s.Warning = "no endpoints: the selector matches no pods, or the Service has no selector"
+	case s.Ready == 0:
+		s.Warning = "no ready endpoints: matching pods exist but none pass their readiness probe"
+	}
+}
+
+// endpointAddress formats an endpoint as "ip (kind/name)"
+func endpointAddress(addresses []string, target *corev1.ObjectReference) string {
+	address := strings.Join(addresses, ",")
+	if target != nil {
+		address += fmt.Sprintf(" (%s/%s)", strings.ToLower(target.Kind), target.Name)
+	}
+	return address
+}
+
+// endpointPortString formats a port as "name:port/protocol"
+func endpointPortString(name *string, port int32, protocol *corev1.Protocol) string {
+	s := fmt.Sprintf("%d", port)
+	if name != nil && *name != "" {
+		s = *name + ":" + s
+	}
+	if protocol != nil && *protocol != "" {
+		s += "/" + string(*protocol)
+	}
+	return s
+}
+
+func appendCapped(list []string, value string) []string {
+	if len(list) >= maxListedAddresses {
+		return list
+	}
+	return append(list, value)
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,135 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Exposure describes how a workload is reached: the Services selecting its pods and the
+// Ingress rules routing to those Services
+type Exposure struct {
+	Services  []ExposedService `json:"services"`
+	Ingresses []string         `json:"ingresses,omitempty"`
+}
+
+// ExposedService is a Service selecting the workload's pods
+type ExposedService struct {
+	Name  string   `json:"name"`
+	Type  string   `json:"type"`
+	Ports []string `json:"ports"`
+}
+
+// podTemplateLabels returns the labels of the pods a workload creates, or of the pod itself
+func podTemplateLabels(obj interface{}) map[string]string {
+	var content map[string]interface{}
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		content = u.Object
+	} else {
+		converted, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil
+		}
+		content = converted
+	}
+
+	for _, path := range [][]string{
+		{"spec", "template", "metadata", "labels"},
+		{"spec", "jobTemplate", "spec", "template", "metadata", "labels"},
+	} {
+		if podLabels, found, _ := unstructured.NestedStringMap(content, path...); found && len(podLabels) > 0 {
+			return podLabels
+		}
+	}
+	if kind, _, _ := unstructured.NestedString(content, "kind"); kind == "Pod" {
+		podLabels, _, _ := unstructured.NestedStringMap(content, "metadata", "labels")
+		return podLabels
+	}
+	if _, ok := obj.(*corev1.Pod); ok {
+		podLabels, _, _ := unstructured.NestedStringMap(content, "metadata", "labels")
+		return podLabels
+	}
+	return nil
+}
+
+// exposure finds the Services whose selector matches the pod labels and the Ingresses that
+// route to those Services
+func (c *Client) exposure(ctx context.Context, namespace string, podLabels map[string]string) (*Exposure, error) {
+	services, err := c.clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	exposure := &Exposure{}
+	exposed := make(map[string]bool)
+	for _, svc := range services.Items {
+		if len(svc.Spec.Selector) == 0 || !labels.SelectorFromSet(svc.Spec.Selector).Matches(labels.Set(podLabels)) {
+			continue
+		}
+		ports := make([]string, 0, len(svc.Spec.Ports))
+		for _, port := range svc.Spec.Ports {
+			ports = append(ports, fmt.Sprintf("%d->%s/%s", port.Port, port.TargetPort.String(), port.Protocol))
+		}
+		exposure.Services = append(exposure.Services, ExposedService{Name: svc.Name, Type: string(svc.Spec.Type), Ports: ports})
+		exposed[svc.Name] = true
+	}
+	if len(exposed) == 0 {
+		return exposure, nil
+	}
+
+	// Ingresses are optional, a cluster without the API or permission still has the Services
+	ingresses, err := c.clientset.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return exposure, nil
+	}
+	for _, ing := range ingresses.Items {
+		if backend := ing.Spec.DefaultBackend; backend != nil && backend.Service != nil && exposed[backend.Service.Name] {
+			exposure.Ingresses = append(exposure.Ingresses, fmt.Sprintf("%s: default backend -> %s", ing.Name, backend.Service.Name))
+		}
+		for _, rule := range ing.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			host := rule.Host
+			if host == "" {
+				host = "*"
+			}
+			for _, path := range rule.HTTP.Paths {
+				if path.Backend.Service == nil || !exposed[path.Backend.Service.Name] {
+					continue
+				}
+				exposure.Ingresses = append(exposure.Ingresses, fmt.Sprintf("%s: %s%s -> %s", ing.Name, host, path.Path, path.Backend.Service.Name))
+			}
+		}
+	}
+	sort.Strings(exposure.Ingresses)
+	return exposure, nil
+}
+
+// AddExposure stores how a gathered workload is reached under "<resource>_exposure"
+func (c *Client) AddExposure(ctx context.Context, namespace, resource string, result map[string]interface{}) error {
+	namespace, _, _, err := splitResource(namespace, resource)
+	if err != nil {
+		return err
+	}
+
+	podLabels := podTemplateLabels(result[resource])
+	if len(podLabels) == 0 {
+		return nil
+	}
+
+	exposure, err := c.exposure(ctx, namespace, podLabels)
+	if err != nil {
+		return err
+	}
+	if len(exposure.Services) > 0 {
+		result[resource+"_exposure"] = exposure
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// deploymentRevisionAnnotation numbers the ReplicaSets of a Deployment's rollout history
+	deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+
+	// changeCauseAnnotation records why a revision was rolled out, when set by the user or CI
+	changeCauseAnnotation = "kubernetes.io/change-cause"
+)
+
+// RolloutEntry is one revision of a Deployment's rollout history, compact enough for the debug
+// context, so the LLM can tie a symptom to the revision that introduced it
+type RolloutEntry struct {
+	Revision      int64     `json:"revision"`
+	ReplicaSet    string    `json:"replicaSet"`
+	Images        []string  `json:"images"`
+	Created       time.Time `json:"created"`
+	Replicas      int32     `json:"replicas"`
+	ReadyReplicas int32     `json:"readyReplicas"`
+	Current       bool      `json:"current,omitempty"`
+	ChangeCause   string    `json:"changeCause,omitempty"`
+}
+
+// Revision is a workload's pod template at one point of its rollout history
+type Revision struct {
+	Number   int64
+	Template map[string]interface{}
+}
+
+// RolloutHistory returns the pod templates of a Deployment, StatefulSet or DaemonSet's retained
+// revisions, oldest first, like kubectl rollout history
+func (c *Client) RolloutHistory(ctx context.Context, namespace, resource string) ([]Revision, error) {
+	namespace, resourceType, resourceName, err := splitResource(namespace, resource)
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions []Revision
+	switch resourceType {
+	case "deployment", "deploy", "deployments":
+		deployment, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, resourceName, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		replicaSets, err := c.ownedReplicaSets(ctx, namespace, deployment)
+		if err != nil {
+			return nil, err
+		}
+		for _, rs := range replicaSets {
+			number, err := strconv.ParseInt(rs.Annotations[deploymentRevisionAnnotation], 10, 64)
+			if err != nil {
+				continue
+			}
+			template, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&rs.Spec.Template)
+			if err != nil {
+				return nil, err
+			}
+			revisions = append(revisions, Revision{Number: number, Template: template})
+		}
+
+	case "statefulset", "statefulsets", "sts":
+		sts, err := c.clientset.AppsV1().StatefulSets(namespace).Get(ctx, resourceName, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		revisions, err = c.controllerRevisions(ctx, namespace, sts.UID, sts.Spec.Selector)
+		if err != nil {
+			return nil, err
+		}
+
+	case "daemonset", "daemonsets", "ds":
+		ds, err := c.clientset.AppsV1().DaemonSets(namespace).Get(ctx, resourceName, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		revisions, err = c.controllerRevisions(ctx, namespace, ds.UID, ds.Spec.Selector)
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("rollout history is only available for deployments, statefulsets and daemonsets, not %s", resourceType)
+	}
+
+	if len(revisions) == 0 {
+		return nil, fmt.Errorf("no rollout history found for %s", resource)
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Number < revisions[j].Number
+	})
+	return revisions, nil
+}
+
+// deploymentHistory summarizes the ReplicaSets a Deployment owns, newest revision first
+func (c *Client) deploymentHistory(ctx context.Context, namespace string, deployment *appsv1.Deployment) ([]RolloutEntry, error) {
+	replicaSets, err := c.ownedReplicaSets(ctx, namespace, deployment)
+	if err != nil {
+		return nil, err
+	}
+
+	current := deployment.Annotations[deploymentRevisionAnnotation]
+	history := make([]RolloutEntry, 0, len(replicaSets))
+	for _, rs := range replicaSets {
+		number, err := strconv.ParseInt(rs.Annotations[deploymentRevisionAnnotation], 10, 64)
+		if err != nil {
+			continue
+		}
+		entry := RolloutEntry{
+			Revision:      number,
+			ReplicaSet:    rs.Name,
+			Created:       rs.CreationTimestamp.Time,
+			Replicas:      rs.Status.Replicas,
+			ReadyReplicas: rs.Status.ReadyReplicas,
+			Current:       rs.Annotations[deploymentRevisionAnnotation] == current,
+			ChangeCause:   rs.Annotations[changeCauseAnnotation],
+		}
+		for _, container := range rs.Spec.Template.Spec.Containers {
+			entry.Images = append(entry.Images, container.Image)
+		}
+		history = append(history, entry)
+	}
+
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].Revision > history[j].Revision
+	})
+	return history, nil
+}
+
+// controllerRevisions reads the pod templates StatefulSets and DaemonSets record in ControllerRevisions.
+// Each revision's data is a patch holding the full spec.template.
+func (c *Client) controllerRevisions(ctx context.Context, namespace string, owner types.UID, selector *metav1.LabelSelector) ([]Revision, error) {
+	list, err := c.clientset.AppsV1().ControllerRevisions(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: metav1.FormatLabelSelector(selector),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions []Revision
+	for _, rev := range list.Items {
+		if ref := metav1.GetControllerOf(&rev); ref == nil || ref.UID != owner {
+			continue
+		}
+		template, err := revisionTemplate(&rev)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read revision %d: %w", rev.Revision, err)
+		}
+		revisions = append(revisions, Revision{Number: rev.Revision, Template: template})
+	}
+	return revisions, nil
+}
+
+// revisionTemplate extracts spec.template from a ControllerRevision's data
+func revisionTemplate(rev *appsv1.ControllerRevision) (map[string]interface{}, error) {
+	var data struct {
+		Spec struct {
+			Template map[string]interface{} `json:"template"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(rev.Data.Raw, &data); err != nil {
+		return nil, err
+	}
+	if data.Spec.Template == nil {
+		return nil, fmt.Errorf("no pod template in revision data")
+	}
+	// The patch directive isn't part of the template
+	delete(data.Spec.Template, "$patch")
+	return data.Spec.Template, nil
+}
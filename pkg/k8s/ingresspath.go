@@ -0,0 +1,264 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Hops of an Ingress path, reported as the first broken one
+const (
+	hopService   = "service"
+	hopPort      = "service port"
+	hopEndpoints = "endpoints"
+	hopPods      = "pods"
+)
+
+// IngressPath traces an Ingress through its backend Services and their endpoints to the pods
+// serving them, so a 502 or 503 can be pinned to the hop that is broken
+type IngressPath struct {
+	Class     string           `json:"class,omitempty"`
+	Addresses []string         `json:"addresses,omitempty"`
+	Warning   string           `json:"warning,omitempty"`
+	Backends  []IngressBackend `json:"backends"`
+}
+
+// IngressBackend is one Service port the Ingress routes to, with the rules that use it and the
+// health of every hop behind it. BrokenHop names the first hop that can't serve traffic.
+type IngressBackend struct {
+	Routes      []string         `json:"routes"`
+	Service     string           `json:"service"`
+	Port        string           `json:"port"`
+	TargetPort  string           `json:"targetPort,omitempty"`
+	ServiceType string           `json:"serviceType,omitempty"`
+	Endpoints   *EndpointSummary `json:"endpoints,omitempty"`
+	Pods        []PathPod        `json:"pods,omitempty"`
+	ReadyPods   int              `json:"readyPods"`
+	TotalPods   int              `json:"totalPods"`
+	BrokenHop   string           `json:"brokenHop,omitempty"`
+	Problem     string           `json:"problem,omitempty"`
+}
+
+// PathPod is a pod selected by a backend Service and why it isn't ready, if it isn't
+type PathPod struct {
+	Name   string `json:"name"`
+	Phase  string `json:"phase"`
+	Ready  bool   `json:"ready"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ingressPath resolves every backend of the Ingress: the Service and port it names, the
+// Service's endpoints and the readiness of the pods it selects
+func (c *Client) ingressPath(ctx context.Context, namespace string, ing *networkingv1.Ingress) *IngressPath {
+	path := &IngressPath{Backends: []IngressBackend{}}
+	if ing.Spec.IngressClassName != nil {
+		path.Class = *ing.Spec.IngressClassName
+	}
+	for _, lb := range ing.Status.LoadBalancer.Ingress {
+		if lb.IP != "" {
+			path.Addresses = append(path.Addresses, lb.IP)
+		} else if lb.Hostname != "" {
+			path.Addresses = append(path.Addresses, lb.Hostname)
+		}
+	}
+	if len(path.Addresses) == 0 {
+		path.Warning = "no address: no ingress controller has admitted this Ingress, check its class"
+	}
+
+	for _, backend := range ingressBackends(ing) {
+		path.Backends = append(path.Backends, c.resolveBackend(ctx, namespace, backend))
+	}
+	return path
+}
+
+// ingressBackends groups the rules of an Ingress by the Service port they route to, in the
+// order the Service ports first appear
+func ingressBackends(ing *networkingv1.Ingress) []IngressBackend {
+	var backends []IngressBackend
+	index := make(map[string]int)
+	add := func(route string, service *networkingv1.IngressServiceBackend) {
+		if service == nil {
+			return
+		}
+		port := service.Port.Name
+		if port == "" {
+			port = fmt.Sprintf("%d", service.Port.Number)
+		}
+		key := service.Name + ":" + port
+		if i, ok := index[key]; ok {
+			backends[i].Routes = append(backends[i].Routes, route)
+			return
+		}
+		index[key] = len(backends)
+		backends = append(backends, IngressBackend{Routes: []string{route}, Service: service.Name, Port: port})
+	}
+
+	if ing.Spec.DefaultBackend != nil {
+		add("default backend", ing.Spec.DefaultBackend.Service)
+	}
+	for _, rule := range ing.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		host := rule.Host
+		if host == "" {
+			host = "*"
+		}
+		for _, p := range rule.HTTP.Paths {
+			add(host+p.Path, p.Backend.Service)
+		}
+	}
+	return backends
+}
+
+// resolveBackend checks each hop behind a backend and stops at the first broken one
+func (c *Client) resolveBackend(ctx context.Context, namespace string, backend IngressBackend) IngressBackend {
+	service, err := c.clientset.CoreV1().Services(namespace).Get(ctx, backend.Service, metav1.GetOptions{})
+	if err != nil {
+		backend.BrokenHop = hopService
+		backend.Problem = fmt.Sprintf("service %s: %v", backend.Service, err)
+		if apierrors.IsNotFound(err) {
+			backend.Problem = fmt.Sprintf("service %s does not exist in namespace %s", backend.Service, namespace)
+		}
+		return backend
+	}
+	backend.ServiceType = string(service.Spec.Type)
+
+	servicePort := findServicePort(service, backend.Port)
+	if servicePort == nil {
+		backend.BrokenHop = hopPort
+		backend.Problem = fmt.Sprintf("service %s has no port %s (it exposes %s)", backend.Service, backend.Port, servicePortList(service))
+		return backend
+	}
+	backend.TargetPort = servicePort.TargetPort.String()
+
+	// ExternalName services resolve through DNS and have no endpoints or pods by design
+	if service.Spec.Type == corev1.ServiceTypeExternalName {
+		return backend
+	}
+
+	if endpoints, err := c.serviceEndpoints(ctx, namespace, service.Name); err == nil {
+		backend.Endpoints = endpoints
+	}
+
+	if len(service.Spec.Selector) > 0 {
+		pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(service.Spec.Selector).String(),
+		})
+		if err == nil {
+			backend.TotalPods = len(pods.Items)
+			for i := range pods.Items {
+				pod := pathPod(&pods.Items[i])
+				if pod.Ready {
+					backend.ReadyPods++
+				}
+				if len(backend.Pods) < maxListedAddresses {
+					backend.Pods = append(backend.Pods, pod)
+				}
+			}
+			sort.Slice(backend.Pods, func(i, j int) bool { return backend.Pods[i].Name < backend.Pods[j].Name })
+
+			if servicePort.TargetPort.StrVal != "" && !podsDeclarePort(pods.Items, servicePort.TargetPort.StrVal) && backend.TotalPods > 0 {
+				backend.BrokenHop = hopPort
+				backend.Problem = fmt.Sprintf("service %s targets the named port %q, which no selected pod declares", backend.Service, servicePort.TargetPort.StrVal)
+				return backend
+			}
+		}
+	}
+
+	switch {
+	case backend.TotalPods > 0 && backend.ReadyPods == 0:
+		backend.BrokenHop = hopPods
+		backend.Problem = fmt.Sprintf("none of the %d pods selected by service %s are ready", backend.TotalPods, backend.Service)
+	case backend.Endpoints != nil && backend.Endpoints.Ready == 0:
+		backend.BrokenHop = hopEndpoints
+		backend.Problem = backend.Endpoints.Warning
+	case len(service.Spec.Selector) > 0 && backend.TotalPods == 0:
+		backend.BrokenHop = hopEndpoints
+		backend.Problem = fmt.Sprintf("the selector of service %s (%s) matches no pods", backend.Service, labels.SelectorFromSet(service.Spec.Selector))
+	}
+	return backend
+}
+
+// findServicePort returns the Service port an Ingress backend refers to by name or number
+func findServicePort(service *corev1.Service, port string) *corev1.ServicePort {
+	for i := range service.Spec.Ports {
+		p := &service.Spec.Ports[i]
+		if p.Name == port || fmt.Sprintf("%d", p.Port) == port {
+			return p
+		}
+	}
+	return nil
+}
+
+// servicePortList formats the ports a Service exposes as "name:port" or "port"
+func servicePortList(service *corev1.Service) string {
+	if len(service.Spec.Ports) == 0 {
+		return "no ports"
+	}
+	ports := make([]string, 0, len(service.Spec.Ports))
+	for _, p := range service.Spec.Ports {
+		port := fmt.Sprintf("%d", p.Port)
+		if p.Name != "" {
+			port = p.Name + ":" + port
+		}
+		ports = append(ports, port)
+	}
+	return strings.Join(ports, ", ")
+}
+
+// podsDeclarePort reports whether any of the pods has a container port with the given name
+func podsDeclarePort(pods []corev1.Pod, name string) bool {
+	for _, pod := range pods {
+		for _, container := range pod.Spec.Containers {
+			for _, port := range container.Ports {
+				if port.Name == name {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// pathPod summarizes a pod's readiness, with the most specific reason it isn't ready
+func pathPod(pod *corev1.Pod) PathPod {
+	summary := PathPod{Name: pod.Name, Phase: string(pod.Status.Phase)}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			summary.Ready = cond.Status == corev1.ConditionTrue
+			if !summary.Ready {
+				summary.Reason = cond.Reason
+			}
+		}
+	}
+	if pod.DeletionTimestamp != nil {
+		summary.Ready = false
+		summary.Reason = "Terminating"
+	}
+	if summary.Ready {
+		return summary
+	}
+
+	for _, status := range pod.Status.ContainerStatuses {
+		switch {
+		case status.State.Waiting != nil && status.State.Waiting.Reason != "":
+			summary.Reason = fmt.Sprintf("container %s: %s", status.Name, status.State.Waiting.Reason)
+			return summary
+		case status.State.Terminated != nil && status.State.Terminated.Reason != "":
+			summary.Reason = fmt.Sprintf("container %s: %s", status.Name, status.State.Terminated.Reason)
+			return summary
+		case status.State.Running != nil && !status.Ready:
+			summary.Reason = fmt.Sprintf("container %s: running but failing its readiness probe", status.Name)
+			return summary
+		}
+	}
+	return summary
+}
@@ -0,0 +1,135 @@
+package k8s
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxCronJobHistory caps how many of a CronJob's most recent Jobs are summarized
+const maxCronJobHistory = 5
+
+// JobStatus summarizes a Job's progress, so the LLM doesn't have to derive completion and
+// failure from raw conditions
+type JobStatus struct {
+	Name           string     `json:"name"`
+	Active         int32      `json:"active"`
+	Succeeded      int32      `json:"succeeded"`
+	Failed         int32      `json:"failed"`
+	Completions    int32      `json:"completions"`
+	BackoffLimit   int32      `json:"backoffLimit"`
+	State          string     `json:"state"`
+	Reason         string     `json:"reason,omitempty"`
+	Message        string     `json:"message,omitempty"`
+	StartTime      *time.Time `json:"startTime,omitempty"`
+	CompletionTime *time.Time `json:"completionTime,omitempty"`
+}
+
+// CronJobStatus summarizes a CronJob's schedule and the outcome of its most recent Jobs
+type CronJobStatus struct {
+	Schedule           string      `json:"schedule"`
+	Suspended          bool        `json:"suspended"`
+	LastScheduleTime   *time.Time  `json:"lastScheduleTime,omitempty"`
+	LastSuccessfulTime *time.Time  `json:"lastSuccessfulTime,omitempty"`
+	ActiveJobs         int         `json:"activeJobs"`
+	RecentJobs         []JobStatus `json:"recentJobs,omitempty"`
+}
+
+// jobStatus summarizes a Job from its status counters and terminal condition
+func jobStatus(job *batchv1.Job) JobStatus {
+	status := JobStatus{
+		Name:           job.Name,
+		Active:         job.Status.Active,
+		Succeeded:      job.Status.Succeeded,
+		Failed:         job.Status.Failed,
+		Completions:    1,
+		BackoffLimit:   6,
+		State:          "Running",
+		StartTime:      timeOrNil(job.Status.StartTime),
+		CompletionTime: timeOrNil(job.Status.CompletionTime),
+	}
+	if job.Spec.Completions != nil {
+		status.Completions = *job.Spec.Completions
+	}
+	if job.Spec.BackoffLimit != nil {
+		status.BackoffLimit = *job.Spec.BackoffLimit
+	}
+	if job.Spec.Suspend != nil && *job.Spec.Suspend {
+		status.State = "Suspended"
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			status.State = "Complete"
+		case batchv1.JobFailed:
+			status.State = "Failed"
+			status.Reason = condition.Reason
+			status.Message = condition.Message
+		}
+	}
+	return status
+}
+
+// cronJobStatus summarizes the CronJob and the Jobs it owns, newest first
+func (c *Client) cronJobStatus(ctx context.Context, namespace string, cronJob *batchv1.CronJob) (*CronJobStatus, []batchv1.Job, error) {
+	status := &CronJobStatus{
+		Schedule:           cronJob.Spec.Schedule,
+		Suspended:          cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend,
+		LastScheduleTime:   timeOrNil(cronJob.Status.LastScheduleTime),
+		LastSuccessfulTime: timeOrNil(cronJob.Status.LastSuccessfulTime),
+		ActiveJobs:         len(cronJob.Status.Active),
+	}
+
+	jobs, err := c.clientset.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var owned []batchv1.Job
+	for _, job := range jobs.Items {
+		for _, owner := range job.OwnerReferences {
+			if owner.UID == cronJob.UID {
+				owned = append(owned, job)
+				break
+			}
+		}
+	}
+	sort.Slice(owned, func(i, j int) bool {
+		return owned[j].CreationTimestamp.Before(&owned[i].CreationTimestamp)
+	})
+	if len(owned) > maxCronJobHistory {
+		owned = owned[:maxCronJobHistory]
+	}
+
+	for i := range owned {
+		status.RecentJobs = append(status.RecentJobs, jobStatus(&owned[i]))
+	}
+	return status, owned, nil
+}
+
+// getPodsForJob lists the pods created by a Job, including failed attempts
+func (c *Client) getPodsForJob(ctx context.Context, namespace string, job *batchv1.Job) (*corev1.PodList, error) {
+	listOptions := metav1.ListOptions{}
+	if job.Spec.Selector != nil {
+		listOptions.LabelSelector = metav1.FormatLabelSelector(job.Spec.Selector)
+	} else {
+		listOptions.LabelSelector = "job-name=" + job.Name
+	}
+	return c.clientset.CoreV1().Pods(namespace).List(ctx, listOptions)
+}
+
+// timeOrNil converts an optional API timestamp
+func timeOrNil(t *metav1.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	return &t.Time
+}
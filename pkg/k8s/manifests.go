@@ -0,0 +1,184 @@
+package k8s
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// ManifestsKey lists the files resources were read from when they come from manifests instead of the cluster
+const ManifestsKey = "manifest_files"
+
+// StdinManifest is the -f path that reads manifests from stdin, like kubectl apply -f -
+const StdinManifest = "-"
+
+// manifestExtensions are the files read from a directory, like kubectl does
+var manifestExtensions = map[string]bool{".yaml": true, ".yml": true, ".json": true}
+
+// Manifest is a parsed manifest and the file it was read from
+type Manifest struct {
+	File   string
+	Object map[string]interface{}
+}
+
+// ReadManifests parses every object in the given files, directories and stdin ("-"). Files may
+// hold several YAML documents or JSON objects, directories contribute their .yaml, .yml and .json
+// files (not recursively), and the items of a List become separate manifests.
+func ReadManifests(paths []string, stdin io.Reader) ([]Manifest, error) {
+	var manifests []Manifest
+	for _, path := range paths {
+		if path == StdinManifest {
+			objects, err := decodeManifests("stdin", stdin)
+			if err != nil {
+				return nil, err
+			}
+			manifests = append(manifests, objects...)
+			continue
+		}
+
+		files := []string{path}
+		if info, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		} else if info.IsDir() {
+			if files, err = manifestFilesIn(path); err != nil {
+				return nil, err
+			}
+		}
+		for _, file := range files {
+			f, err := os.Open(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", file, err)
+			}
+			objects, err := decodeManifests(file, f)
+			f.Close()
+			if err != nil {
+				return nil, err
+			}
+			manifests = append(manifests, objects...)
+		}
+	}
+	return manifests, nil
+}
+
+// manifestFilesIn lists the manifest files directly inside dir, sorted by name
+func manifestFilesIn(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && manifestExtensions[strings.ToLower(filepath.Ext(entry.Name()))] {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%s has no .yaml, .yml or .json files", dir)
+	}
+	return files, nil
+}
+
+// decodeManifests parses a stream of YAML documents or JSON objects, skipping empty documents such
+// as the comment-only ones helm template emits, and expands Lists into their items
+func decodeManifests(file string, r io.Reader) ([]Manifest, error) {
+	var manifests []Manifest
+	decoder := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+	for document := 1; ; document++ {
+		var object map[string]interface{}
+		if err := decoder.Decode(&object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to parse %s (document %d): %w", file, document, err)
+		}
+		if len(object) == 0 {
+			continue
+		}
+
+		kind, _ := object["kind"].(string)
+		if kind != "List" && !(strings.HasSuffix(kind, "List") && object["items"] != nil) {
+			manifests = append(manifests, Manifest{File: file, Object: object})
+			continue
+		}
+		items, _ := object["items"].([]interface{})
+		for _, item := range items {
+			itemObject, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s (document %d): list item is not an object", file, document)
+			}
+			manifests = append(manifests, Manifest{File: file, Object: itemObject})
+		}
+	}
+	if len(manifests) == 0 {
+		return nil, fmt.Errorf("%s has no resources", file)
+	}
+	return manifests, nil
+}
+
+// ManifestKey returns the kind/name a manifest is stored under, or an error when it lacks either
+func ManifestKey(manifest Manifest) (string, error) {
+	obj := &unstructured.Unstructured{Object: manifest.Object}
+	if obj.GetKind() == "" || obj.GetName() == "" {
+		return "", fmt.Errorf("%s: every resource needs a kind and metadata.name", manifest.File)
+	}
+	return strings.ToLower(obj.GetKind()) + "/" + obj.GetName(), nil
+}
+
+// ResourcesFromManifests builds the map GatherResources returns from manifests that may not be
+// applied yet, without contacting a cluster. Each object is stored under kind/name. Objects are
+// sanitized like gathered ones, redacted when redact is set, and Secret values are always masked
+// since manifests carry them in clear.
+func ResourcesFromManifests(manifests []Manifest, redact *regexp.Regexp) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	sources := make(map[string]string)
+	var files []string
+
+	for _, manifest := range manifests {
+		if len(files) == 0 || files[len(files)-1] != manifest.File {
+			files = append(files, manifest.File)
+		}
+		key, err := ManifestKey(manifest)
+		if err != nil {
+			return nil, err
+		}
+		if previous, ok := sources[key]; ok {
+			return nil, fmt.Errorf("%s: %s is already defined in %s", manifest.File, key, previous)
+		}
+		sources[key] = manifest.File
+
+		obj := &unstructured.Unstructured{Object: manifest.Object}
+		sanitizeObject(obj)
+		if obj.GetKind() == "Secret" {
+			maskSecret(obj)
+		}
+		if redact != nil {
+			redactObject(obj, redact)
+		}
+		result[key] = obj
+	}
+
+	if len(files) > 0 {
+		result[ManifestsKey] = files
+	}
+	return result, nil
+}
+
+// maskSecret replaces every value of a Secret's data, stringData and binaryData, keeping the keys
+func maskSecret(obj *unstructured.Unstructured) {
+	for _, field := range secretDataFields {
+		values, ok := obj.Object[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key := range values {
+			values[key] = RedactedValue
+		}
+	}
+}
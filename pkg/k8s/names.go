@@ -0,0 +1,50 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+)
+
+// ListNamespaces returns the sorted names of all namespaces, for shell completion
+func (c *Client) ListNamespaces(ctx context.Context) ([]string, error) {
+	namespaces, err := c.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(namespaces.Items))
+	for _, ns := range namespaces.Items {
+		names = append(names, ns.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ListResourceNames returns the sorted names of the resources of a type, which may be a plural,
+// singular or short name, for shell completion
+func (c *Client) ListResourceNames(ctx context.Context, namespace, resourceType string) ([]string, error) {
+	apiResource, gvr, err := c.discoverResource(ctx, resourceType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover resource type %s: %w", resourceType, err)
+	}
+
+	var resourceClient dynamic.ResourceInterface = c.dynamic.Resource(gvr)
+	if apiResource.Namespaced {
+		resourceClient = c.dynamic.Resource(gvr).Namespace(namespace)
+	}
+	items, err := resourceClient.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(items.Items))
+	for _, item := range items.Items {
+		names = append(names, item.GetName())
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,74 @@
+package k8s
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// selectedPodNames returns the names of the pods matched by a NetworkPolicy's podSelector.
+// An empty selector selects every pod in the namespace.
+func selectedPodNames(policy *networkingv1.NetworkPolicy, pods []corev1.Pod) []string {
+	selector, err := metav1.LabelSelectorAsSelector(&policy.Spec.PodSelector)
+	if err != nil {
+		return nil
+	}
+
+	names := []string{}
+	for _, pod := range pods {
+		if selector.Matches(labels.Set(pod.Labels)) {
+			names = append(names, pod.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// networkPolicyPods lists the namespace's pods and returns the names the policy selects
+func (c *Client) networkPolicyPods(ctx context.Context, namespace string, policy *networkingv1.NetworkPolicy) ([]string, error) {
+	// An empty podSelector becomes "", which lists every pod, as the policy selects them all
+	selector, err := metav1.LabelSelectorAsSelector(&policy.Spec.PodSelector)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return selectedPodNames(policy, pods.Items), nil
+}
+
+// addNetworkPolicySelections records, for every gathered NetworkPolicy list, which of the gathered
+// pods each policy selects under "[<namespace>/]networkpolicy_selected_pods"
+func addNetworkPolicySelections(result map[string]interface{}) {
+	selections := make(map[string]map[string][]string)
+	for key, value := range result {
+		policies, ok := value.(*networkingv1.NetworkPolicyList)
+		if !ok || (key != "networkpolicies" && !strings.HasSuffix(key, "/networkpolicies")) {
+			continue
+		}
+		prefix := strings.TrimSuffix(key, "networkpolicies")
+
+		var pods []corev1.Pod
+		if podList, ok := result[prefix+"pods"].(*corev1.PodList); ok {
+			pods = podList.Items
+		}
+
+		selected := make(map[string][]string, len(policies.Items))
+		for i := range policies.Items {
+			selected[policies.Items[i].Name] = selectedPodNames(&policies.Items[i], pods)
+		}
+		selections[prefix+"networkpolicy_selected_pods"] = selected
+	}
+
+	for key, selected := range selections {
+		result[key] = selected
+	}
+}
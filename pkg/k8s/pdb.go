@@ -0,0 +1,100 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// PDBBudget summarizes a PodDisruptionBudget against the pods it currently matches,
+// so the LLM can tell whether the budget blocks evictions such as node drains
+type PDBBudget struct {
+	MatchedPods        int    `json:"matchedPods"`
+	HealthyPods        int32  `json:"healthyPods"`
+	DesiredHealthy     int32  `json:"desiredHealthy"`
+	MinAvailable       string `json:"minAvailable,omitempty"`
+	MaxUnavailable     string `json:"maxUnavailable,omitempty"`
+	DisruptionsAllowed int32  `json:"disruptionsAllowed"`
+	BlocksEviction     bool   `json:"blocksEviction"`
+}
+
+// newPDBBudget summarizes the PDB from its status, which counts the pods it expects
+func newPDBBudget(pdb *policyv1.PodDisruptionBudget) *PDBBudget {
+	budget := &PDBBudget{
+		MatchedPods:        int(pdb.Status.ExpectedPods),
+		HealthyPods:        pdb.Status.CurrentHealthy,
+		DesiredHealthy:     pdb.Status.DesiredHealthy,
+		DisruptionsAllowed: pdb.Status.DisruptionsAllowed,
+		BlocksEviction:     pdb.Status.DisruptionsAllowed == 0,
+	}
+	if pdb.Spec.MinAvailable != nil {
+		budget.MinAvailable = pdb.Spec.MinAvailable.String()
+	}
+	if pdb.Spec.MaxUnavailable != nil {
+		budget.MaxUnavailable = pdb.Spec.MaxUnavailable.String()
+	}
+	return budget
+}
+
+// pdbBudget counts the pods matched by the PDB's selector and combines them with its status
+func (c *Client) pdbBudget(ctx context.Context, namespace string, pdb *policyv1.PodDisruptionBudget) (*PDBBudget, error) {
+	budget := newPDBBudget(pdb)
+
+	// The status lags behind the cluster, so count the matching pods directly when possible.
+	// An empty selector becomes "", which lists every pod, as the budget covers them all.
+	if pdb.Spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			return nil, err
+		}
+		pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: selector.String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		budget.MatchedPods = len(pods.Items)
+	}
+
+	return budget, nil
+}
+
+// addDisruptionBudgets summarizes, for every gathered PodDisruptionBudget list, each budget
+// against the gathered pods under "[<namespace>/]poddisruptionbudget_budgets"
+func addDisruptionBudgets(result map[string]interface{}) {
+	summaries := make(map[string]map[string]*PDBBudget)
+	for key, value := range result {
+		pdbs, ok := value.(*policyv1.PodDisruptionBudgetList)
+		if !ok || (key != "poddisruptionbudgets" && !strings.HasSuffix(key, "/poddisruptionbudgets")) {
+			continue
+		}
+		prefix := strings.TrimSuffix(key, "poddisruptionbudgets")
+		podList, hasPods := result[prefix+"pods"].(*corev1.PodList)
+
+		budgets := make(map[string]*PDBBudget, len(pdbs.Items))
+		for i := range pdbs.Items {
+			pdb := &pdbs.Items[i]
+			budget := newPDBBudget(pdb)
+			if hasPods && pdb.Spec.Selector != nil {
+				if selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector); err == nil {
+					budget.MatchedPods = 0
+					for _, pod := range podList.Items {
+						if selector.Matches(labels.Set(pod.Labels)) {
+							budget.MatchedPods++
+						}
+					}
+				}
+			}
+			budgets[pdb.Name] = budget
+		}
+		summaries[prefix+"poddisruptionbudget_budgets"] = budgets
+	}
+
+	for key, budgets := range summaries {
+		result[key] = budgets
+	}
+}
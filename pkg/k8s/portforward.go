@@ -0,0 +1,162 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// PortForward is an active port-forward from a local port to a pod behind a service
+type PortForward struct {
+	LocalPort int
+	Pod       string
+
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// Close stops the port-forward and waits for the forwarding goroutine to exit
+func (pf *PortForward) Close() {
+	pf.stopOnce.Do(func() {
+		close(pf.stopCh)
+	})
+	<-pf.doneCh
+}
+
+// PortForwardService forwards a free local port to servicePort of the given service.
+// Like kubectl, it picks a ready pod backing the service and forwards to its target port.
+func (c *Client) PortForwardService(ctx context.Context, namespace, serviceName string, servicePort int) (*PortForward, error) {
+	service, err := c.clientset.CoreV1().Services(namespace).Get(ctx, serviceName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service %s: %w", serviceName, err)
+	}
+	if len(service.Spec.Selector) == 0 {
+		return nil, fmt.Errorf("service %s has no selector to find pods", serviceName)
+	}
+
+	labelSelector := metav1.LabelSelector{MatchLabels: service.Spec.Selector}
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: metav1.FormatLabelSelector(&labelSelector),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods for service %s: %w", serviceName, err)
+	}
+
+	pod := pickReadyPod(pods.Items)
+	if pod == nil {
+		return nil, fmt.Errorf("no running pods found for service %s", serviceName)
+	}
+
+	targetPort, err := resolveTargetPort(service, servicePort, pod)
+	if err != nil {
+		return nil, err
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(c.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create port-forward transport: %w", err)
+	}
+	portForwardURL := c.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod.Name).
+		SubResource("portforward").
+		URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, portForwardURL)
+
+	stopCh := make(chan struct{})
+	readyCh := make(chan struct{})
+	// Port 0 lets the OS pick a free local port
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"},
+		[]string{fmt.Sprintf("0:%d", targetPort)}, stopCh, readyCh, io.Discard, io.Discard)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create port-forward: %w", err)
+	}
+
+	pf := &PortForward{
+		Pod:    pod.Name,
+		stopCh: stopCh,
+		doneCh: make(chan struct{}),
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(pf.doneCh)
+		errCh <- forwarder.ForwardPorts()
+	}()
+
+	select {
+	case <-readyCh:
+	case err := <-errCh:
+		return nil, fmt.Errorf("port-forward failed: %w", err)
+	case <-ctx.Done():
+		pf.Close()
+		return nil, ctx.Err()
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil || len(ports) == 0 {
+		pf.Close()
+		return nil, fmt.Errorf("failed to get forwarded port: %v", err)
+	}
+	pf.LocalPort = int(ports[0].Local)
+
+	return pf, nil
+}
+
+// pickReadyPod returns the first running and ready pod, or the first running one
+func pickReadyPod(pods []corev1.Pod) *corev1.Pod {
+	var running *corev1.Pod
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+				return pod
+			}
+		}
+		if running == nil {
+			running = pod
+		}
+	}
+	return running
+}
+
+// resolveTargetPort maps a service port to the container port on the pod
+func resolveTargetPort(service *corev1.Service, servicePort int, pod *corev1.Pod) (int, error) {
+	for _, port := range service.Spec.Ports {
+		if int(port.Port) != servicePort {
+			continue
+		}
+
+		switch {
+		case port.TargetPort.Type == intstr.Int && port.TargetPort.IntValue() > 0:
+			return port.TargetPort.IntValue(), nil
+		case port.TargetPort.Type == intstr.String && port.TargetPort.StrVal != "":
+			// Named port, look it up on the pod's containers
+			for _, container := range pod.Spec.Containers {
+				for _, containerPort := range container.Ports {
+					if containerPort.Name == port.TargetPort.StrVal {
+						return int(containerPort.ContainerPort), nil
+					}
+				}
+			}
+			return 0, fmt.Errorf("named port %s not found on pod %s", port.TargetPort.StrVal, pod.Name)
+		default:
+			// No target port means it equals the service port
+			return servicePort, nil
+		}
+	}
+	return 0, fmt.Errorf("service %s has no port %d", service.Name, servicePort)
+}
@@ -0,0 +1,150 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxPVCs caps how many claims are fetched per workload, e.g. for large StatefulSets
+const maxPVCs = 20
+
+// PVCStatus describes a PersistentVolumeClaim referenced by a workload together with its
+// StorageClass, so the LLM can see why a volume isn't bound
+type PVCStatus struct {
+	Name             string                                  `json:"name"`
+	Phase            corev1.PersistentVolumeClaimPhase       `json:"phase,omitempty"`
+	VolumeName       string                                  `json:"volumeName,omitempty"`
+	StorageClassName string                                  `json:"storageClassName,omitempty"`
+	Requested        string                                  `json:"requested,omitempty"`
+	Capacity         string                                  `json:"capacity,omitempty"`
+	AccessModes      []corev1.PersistentVolumeAccessMode     `json:"accessModes,omitempty"`
+	Conditions       []corev1.PersistentVolumeClaimCondition `json:"conditions,omitempty"`
+	StorageClass     *StorageClassSummary                    `json:"storageClass,omitempty"`
+	Error            string                                  `json:"error,omitempty"`
+}
+
+// StorageClassSummary holds the StorageClass fields that decide how and when a claim is provisioned
+type StorageClassSummary struct {
+	Provisioner          string                                `json:"provisioner"`
+	VolumeBindingMode    *storagev1.VolumeBindingMode          `json:"volumeBindingMode,omitempty"`
+	ReclaimPolicy        *corev1.PersistentVolumeReclaimPolicy `json:"reclaimPolicy,omitempty"`
+	AllowVolumeExpansion *bool                                 `json:"allowVolumeExpansion,omitempty"`
+	Parameters           map[string]string                     `json:"parameters,omitempty"`
+}
+
+// claimNames returns the PVC names mounted by the given pod specs
+func claimNames(specs ...corev1.PodSpec) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, spec := range specs {
+		for _, volume := range spec.Volumes {
+			if volume.PersistentVolumeClaim == nil || seen[volume.PersistentVolumeClaim.ClaimName] {
+				continue
+			}
+			seen[volume.PersistentVolumeClaim.ClaimName] = true
+			names = append(names, volume.PersistentVolumeClaim.ClaimName)
+		}
+	}
+	return names
+}
+
+// statefulSetClaimNames returns the PVCs the StatefulSet creates from its volumeClaimTemplates,
+// named <template>-<statefulset>-<ordinal>
+func statefulSetClaimNames(sts *appsv1.StatefulSet) []string {
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+
+	var names []string
+	for _, template := range sts.Spec.VolumeClaimTemplates {
+		for ordinal := int32(0); ordinal < replicas; ordinal++ {
+			names = append(names, fmt.Sprintf("%s-%s-%d", template.Name, sts.Name, ordinal))
+		}
+	}
+	return names
+}
+
+// addPVCs stores the status of the named claims under "<resource>_pvcs". Missing claims are kept
+// with an error since a pod waiting on a nonexistent claim is a common root cause.
+func (c *Client) addPVCs(ctx context.Context, namespace string, names []string, resource string, result map[string]interface{}) {
+	if len(names) == 0 {
+		return
+	}
+
+	seen := make(map[string]bool)
+	storageClasses := make(map[string]*StorageClassSummary)
+	var statuses []PVCStatus
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		if len(seen) >= maxPVCs {
+			break
+		}
+		seen[name] = true
+
+		pvc, err := c.clientset.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			statuses = append(statuses, PVCStatus{Name: name, Error: err.Error()})
+			continue
+		}
+
+		status := pvcStatus(pvc)
+		if status.StorageClassName != "" {
+			sc, ok := storageClasses[status.StorageClassName]
+			if !ok {
+				sc = c.storageClassSummary(ctx, status.StorageClassName)
+				storageClasses[status.StorageClassName] = sc
+			}
+			status.StorageClass = sc
+		}
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	result[resource+"_pvcs"] = statuses
+}
+
+// pvcStatus summarizes a claim's binding state
+func pvcStatus(pvc *corev1.PersistentVolumeClaim) PVCStatus {
+	status := PVCStatus{
+		Name:        pvc.Name,
+		Phase:       pvc.Status.Phase,
+		VolumeName:  pvc.Spec.VolumeName,
+		AccessModes: pvc.Spec.AccessModes,
+		Conditions:  pvc.Status.Conditions,
+	}
+	if pvc.Spec.StorageClassName != nil {
+		status.StorageClassName = *pvc.Spec.StorageClassName
+	}
+	if requested, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		status.Requested = requested.String()
+	}
+	if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		status.Capacity = capacity.String()
+	}
+	return status
+}
+
+// storageClassSummary fetches a StorageClass, returning nil when it can't be read
+// (it may not exist, or the user may lack cluster-scoped read access)
+func (c *Client) storageClassSummary(ctx context.Context, name string) *StorageClassSummary {
+	sc, err := c.clientset.StorageV1().StorageClasses().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	return &StorageClassSummary{
+		Provisioner:          sc.Provisioner,
+		VolumeBindingMode:    sc.VolumeBindingMode,
+		ReclaimPolicy:        sc.ReclaimPolicy,
+		AllowVolumeExpansion: sc.AllowVolumeExpansion,
+		Parameters:           sc.Parameters,
+	}
+}
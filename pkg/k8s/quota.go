@@ -0,0 +1,70 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// QuotaHeadroomKey holds warnings about ResourceQuotas that are close to their hard limits
+const QuotaHeadroomKey = "resourcequota_headroom"
+
+// quotaWarnRatio is the share of a quota's hard limit above which it is flagged
+const quotaWarnRatio = 0.9
+
+// gatherNamespacePolicies adds the namespace's ResourceQuotas and LimitRanges, which silently
+// reject or default pods of any workload in it
+func (c *Client) gatherNamespacePolicies(ctx context.Context, namespace string, result map[string]interface{}) {
+	if namespace == metav1.NamespaceAll {
+		return
+	}
+
+	quotas, err := c.clientset.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
+	if err == nil && len(quotas.Items) > 0 {
+		result["resourcequotas"] = quotas
+	}
+	limitRanges, err := c.clientset.CoreV1().LimitRanges(namespace).List(ctx, metav1.ListOptions{})
+	if err == nil && len(limitRanges.Items) > 0 {
+		result["limitranges"] = limitRanges
+	}
+}
+
+// quotaHeadroom returns a warning for every quota resource that has used most of its hard limit
+func quotaHeadroom(result map[string]interface{}) []string {
+	var quotas []corev1.ResourceQuota
+	for _, value := range result {
+		switch v := value.(type) {
+		case *corev1.ResourceQuotaList:
+			quotas = append(quotas, v.Items...)
+		case *corev1.ResourceQuota:
+			quotas = append(quotas, *v)
+		}
+	}
+
+	var warnings []string
+	for _, quota := range quotas {
+		for name, hard := range quota.Status.Hard {
+			used := quota.Status.Used[name]
+			hardValue := hard.AsApproximateFloat64()
+			if hardValue <= 0 {
+				warnings = append(warnings, fmt.Sprintf("%s/%s: %s has a hard limit of 0, nothing can be created", quota.Namespace, quota.Name, name))
+				continue
+			}
+
+			ratio := used.AsApproximateFloat64() / hardValue
+			if ratio < quotaWarnRatio {
+				continue
+			}
+			remaining := hard.DeepCopy()
+			remaining.Sub(used)
+			warnings = append(warnings, fmt.Sprintf("%s/%s: %s %s of %s used (%.0f%%, %s left)",
+				quota.Namespace, quota.Name, name, used.String(), hard.String(), ratio*100, remaining.String()))
+		}
+	}
+
+	sort.Strings(warnings)
+	return warnings
+}
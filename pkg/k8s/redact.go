@@ -0,0 +1,169 @@
+package k8s
+
+import (
+	"regexp"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// DefaultRedactPattern matches keys whose values are masked before resources reach the LLM
+	DefaultRedactPattern = `(?i)(password|token|secret|key|credential)`
+
+	// RedactedValue replaces masked values
+	RedactedValue = "[REDACTED]"
+
+	// lastAppliedAnnotation holds a full copy of the applied object, including any ConfigMap data.
+	// Gathered objects have it stripped; Redact masks it on objects fetched outside GatherResources.
+	lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+// SetRedactPattern sets the pattern of ConfigMap, annotation and env var keys whose values are masked.
+// A nil pattern disables redaction.
+func (c *Client) SetRedactPattern(pattern *regexp.Regexp) {
+	c.redactPattern = pattern
+}
+
+// Redact masks sensitive values in a gathered object in place
+func (c *Client) Redact(obj interface{}) {
+	if c.redactPattern == nil {
+		return
+	}
+	redactObject(obj, c.redactPattern)
+}
+
+// redactObject masks values of matching ConfigMap data keys, annotations and container env vars in obj, expanding lists
+func redactObject(obj interface{}, pattern *regexp.Regexp) {
+	runtimeObj, ok := obj.(runtime.Object)
+	if !ok {
+		return
+	}
+
+	if meta.IsListType(runtimeObj) {
+		items, err := meta.ExtractList(runtimeObj)
+		if err != nil {
+			return
+		}
+		for _, item := range items {
+			redactObject(item, pattern)
+		}
+		return
+	}
+
+	switch o := runtimeObj.(type) {
+	case *corev1.ConfigMap:
+		redactMap(o.Data, pattern)
+		for key := range o.BinaryData {
+			if pattern.MatchString(key) {
+				o.BinaryData[key] = []byte(RedactedValue)
+			}
+		}
+	case *corev1.Pod:
+		redactPodSpec(&o.Spec, pattern)
+	case *appsv1.Deployment:
+		redactPodSpec(&o.Spec.Template.Spec, pattern)
+	case *appsv1.StatefulSet:
+		redactPodSpec(&o.Spec.Template.Spec, pattern)
+	case *appsv1.DaemonSet:
+		redactPodSpec(&o.Spec.Template.Spec, pattern)
+	case *appsv1.ReplicaSet:
+		redactPodSpec(&o.Spec.Template.Spec, pattern)
+	case *batchv1.Job:
+		redactPodSpec(&o.Spec.Template.Spec, pattern)
+	case *batchv1.CronJob:
+		redactPodSpec(&o.Spec.JobTemplate.Spec.Template.Spec, pattern)
+	case *unstructured.Unstructured:
+		if o.GetKind() == "ConfigMap" {
+			if data, found, _ := unstructured.NestedStringMap(o.Object, "data"); found {
+				redactMap(data, pattern)
+				_ = unstructured.SetNestedStringMap(o.Object, data, "data")
+			}
+		}
+		redactUnstructuredPodSpecs(o, pattern)
+	}
+
+	if accessor, err := meta.Accessor(runtimeObj); err == nil {
+		annotations := accessor.GetAnnotations()
+		if len(annotations) > 0 {
+			redactMap(annotations, pattern)
+			if _, ok := annotations[lastAppliedAnnotation]; ok {
+				annotations[lastAppliedAnnotation] = RedactedValue
+			}
+			accessor.SetAnnotations(annotations)
+		}
+	}
+}
+
+// redactPodSpec masks literal env values of matching variables, keeping the name and any valueFrom reference
+func redactPodSpec(spec *corev1.PodSpec, pattern *regexp.Regexp) {
+	for i := range spec.InitContainers {
+		redactEnv(spec.InitContainers[i].Env, pattern)
+	}
+	for i := range spec.Containers {
+		redactEnv(spec.Containers[i].Env, pattern)
+	}
+	for i := range spec.EphemeralContainers {
+		redactEnv(spec.EphemeralContainers[i].Env, pattern)
+	}
+}
+
+func redactEnv(env []corev1.EnvVar, pattern *regexp.Regexp) {
+	for i := range env {
+		if env[i].Value != "" && pattern.MatchString(env[i].Name) {
+			env[i].Value = RedactedValue
+		}
+	}
+}
+
+// podSpecPaths are where pod specs live in workloads fetched through the dynamic client
+var podSpecPaths = [][]string{
+	{"spec"},
+	{"spec", "template", "spec"},
+	{"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// redactUnstructuredPodSpecs masks env values in any pod spec found in an unstructured object
+func redactUnstructuredPodSpecs(obj *unstructured.Unstructured, pattern *regexp.Regexp) {
+	for _, path := range podSpecPaths {
+		for _, field := range []string{"initContainers", "containers", "ephemeralContainers"} {
+			containersPath := append(append([]string{}, path...), field)
+			containers, found, _ := unstructured.NestedSlice(obj.Object, containersPath...)
+			if !found {
+				continue
+			}
+
+			for _, c := range containers {
+				container, ok := c.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				env, _ := container["env"].([]interface{})
+				for _, e := range env {
+					envVar, ok := e.(map[string]interface{})
+					if !ok {
+						continue
+					}
+					name, _ := envVar["name"].(string)
+					if _, hasValue := envVar["value"]; hasValue && pattern.MatchString(name) {
+						envVar["value"] = RedactedValue
+					}
+				}
+			}
+			_ = unstructured.SetNestedSlice(obj.Object, containers, containersPath...)
+		}
+	}
+}
+
+// redactMap masks the values of keys matching pattern
+func redactMap(values map[string]string, pattern *regexp.Regexp) {
+	for key := range values {
+		if pattern.MatchString(key) {
+			values[key] = RedactedValue
+		}
+	}
+}
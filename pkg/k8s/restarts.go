@@ -0,0 +1,116 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// RestartsKey holds the crash-looping and frequently restarting containers found in the gathered pods
+const RestartsKey = "container_restarts"
+
+// ReasonCrashLoopBackOff is the waiting reason of a container the kubelet is backing off restarting
+const ReasonCrashLoopBackOff = "CrashLoopBackOff"
+
+const (
+	// minRestarts is the restart count from which a container is called out
+	minRestarts = 5
+	// stormRestartsPerHour is the restart rate from which a container is in a restart storm
+	stormRestartsPerHour = 3.0
+	// maxRestarts caps how many restarting containers are called out, storms first
+	maxRestarts = 20
+)
+
+// ContainerRestarts is a container that is in CrashLoopBackOff or has restarted often
+type ContainerRestarts struct {
+	Namespace       string  `json:"namespace"`
+	Pod             string  `json:"pod"`
+	Container       string  `json:"container"`
+	Restarts        int32   `json:"restarts"`
+	WaitingReason   string  `json:"waitingReason,omitempty"`
+	PodAgeMinutes   int     `json:"podAgeMinutes"`
+	RestartsPerHour float64 `json:"restartsPerHour"`
+	Storm           bool    `json:"storm"`
+}
+
+// containerRestarts scans every gathered pod for containers in CrashLoopBackOff or with a high
+// restart count. Both are only visible as raw numbers in containerStatuses otherwise.
+func containerRestarts(result map[string]interface{}, now time.Time) []ContainerRestarts {
+	var pods []corev1.Pod
+	for _, value := range result {
+		switch v := value.(type) {
+		case *corev1.Pod:
+			pods = append(pods, *v)
+		case *corev1.PodList:
+			pods = append(pods, v.Items...)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var restarts []ContainerRestarts
+	for _, pod := range pods {
+		started := pod.CreationTimestamp.Time
+		if pod.Status.StartTime != nil {
+			started = pod.Status.StartTime.Time
+		}
+		age := now.Sub(started)
+
+		var statuses []corev1.ContainerStatus
+		statuses = append(statuses, pod.Status.InitContainerStatuses...)
+		statuses = append(statuses, pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			key := fmt.Sprintf("%s/%s/%s", pod.Namespace, pod.Name, status.Name)
+			if seen[key] {
+				continue
+			}
+
+			var waitingReason string
+			if status.State.Waiting != nil && status.State.Waiting.Reason == ReasonCrashLoopBackOff {
+				waitingReason = ReasonCrashLoopBackOff
+			}
+			if waitingReason == "" && status.RestartCount < minRestarts {
+				continue
+			}
+
+			seen[key] = true
+			rate := restartRate(status.RestartCount, age)
+			restarts = append(restarts, ContainerRestarts{
+				Namespace:       pod.Namespace,
+				Pod:             pod.Name,
+				Container:       status.Name,
+				Restarts:        status.RestartCount,
+				WaitingReason:   waitingReason,
+				PodAgeMinutes:   int(age.Minutes()),
+				RestartsPerHour: rate,
+				Storm:           status.RestartCount >= minRestarts && rate >= stormRestartsPerHour,
+			})
+		}
+	}
+
+	sort.Slice(restarts, func(i, j int) bool {
+		a, b := restarts[i], restarts[j]
+		if a.Storm != b.Storm {
+			return a.Storm
+		}
+		if a.RestartsPerHour != b.RestartsPerHour {
+			return a.RestartsPerHour > b.RestartsPerHour
+		}
+		return a.Namespace+"/"+a.Pod+"/"+a.Container < b.Namespace+"/"+b.Pod+"/"+b.Container
+	})
+	if len(restarts) > maxRestarts {
+		restarts = restarts[:maxRestarts]
+	}
+	return restarts
+}
+
+// restartRate is the average number of restarts per hour since the pod started. Pods younger
+// than an hour count as an hour old, so a handful of restarts in a new pod isn't overstated.
+func restartRate(restarts int32, age time.Duration) float64 {
+	hours := age.Hours()
+	if hours < 1 {
+		hours = 1
+	}
+	return float64(restarts) / hours
+}
@@ -0,0 +1,74 @@
+package k8s
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// ScalingEvent is a Kubernetes event recording a change of a workload's replica count
+type ScalingEvent struct {
+	Time    time.Time `json:"time"`
+	Object  string    `json:"object"` // hpa/name or the workload's kind/name
+	Reason  string    `json:"reason"`
+	Message string    `json:"message"`
+}
+
+// FromHPA reports whether an autoscaler, rather than the workload itself, recorded the event
+func (e ScalingEvent) FromHPA() bool {
+	return e.Reason == "SuccessfulRescale"
+}
+
+// scalingEventReasons are the event reasons that record a replica count change
+var scalingEventReasons = map[string]bool{
+	"SuccessfulRescale": true, // HPA, including the ones KEDA manages
+	"ScalingReplicaSet": true, // Deployment, on manual scales and rollouts
+	"SuccessfulCreate":  true, // StatefulSet and ReplicaSet pods
+	"SuccessfulDelete":  true,
+}
+
+// ScalingEvents returns the events recording why a workload scaled, oldest first: rescales by the
+// HPAs targeting it, including KEDA's, and the workload's own scaling events. Kubernetes keeps
+// events for an hour by default, so older replica changes have none.
+func (c *Client) ScalingEvents(ctx context.Context, namespace, kind, name string) ([]ScalingEvent, error) {
+	objects := [][2]string{{kind, name}}
+	hpas, err := c.clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, metav1.ListOptions{})
+	if err == nil {
+		for _, hpa := range hpas.Items {
+			if strings.EqualFold(hpa.Spec.ScaleTargetRef.Kind, kind) && hpa.Spec.ScaleTargetRef.Name == name {
+				objects = append(objects, [2]string{"HorizontalPodAutoscaler", hpa.Name})
+			}
+		}
+	}
+
+	var events []ScalingEvent
+	for _, object := range objects {
+		list, err := c.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+			FieldSelector: fields.Set{
+				"involvedObject.kind": object[0],
+				"involvedObject.name": object[1],
+			}.String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		label := strings.ToLower(object[0]) + "/" + object[1]
+		if object[0] == "HorizontalPodAutoscaler" {
+			label = "hpa/" + object[1]
+		}
+		for i := range list.Items {
+			event := &list.Items[i]
+			if scalingEventReasons[event.Reason] {
+				events = append(events, ScalingEvent{Time: eventTime(event), Object: label, Reason: event.Reason, Message: event.Message})
+			}
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	return events, nil
+}
@@ -0,0 +1,116 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TerminationsKey holds the OOMKilled and failed container terminations found in the gathered pods
+const TerminationsKey = "container_terminations"
+
+// maxTerminations caps how many terminations are called out, OOM kills first
+const maxTerminations = 20
+
+// Termination reasons that are called out before analysis
+const (
+	ReasonOOMKilled = "OOMKilled"
+	ReasonError     = "Error"
+)
+
+// ContainerTermination is a container that was OOMKilled or exited with an error, either in
+// its current state or in its last state before a restart
+type ContainerTermination struct {
+	Namespace   string     `json:"namespace"`
+	Pod         string     `json:"pod"`
+	Container   string     `json:"container"`
+	Reason      string     `json:"reason"`
+	ExitCode    int32      `json:"exitCode"`
+	Restarts    int32      `json:"restarts"`
+	MemoryLimit string     `json:"memoryLimit,omitempty"`
+	FinishedAt  *time.Time `json:"finishedAt,omitempty"`
+	Current     bool       `json:"current"`
+}
+
+// containerTerminations scans every gathered pod for OOMKilled and Error terminations. The
+// reason lives in containerStatuses[].lastState.terminated, which is easy to miss in a full pod.
+func containerTerminations(result map[string]interface{}) []ContainerTermination {
+	var pods []corev1.Pod
+	for _, value := range result {
+		switch v := value.(type) {
+		case *corev1.Pod:
+			pods = append(pods, *v)
+		case *corev1.PodList:
+			pods = append(pods, v.Items...)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var terminations []ContainerTermination
+	for _, pod := range pods {
+		limits := make(map[string]string)
+		for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+			for _, container := range containers {
+				if limit, ok := container.Resources.Limits[corev1.ResourceMemory]; ok {
+					limits[container.Name] = limit.String()
+				}
+			}
+		}
+
+		var statuses []corev1.ContainerStatus
+		statuses = append(statuses, pod.Status.InitContainerStatuses...)
+		statuses = append(statuses, pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			key := fmt.Sprintf("%s/%s/%s", pod.Namespace, pod.Name, status.Name)
+			if seen[key] {
+				continue
+			}
+
+			terminated, current := status.State.Terminated, true
+			if !isFailedTermination(terminated) {
+				terminated, current = status.LastTerminationState.Terminated, false
+			}
+			if !isFailedTermination(terminated) {
+				continue
+			}
+
+			seen[key] = true
+			terminations = append(terminations, ContainerTermination{
+				Namespace:   pod.Namespace,
+				Pod:         pod.Name,
+				Container:   status.Name,
+				Reason:      terminated.Reason,
+				ExitCode:    terminated.ExitCode,
+				Restarts:    status.RestartCount,
+				MemoryLimit: limits[status.Name],
+				FinishedAt:  timeOrNil(&terminated.FinishedAt),
+				Current:     current,
+			})
+		}
+	}
+
+	sort.Slice(terminations, func(i, j int) bool {
+		a, b := terminations[i], terminations[j]
+		if (a.Reason == ReasonOOMKilled) != (b.Reason == ReasonOOMKilled) {
+			return a.Reason == ReasonOOMKilled
+		}
+		if a.Restarts != b.Restarts {
+			return a.Restarts > b.Restarts
+		}
+		return a.Namespace+"/"+a.Pod+"/"+a.Container < b.Namespace+"/"+b.Pod+"/"+b.Container
+	})
+	if len(terminations) > maxTerminations {
+		terminations = terminations[:maxTerminations]
+	}
+	return terminations
+}
+
+// isFailedTermination reports whether a container was OOMKilled or exited with an error
+func isFailedTermination(terminated *corev1.ContainerStateTerminated) bool {
+	if terminated == nil {
+		return false
+	}
+	return terminated.Reason == ReasonOOMKilled || (terminated.Reason == ReasonError && terminated.ExitCode != 0)
+}
@@ -0,0 +1,29 @@
+package k8s
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// VerboseOutput receives a line per Kubernetes API request when set (e.g. by --verbose).
+// It must be set before NewClient, which only installs the tracing transport when it is.
+var VerboseOutput io.Writer
+
+// tracingTransport logs each API request with its status and duration
+type tracingTransport struct {
+	next http.RoundTripper
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		fmt.Fprintf(VerboseOutput, "[k8s] %s %s failed after %s: %v\n", req.Method, req.URL.RequestURI(), elapsed, err)
+		return nil, err
+	}
+	fmt.Fprintf(VerboseOutput, "[k8s] %s %s %d (%s)\n", req.Method, req.URL.RequestURI(), resp.StatusCode, elapsed)
+	return resp, nil
+}
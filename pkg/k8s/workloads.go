@@ -0,0 +1,124 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// customWorkload describes how a custom resource selects the pods it manages, for kinds that
+// don't keep a label selector in spec.selector like Deployments. Exactly one way is set.
+type customWorkload struct {
+	// selectorPath is where a metav1.LabelSelector lives
+	selectorPath []string
+	// workloadRefPath is where a reference to a Deployment or ReplicaSet holding the selector
+	// lives, used when the object has no selector of its own
+	workloadRefPath []string
+	// podLabel is a label set on every pod to the name of the object
+	podLabel string
+}
+
+// customWorkloads maps "Kind.group" of known custom workloads to how they select their pods.
+// Add an entry here to discover the pods of another CRD; unknown kinds fall back to spec.selector.
+var customWorkloads = map[string]customWorkload{
+	// Argo Rollouts either embed a selector or reference an existing Deployment with workloadRef
+	"Rollout.argoproj.io": {selectorPath: []string{"spec", "selector"}, workloadRefPath: []string{"spec", "workloadRef"}},
+	// OpenKruise workloads mirror the built-in ones
+	"CloneSet.apps.kruise.io":    {selectorPath: []string{"spec", "selector"}},
+	"StatefulSet.apps.kruise.io": {selectorPath: []string{"spec", "selector"}},
+	// Knative labels the pods of every revision with the service name
+	"Service.serving.knative.dev": {podLabel: "serving.knative.dev/service"},
+}
+
+// workloadPodSelector returns the selector of the pods obj manages, or nil when it doesn't
+// manage pods or its selector can't be resolved
+func (c *Client) workloadPodSelector(ctx context.Context, namespace string, obj *unstructured.Unstructured) labels.Selector {
+	gvk := obj.GroupVersionKind()
+	workload, ok := customWorkloads[gvk.Kind+"."+gvk.Group]
+	if !ok {
+		return nestedLabelSelector(obj, "spec", "selector")
+	}
+
+	if workload.podLabel != "" {
+		return labels.SelectorFromSet(labels.Set{workload.podLabel: obj.GetName()})
+	}
+	if selector := nestedLabelSelector(obj, workload.selectorPath...); selector != nil {
+		return selector
+	}
+	if workload.workloadRefPath != nil {
+		return c.workloadRefSelector(ctx, namespace, obj, workload.workloadRefPath)
+	}
+	return nil
+}
+
+// nestedLabelSelector parses the metav1.LabelSelector at path. An empty selector would match
+// every pod in the namespace, so it counts as none.
+func nestedLabelSelector(obj *unstructured.Unstructured, path ...string) labels.Selector {
+	value, found, err := unstructured.NestedMap(obj.Object, path...)
+	if err != nil || !found {
+		return nil
+	}
+
+	var labelSelector metav1.LabelSelector
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(value, &labelSelector); err != nil {
+		return nil
+	}
+	if len(labelSelector.MatchLabels) == 0 && len(labelSelector.MatchExpressions) == 0 {
+		return nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(&labelSelector)
+	if err != nil {
+		return nil
+	}
+	return selector
+}
+
+// workloadRefSelector resolves the selector of the Deployment or ReplicaSet a workload references
+func (c *Client) workloadRefSelector(ctx context.Context, namespace string, obj *unstructured.Unstructured, path []string) labels.Selector {
+	kind, _, _ := unstructured.NestedString(obj.Object, append(append([]string{}, path...), "kind")...)
+	name, _, _ := unstructured.NestedString(obj.Object, append(append([]string{}, path...), "name")...)
+	if name == "" {
+		return nil
+	}
+
+	var labelSelector *metav1.LabelSelector
+	switch kind {
+	case "Deployment":
+		deployment, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil
+		}
+		labelSelector = deployment.Spec.Selector
+	case "ReplicaSet":
+		replicaSet, err := c.clientset.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil
+		}
+		labelSelector = replicaSet.Spec.Selector
+	default:
+		return nil
+	}
+
+	if labelSelector == nil || (len(labelSelector.MatchLabels) == 0 && len(labelSelector.MatchExpressions) == 0) {
+		return nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil
+	}
+	return selector
+}
+
+// getPodsForWorkload gets the pods managed by a workload fetched through the dynamic client
+func (c *Client) getPodsForWorkload(ctx context.Context, namespace string, obj *unstructured.Unstructured) (*corev1.PodList, error) {
+	selector := c.workloadPodSelector(ctx, namespace, obj)
+	if selector == nil {
+		return nil, fmt.Errorf("no selector found")
+	}
+	return c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+}
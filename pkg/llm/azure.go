@@ -0,0 +1,127 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultAzureOpenAIAPIVersion is used when AZURE_OPENAI_API_VERSION is not set
+const DefaultAzureOpenAIAPIVersion = "2024-06-01"
+
+type AzureOpenAI struct {
+	endpoint   string
+	apiKey     string
+	deployment string
+	apiVersion string
+	client     *http.Client
+	maxRetries int
+	options    GenerationOptions
+}
+
+func NewAzureOpenAI(endpoint, apiKey, deployment, apiVersion string) *AzureOpenAI {
+	if apiVersion == "" {
+		apiVersion = DefaultAzureOpenAIAPIVersion
+	}
+	return &AzureOpenAI{
+		endpoint:   strings.TrimRight(endpoint, "/"),
+		apiKey:     apiKey,
+		deployment: deployment,
+		apiVersion: apiVersion,
+		client:     &http.Client{Timeout: 60 * time.Second},
+		maxRetries: DefaultMaxRetries,
+	}
+}
+
+func (a *AzureOpenAI) Chat(prompt string) (string, error) {
+	return a.ChatMessages([]Message{{Role: RoleUser, Content: prompt}})
+}
+
+// ChatMessages sends a multi-turn conversation and returns the assistant's reply
+func (a *AzureOpenAI) ChatMessages(messages []Message) (string, error) {
+	// The model is chosen by the deployment in the URL, so it is not part of the body
+	body := map[string]interface{}{
+		"messages":    messages,
+		"max_tokens":  a.options.maxTokens(),
+		"temperature": a.options.Temperature,
+	}
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+
+	statusCode, respBytes, err := sendWithRetry(a.client, a.maxRetries, func() (*http.Request, error) {
+		req, err := http.NewRequest("POST", a.chatURL(), bytes.NewBuffer(jsonBody))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("api-key", a.apiKey)
+		return req, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if statusCode != http.StatusOK {
+		return "", newAPIError("Azure OpenAI", statusCode, respBytes)
+	}
+
+	// Azure OpenAI uses the OpenAI response structure
+	var azureResp struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Error struct {
+			Message string `json:"message"`
+			Code    string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(respBytes, &azureResp); err != nil {
+		return "", err
+	}
+	if azureResp.Error.Message != "" {
+		return "", newAPIError("Azure OpenAI", 0, respBytes)
+	}
+	if len(azureResp.Choices) == 0 {
+		return "", fmt.Errorf("empty response from Azure OpenAI")
+	}
+	return azureResp.Choices[0].Message.Content, nil
+}
+
+// chatURL builds {endpoint}/openai/deployments/{deployment}/chat/completions?api-version=...
+func (a *AzureOpenAI) chatURL() string {
+	return fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+		a.endpoint, url.PathEscape(a.deployment), url.QueryEscape(a.apiVersion))
+}
+
+// BaseURL returns the Azure OpenAI resource the client sends requests to
+func (a *AzureOpenAI) BaseURL() string {
+	return a.endpoint
+}
+
+// GetModel returns the Azure deployment used by this client
+func (a *AzureOpenAI) GetModel() string {
+	return a.deployment
+}
+
+// SetMaxRetries sets how many times a failed request is retried
+func (a *AzureOpenAI) SetMaxRetries(maxRetries int) {
+	a.maxRetries = maxRetries
+}
+
+// SetGenerationOptions sets the temperature and response length of each request
+func (a *AzureOpenAI) SetGenerationOptions(options GenerationOptions) {
+	a.options = options
+}
+
+// generationOptions returns the sampling settings set with SetGenerationOptions
+func (a *AzureOpenAI) generationOptions() GenerationOptions {
+	return a.options
+}
@@ -0,0 +1,133 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// DefaultBedrockModel is the Claude model ID used when none is configured
+const DefaultBedrockModel = "anthropic.claude-3-5-sonnet-20240620-v1:0"
+
+// Bedrock calls Claude models through the Amazon Bedrock InvokeModel API
+type Bedrock struct {
+	region      string
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+	client      *http.Client
+	model       string
+	maxRetries  int
+	options     GenerationOptions
+}
+
+func NewBedrockWithModel(region string, credentials aws.CredentialsProvider, model string) *Bedrock {
+	if model == "" {
+		model = DefaultBedrockModel
+	}
+	return &Bedrock{
+		region:      region,
+		credentials: credentials,
+		signer:      v4.NewSigner(),
+		client:      &http.Client{Timeout: 60 * time.Second},
+		model:       model,
+		maxRetries:  DefaultMaxRetries,
+	}
+}
+
+func (b *Bedrock) Chat(prompt string) (string, error) {
+	return b.ChatMessages([]Message{{Role: RoleUser, Content: prompt}})
+}
+
+// ChatMessages sends a multi-turn conversation and returns the assistant's reply
+func (b *Bedrock) ChatMessages(messages []Message) (string, error) {
+	system, messages := splitSystem(messages)
+
+	// Bedrock takes the model from the URL and the API version from the body
+	body := map[string]interface{}{
+		"anthropic_version": "bedrock-2023-05-31",
+		"messages":          messages,
+		"max_tokens":        b.options.maxTokens(),
+		"temperature":       b.options.Temperature,
+	}
+	if system != "" {
+		body["system"] = system
+	}
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+	payloadHash := sha256.Sum256(jsonBody)
+
+	statusCode, respBytes, err := sendWithRetry(b.client, b.maxRetries, func() (*http.Request, error) {
+		req, err := http.NewRequest("POST", b.invokeURL(), bytes.NewBuffer(jsonBody))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+
+		// Sign every attempt so retries carry a fresh signature timestamp
+		ctx := context.Background()
+		creds, err := b.credentials.Retrieve(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+		}
+		if err := b.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(payloadHash[:]), "bedrock", b.region, time.Now()); err != nil {
+			return nil, fmt.Errorf("failed to sign Bedrock request: %w", err)
+		}
+		return req, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if statusCode != http.StatusOK {
+		return "", newAPIError("Bedrock", statusCode, respBytes)
+	}
+
+	return parseClaudeResponse(respBytes, "Bedrock")
+}
+
+// invokeURL builds the bedrock-runtime InvokeModel endpoint for the model
+func (b *Bedrock) invokeURL() string {
+	return fmt.Sprintf("%s/model/%s/invoke", b.BaseURL(), url.PathEscape(b.model))
+}
+
+// BaseURL returns the regional bedrock-runtime API the client sends requests to
+func (b *Bedrock) BaseURL() string {
+	return fmt.Sprintf("https://bedrock-runtime.%s.amazonaws.com", b.region)
+}
+
+// GetModel returns the Bedrock model ID used by this client
+func (b *Bedrock) GetModel() string {
+	return b.model
+}
+
+// SetMaxRetries sets how many times a failed request is retried
+func (b *Bedrock) SetMaxRetries(maxRetries int) {
+	b.maxRetries = maxRetries
+}
+
+// SetGenerationOptions sets the temperature and response length of each request
+func (b *Bedrock) SetGenerationOptions(options GenerationOptions) {
+	b.options = options
+}
+
+// generationOptions returns the sampling settings set with SetGenerationOptions
+func (b *Bedrock) generationOptions() GenerationOptions {
+	return b.options
+}
+
+// maxTemperature is the highest temperature Claude accepts
+func (b *Bedrock) maxTemperature() float64 {
+	return maxClaudeTemperature
+}
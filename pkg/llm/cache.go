@@ -0,0 +1,234 @@
+package llm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultCacheTTL is how long a cached response is reused
+const DefaultCacheTTL = 24 * time.Hour
+
+// DefaultCacheDir returns the directory responses are cached in, ~/.cache/kubectl-ai/llm on Linux
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("locate cache directory: %w", err)
+	}
+	return filepath.Join(dir, "kubectl-ai", "llm"), nil
+}
+
+// Cached wraps an LLM client and stores its responses on disk, keyed by a hash of the provider,
+// endpoint, model, generation options and the exact conversation. A cache hit skips the API call entirely.
+// Cached forwards the optional interfaces (streaming, JSON mode, retries, options) to the client.
+type Cached struct {
+	llm     LLM
+	dir     string
+	ttl     time.Duration
+	refresh bool
+	options GenerationOptions
+}
+
+// cacheEntry is the file stored for each cached response
+type cacheEntry struct {
+	CreatedAt time.Time `json:"created_at"`
+	Response  string    `json:"response"`
+}
+
+// Request kinds, part of the cache key since JSON mode can change a response
+const (
+	kindChat = "chat"
+	kindJSON = "json"
+)
+
+// NewCached caches the responses of l in dir for ttl. With refresh set, cached responses are
+// ignored but new ones are still stored, forcing a fresh call that updates the cache.
+func NewCached(l LLM, dir string, ttl time.Duration, refresh bool) *Cached {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &Cached{llm: l, dir: dir, ttl: ttl, refresh: refresh}
+}
+
+// Unwrap returns the client behind a Cached LLM, or l itself
+func Unwrap(l LLM) LLM {
+	if c, ok := l.(*Cached); ok {
+		return c.llm
+	}
+	return l
+}
+
+// TTL returns how long responses are reused
+func (c *Cached) TTL() time.Duration {
+	return c.ttl
+}
+
+func (c *Cached) Chat(prompt string) (string, error) {
+	return c.cached(kindChat, []Message{{Role: RoleUser, Content: prompt}}, func() (string, error) {
+		return c.llm.Chat(prompt)
+	})
+}
+
+// ChatMessages returns the cached reply to the conversation, or asks the client
+func (c *Cached) ChatMessages(messages []Message) (string, error) {
+	return c.cached(kindChat, messages, func() (string, error) {
+		return ChatMessages(c.llm, messages)
+	})
+}
+
+// ChatJSON returns the cached JSON reply to the conversation, or asks the client
+func (c *Cached) ChatJSON(messages []Message) (string, error) {
+	return c.cached(kindJSON, messages, func() (string, error) {
+		return ChatJSON(c.llm, messages)
+	})
+}
+
+// ChatStream sends a cached response as a single chunk. Otherwise the client's stream is
+// forwarded and cached once complete.
+func (c *Cached) ChatStream(prompt string, out chan<- string) error {
+	return c.ChatStreamMessages([]Message{{Role: RoleUser, Content: prompt}}, out)
+}
+
+// ChatStreamMessages works like ChatStream for a whole conversation
+func (c *Cached) ChatStreamMessages(messages []Message, out chan<- string) error {
+	key := c.key(kindChat, messages)
+	if response, ok := c.load(key); ok {
+		out <- response
+		close(out)
+		return nil
+	}
+
+	chunks := make(chan string)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- ChatStreamMessages(c.llm, messages, chunks)
+	}()
+
+	var response []byte
+	for chunk := range chunks {
+		response = append(response, chunk...)
+		out <- chunk
+	}
+	close(out)
+
+	err := <-errs
+	if err == nil {
+		c.store(key, string(response))
+	}
+	return err
+}
+
+// SetMaxRetries forwards to the client if it supports retries
+func (c *Cached) SetMaxRetries(maxRetries int) {
+	SetMaxRetries(c.llm, maxRetries)
+}
+
+// SetGenerationOptions forwards to the client and makes the options part of the cache key
+func (c *Cached) SetGenerationOptions(options GenerationOptions) {
+	c.options = options
+	if t, ok := c.llm.(Tunable); ok {
+		t.SetGenerationOptions(options)
+	}
+}
+
+// generationOptions returns the sampling settings set with SetGenerationOptions
+func (c *Cached) generationOptions() GenerationOptions {
+	return c.options
+}
+
+// CachesPrompts forwards to the client, so it still gets cacheable system prompts
+func (c *Cached) CachesPrompts() bool {
+	return CachesPrompts(c.llm)
+}
+
+// GetModel returns the client's model
+func (c *Cached) GetModel() string {
+	if m, ok := c.llm.(interface{ GetModel() string }); ok {
+		return m.GetModel()
+	}
+	return ""
+}
+
+// cached returns the stored response for the request or calls fetch and stores its result
+func (c *Cached) cached(kind string, messages []Message, fetch func() (string, error)) (string, error) {
+	key := c.key(kind, messages)
+	if response, ok := c.load(key); ok {
+		return response, nil
+	}
+
+	response, err := fetch()
+	if err != nil {
+		return "", err
+	}
+	c.store(key, response)
+	return response, nil
+}
+
+// baseURLer is implemented by LLM clients that can talk to different endpoints
+type baseURLer interface {
+	BaseURL() string
+}
+
+// key hashes everything that can change a response, including the endpoint, since two gateways
+// can serve different models under the same name
+func (c *Cached) key(kind string, messages []Message) string {
+	var endpoint string
+	if b, ok := c.llm.(baseURLer); ok {
+		endpoint = b.BaseURL()
+	}
+	data, _ := json.Marshal(struct {
+		Client   string            `json:"client"`
+		Endpoint string            `json:"endpoint,omitempty"`
+		Model    string            `json:"model"`
+		Options  GenerationOptions `json:"options"`
+		Kind     string            `json:"kind"`
+		Messages []Message         `json:"messages"`
+	}{fmt.Sprintf("%T", c.llm), endpoint, c.GetModel(), c.options, kind, messages})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// load returns a stored response that hasn't expired. Unreadable entries count as misses.
+func (c *Cached) load(key string) (string, bool) {
+	if c.refresh {
+		return "", false
+	}
+	data, err := os.ReadFile(filepath.Join(c.dir, key+".json"))
+	if err != nil {
+		return "", false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || time.Since(entry.CreatedAt) > c.ttl {
+		return "", false
+	}
+	if VerboseOutput != nil {
+		fmt.Fprintf(VerboseOutput, "[llm] cache hit %s (%s old)\n", key[:12], time.Since(entry.CreatedAt).Round(time.Second))
+	}
+	return entry.Response, true
+}
+
+// store saves a response. Failing to write the cache never fails the request.
+func (c *Cached) store(key, response string) {
+	data, err := json.Marshal(cacheEntry{CreatedAt: time.Now().UTC(), Response: response})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return
+	}
+
+	// Write to a temporary file and rename it so concurrent runs never read a partial entry
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil || os.Rename(tmp.Name(), filepath.Join(c.dir, key+".json")) != nil {
+		os.Remove(tmp.Name())
+	}
+}
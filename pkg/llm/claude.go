@@ -10,65 +10,132 @@ import (
 )
 
 type Claude struct {
-	apiKey string
-	client *http.Client
-	model  string
+	apiKey     string
+	client     *http.Client
+	stream     *http.Client // No total timeout, see newStreamingClient
+	model      string
+	maxRetries int
+	options    GenerationOptions
 }
 
 func NewClaude(apiKey string) *Claude {
 	return &Claude{
-		apiKey: apiKey,
-		client: &http.Client{Timeout: 60 * time.Second},
-		model:  "claude-sonnet-4-20250514",
+		apiKey:     apiKey,
+		client:     &http.Client{Timeout: 60 * time.Second},
+		stream:     newStreamingClient(),
+		model:      "claude-sonnet-4-20250514",
+		maxRetries: DefaultMaxRetries,
 	}
 }
 
 func NewClaudeWithModel(apiKey, model string) *Claude {
 	return &Claude{
-		apiKey: apiKey,
-		client: &http.Client{Timeout: 60 * time.Second},
-		model:  model,
+		apiKey:     apiKey,
+		client:     &http.Client{Timeout: 60 * time.Second},
+		stream:     newStreamingClient(),
+		model:      model,
+		maxRetries: DefaultMaxRetries,
 	}
 }
 
 func (c *Claude) Chat(prompt string) (string, error) {
-	body := map[string]interface{}{
-		"model": c.model,
-		"messages": []map[string]string{{
-			"role":    "user",
-			"content": prompt,
-		}},
-		"max_tokens":  4000,
-		"temperature": 0,
-	}
+	return c.ChatMessages([]Message{{Role: RoleUser, Content: prompt}})
+}
 
-	jsonBody, err := json.Marshal(body)
+// ChatMessages sends a multi-turn conversation and returns the assistant's reply
+func (c *Claude) ChatMessages(messages []Message) (string, error) {
+	jsonBody, err := json.Marshal(c.requestBody(messages, false))
 	if err != nil {
 		return "", err
 	}
 
-	req, err := http.NewRequest("POST", "https://api.anthropic.com/v1/messages", bytes.NewBuffer(jsonBody))
+	statusCode, respBytes, err := sendWithRetry(c.client, c.maxRetries, func() (*http.Request, error) {
+		req, err := http.NewRequest("POST", "https://api.anthropic.com/v1/messages", bytes.NewBuffer(jsonBody))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("x-api-key", c.apiKey)
+		req.Header.Set("anthropic-version", "2023-06-01")
+		return req, nil
+	})
 	if err != nil {
 		return "", err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-api-key", c.apiKey)
-	req.Header.Set("anthropic-version", "2023-06-01")
+	if statusCode != http.StatusOK {
+		return "", newAPIError("Claude", statusCode, respBytes)
+	}
 
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return "", err
+	return parseClaudeResponse(respBytes, "Claude")
+}
+
+// minCacheableChars is roughly Anthropic's minimum of 1024 tokens for a cached prompt prefix.
+// Shorter blocks are sent as plain strings, since marking them would not cache anything.
+const minCacheableChars = 4096
+
+// claudeBlock is a text content block, optionally marked as the end of a cached prompt prefix
+type claudeBlock struct {
+	Type         string            `json:"type"`
+	Text         string            `json:"text"`
+	CacheControl map[string]string `json:"cache_control,omitempty"`
+}
+
+// claudeMessage is a message whose content is either a string or a list of blocks
+type claudeMessage struct {
+	Role    string      `json:"role"`
+	Content interface{} `json:"content"`
+}
+
+// requestBody builds a messages request. A long system prompt and a long first user message,
+// which carries the resource dump, are marked with cache_control so Anthropic caches them:
+// follow-up questions, the JSON retry and repeated runs then reuse them at a fraction of the cost.
+func (c *Claude) requestBody(messages []Message, stream bool) map[string]interface{} {
+	system, messages := splitSystem(messages)
+
+	converted := make([]claudeMessage, 0, len(messages))
+	firstUser := true
+	for _, m := range messages {
+		var content interface{} = m.Content
+		if m.Role == RoleUser && firstUser {
+			firstUser = false
+			if len(m.Content) >= minCacheableChars {
+				content = []claudeBlock{cachedBlock(m.Content)}
+			}
+		}
+		converted = append(converted, claudeMessage{Role: m.Role, Content: content})
 	}
-	defer resp.Body.Close()
 
-	respBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
+	body := map[string]interface{}{
+		"model":       c.model,
+		"messages":    converted,
+		"max_tokens":  c.options.maxTokens(),
+		"temperature": c.options.Temperature,
 	}
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("Claude API error (status %d): %s", resp.StatusCode, string(respBytes))
+	if system != "" {
+		body["system"] = system
+		if len(system) >= minCacheableChars {
+			body["system"] = []claudeBlock{cachedBlock(system)}
+		}
+	}
+	if stream {
+		body["stream"] = true
 	}
+	return body
+}
+
+// cachedBlock marks text as the end of a cached prompt prefix
+func cachedBlock(text string) claudeBlock {
+	return claudeBlock{Type: "text", Text: text, CacheControl: map[string]string{"type": "ephemeral"}}
+}
+
+// CachesPrompts reports that Claude caches long system prompts and first user messages
+func (c *Claude) CachesPrompts() bool {
+	return true
+}
 
+// parseClaudeResponse extracts the text of a Claude messages response. It is shared
+// by providers that serve Claude models with the same content-block shape.
+func parseClaudeResponse(respBytes []byte, provider string) (string, error) {
 	// Minimal struct to pull out the content text.
 	var claudeResp struct {
 		Content []struct {
@@ -82,10 +149,10 @@ func (c *Claude) Chat(prompt string) (string, error) {
 		return "", err
 	}
 	if claudeResp.Error.Message != "" {
-		return "", fmt.Errorf("Claude API error: %s", claudeResp.Error.Message)
+		return "", newAPIError(provider, 0, respBytes)
 	}
 	if len(claudeResp.Content) == 0 {
-		return "", fmt.Errorf("empty response from Claude")
+		return "", fmt.Errorf("empty response from %s", provider)
 	}
 	return claudeResp.Content[0].Text, nil
 }
@@ -94,3 +161,85 @@ func (c *Claude) Chat(prompt string) (string, error) {
 func (c *Claude) GetModel() string {
 	return c.model
 }
+
+// SetMaxRetries sets how many times a failed request is retried
+func (c *Claude) SetMaxRetries(maxRetries int) {
+	c.maxRetries = maxRetries
+}
+
+// SetGenerationOptions sets the temperature and response length of each request
+func (c *Claude) SetGenerationOptions(options GenerationOptions) {
+	c.options = options
+}
+
+// generationOptions returns the sampling settings set with SetGenerationOptions
+func (c *Claude) generationOptions() GenerationOptions {
+	return c.options
+}
+
+// maxTemperature is the highest temperature Claude accepts
+func (c *Claude) maxTemperature() float64 {
+	return maxClaudeTemperature
+}
+
+// ChatStream sends the prompt with streaming enabled and forwards text deltas to out
+func (c *Claude) ChatStream(prompt string, out chan<- string) error {
+	return c.ChatStreamMessages([]Message{{Role: RoleUser, Content: prompt}}, out)
+}
+
+// ChatStreamMessages streams the reply to a conversation, forwarding text deltas to out
+func (c *Claude) ChatStreamMessages(messages []Message, out chan<- string) error {
+	defer close(out)
+
+	jsonBody, err := json.Marshal(c.requestBody(messages, true))
+	if err != nil {
+		return err
+	}
+
+	resp, err := doWithRetry(c.stream, c.maxRetries, func() (*http.Request, error) {
+		req, err := http.NewRequest("POST", "https://api.anthropic.com/v1/messages", bytes.NewBuffer(jsonBody))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "text/event-stream")
+		req.Header.Set("x-api-key", c.apiKey)
+		req.Header.Set("anthropic-version", "2023-06-01")
+		return req, nil
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBytes, _ := io.ReadAll(resp.Body)
+		return newAPIError("Claude", resp.StatusCode, respBytes)
+	}
+
+	return readSSE(resp.Body, func(data string) error {
+		// Minimal struct covering the content_block_delta and error events
+		var event struct {
+			Type  string `json:"type"`
+			Delta struct {
+				Type string `json:"type"`
+				Text string `json:"text"`
+			} `json:"delta"`
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return err
+		}
+		switch event.Type {
+		case "error":
+			return newAPIError("Claude", 0, []byte(data))
+		case "content_block_delta":
+			if event.Delta.Text != "" {
+				out <- event.Delta.Text
+			}
+		}
+		return nil
+	})
+}
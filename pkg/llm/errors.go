@@ -0,0 +1,141 @@
+package llm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Errors that failed LLM calls can be matched against with errors.Is
+var (
+	ErrAuth                  = errors.New("authentication failed")
+	ErrRateLimited           = errors.New("rate limited")
+	ErrContextLengthExceeded = errors.New("prompt exceeds the model's context window")
+	ErrServerError           = errors.New("provider server error")
+)
+
+// APIError is an error returned by an LLM provider's API. Kind is one of the Err* values above
+// when the failure is recognised, so callers can react to it, and nil otherwise.
+type APIError struct {
+	Provider   string
+	StatusCode int
+	Type       string
+	Message    string
+	Kind       error
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s API error", e.Provider)
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf(" (status %d)", e.StatusCode)
+	}
+	msg += ": " + e.Message
+	if hint := errorHint(e.Kind); hint != "" {
+		msg += " (" + hint + ")"
+	}
+	return msg
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Kind
+}
+
+// errorHint suggests what to do about a recognised failure
+func errorHint(kind error) string {
+	switch kind {
+	case ErrAuth:
+		return "check the API key or credentials for this provider"
+	case ErrRateLimited:
+		return "rate limited, retry later or raise --max-retries"
+	case ErrContextLengthExceeded:
+		return "the prompt is too large for the model, reduce the input (fewer resources, a shorter time range or fewer log lines)"
+	case ErrServerError:
+		return "the provider had a server error, retry later"
+	default:
+		return ""
+	}
+}
+
+// newAPIError builds an APIError from a non-200 response, reading the error type and message
+// from the common body shapes: {"error": {"type", "code", "message"}} (Claude, OpenAI, Azure,
+// Gemini) and {"error": "message"} (Ollama)
+func newAPIError(provider string, statusCode int, body []byte) *APIError {
+	var envelope struct {
+		Error   json.RawMessage `json:"error"`
+		Message string          `json:"message"`
+	}
+	var detail struct {
+		Type    string          `json:"type"`
+		Code    json.RawMessage `json:"code"`
+		Status  string          `json:"status"`
+		Message string          `json:"message"`
+	}
+
+	errType, message := "", strings.TrimSpace(string(body))
+	if json.Unmarshal(body, &envelope) == nil {
+		var text string
+		switch {
+		case json.Unmarshal(envelope.Error, &detail) == nil && detail.Message != "":
+			message = detail.Message
+			// OpenAI's string code is more specific than its type; Gemini's code is numeric
+			var code string
+			json.Unmarshal(detail.Code, &code)
+			errType = firstNonEmpty(code, detail.Type, detail.Status)
+		case json.Unmarshal(envelope.Error, &text) == nil && text != "":
+			message = text
+		case envelope.Message != "":
+			// Bedrock returns {"message": "..."}
+			message = envelope.Message
+		}
+	}
+
+	return &APIError{
+		Provider:   provider,
+		StatusCode: statusCode,
+		Type:       errType,
+		Message:    message,
+		Kind:       classifyError(statusCode, errType, message),
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// classifyError maps a status code and provider error type or message to one of the Err* values
+func classifyError(statusCode int, errType, message string) error {
+	errType = strings.ToLower(errType)
+	message = strings.ToLower(message)
+
+	switch {
+	case errType == "context_length_exceeded",
+		statusCode == http.StatusRequestEntityTooLarge,
+		strings.Contains(message, "prompt is too long"),
+		strings.Contains(message, "context length"),
+		strings.Contains(message, "context window"),
+		strings.Contains(message, "too many tokens"),
+		strings.Contains(message, "input is too long"):
+		return ErrContextLengthExceeded
+	case statusCode == http.StatusUnauthorized, statusCode == http.StatusForbidden,
+		errType == "authentication_error", errType == "permission_error",
+		errType == "invalid_api_key", errType == "unauthenticated", errType == "permission_denied":
+		return ErrAuth
+	case statusCode == http.StatusTooManyRequests,
+		errType == "rate_limit_error", errType == "rate_limit_exceeded", errType == "resource_exhausted",
+		strings.Contains(errType, "throttling"):
+		return ErrRateLimited
+	case statusCode >= http.StatusInternalServerError,
+		errType == "api_error", errType == "overloaded_error", errType == "server_error",
+		errType == "internal", errType == "unavailable":
+		return ErrServerError
+	default:
+		return nil
+	}
+}
@@ -1,17 +1,25 @@
 package llm
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
+	"time"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 )
 
 // Provider represents the LLM provider type
 type Provider string
 
 const (
-	ProviderClaude Provider = "claude"
-	ProviderOpenAI Provider = "openai"
+	ProviderClaude  Provider = "claude"
+	ProviderOpenAI  Provider = "openai"
+	ProviderGemini  Provider = "gemini"
+	ProviderOllama  Provider = "ollama"
+	ProviderAzure   Provider = "azure"
+	ProviderBedrock Provider = "bedrock"
 )
 
 // Factory creates LLM instances based on provider
@@ -36,14 +44,44 @@ func (f *Factory) CreateLLM(provider Provider, config map[string]string) (LLM, e
 		return NewClaude(apiKey), nil
 
 	case ProviderOpenAI:
+		// Compatible gateways at a custom base URL may not need a key
 		apiKey := config["api_key"]
-		if apiKey == "" {
+		if apiKey == "" && config["base_url"] == "" {
 			return nil, fmt.Errorf("OpenAI API key is required")
 		}
+		return NewOpenAIWithConfig(apiKey, config["model"], config["base_url"]), nil
+
+	case ProviderGemini:
+		apiKey := config["api_key"]
+		if apiKey == "" {
+			return nil, fmt.Errorf("Gemini API key is required")
+		}
 		if model := config["model"]; model != "" {
-			return NewOpenAIWithModel(apiKey, model), nil
+			return NewGeminiWithModel(apiKey, model), nil
+		}
+		return NewGemini(apiKey), nil
+
+	case ProviderOllama:
+		// Ollama runs locally and does not need an API key
+		timeout, err := parseTimeout(config["timeout"])
+		if err != nil {
+			return nil, err
 		}
-		return NewOpenAI(apiKey), nil
+		model := config["model"]
+		if model == "" {
+			model = "llama3"
+		}
+		return NewOllamaWithModel(config["host"], model, timeout), nil
+
+	case ProviderAzure:
+		if config["endpoint"] == "" || config["api_key"] == "" || config["deployment"] == "" {
+			return nil, fmt.Errorf("Azure OpenAI endpoint, API key and deployment are required")
+		}
+		return NewAzureOpenAI(config["endpoint"], config["api_key"], config["deployment"], config["api_version"]), nil
+
+	case ProviderBedrock:
+		// Credentials come from the standard AWS chain
+		return newBedrock(config["region"], config["model"])
 
 	default:
 		return nil, fmt.Errorf("unsupported LLM provider: %s", provider)
@@ -52,20 +90,43 @@ func (f *Factory) CreateLLM(provider Provider, config map[string]string) (LLM, e
 
 // CreateFromEnv creates an LLM instance from environment variables
 func (f *Factory) CreateFromEnv() (LLM, error) {
+	return f.createFromEnv(EnvOptions{})
+}
+
+// EnvOptions are settings passed explicitly, e.g. from flags, that take precedence over their
+// environment variables
+type EnvOptions struct {
+	// OpenAIBaseURL overrides OPENAI_BASE_URL for the openai provider
+	OpenAIBaseURL string
+}
+
+func (f *Factory) createFromEnv(options EnvOptions) (LLM, error) {
 	// Check which provider is configured
 	provider := strings.ToLower(os.Getenv("LLM_PROVIDER"))
 
 	switch provider {
 	case "openai":
-		apiKey := os.Getenv("OPENAI_API_KEY")
+		return newOpenAIFromEnv(os.Getenv("OPENAI_MODEL"), options.OpenAIBaseURL)
+
+	case "gemini":
+		apiKey := os.Getenv("GEMINI_API_KEY")
 		if apiKey == "" {
-			return nil, fmt.Errorf("OPENAI_API_KEY environment variable not set")
+			return nil, fmt.Errorf("GEMINI_API_KEY environment variable not set")
 		}
-		model := os.Getenv("OPENAI_MODEL")
+		model := os.Getenv("GEMINI_MODEL")
 		if model != "" {
-			return NewOpenAIWithModel(apiKey, model), nil
+			return NewGeminiWithModel(apiKey, model), nil
 		}
-		return NewOpenAI(apiKey), nil
+		return NewGemini(apiKey), nil
+
+	case "ollama":
+		return newOllamaFromEnv(os.Getenv("OLLAMA_MODEL"))
+
+	case "azure":
+		return newAzureOpenAIFromEnv(os.Getenv("AZURE_OPENAI_DEPLOYMENT"))
+
+	case "bedrock":
+		return newBedrock(os.Getenv("AWS_REGION"), os.Getenv("BEDROCK_MODEL"))
 
 	case "claude", "":
 		// Default to Claude for backward compatibility
@@ -80,18 +141,18 @@ func (f *Factory) CreateFromEnv() (LLM, error) {
 		return NewClaude(apiKey), nil
 
 	default:
-		return nil, fmt.Errorf("unsupported LLM_PROVIDER: %s (supported: claude, openai)", provider)
+		return nil, fmt.Errorf("unsupported LLM_PROVIDER: %s (supported: claude, openai, gemini, ollama, azure, bedrock)", provider)
 	}
 }
 
 // GetAvailableProviders returns a list of available LLM providers
 func (f *Factory) GetAvailableProviders() []Provider {
-	return []Provider{ProviderClaude, ProviderOpenAI}
+	return []Provider{ProviderClaude, ProviderOpenAI, ProviderGemini, ProviderOllama, ProviderAzure, ProviderBedrock}
 }
 
-// CreateFromEnv creates an LLM instance from environment variables
+// CreateFromEnv creates an LLM instance from environment variables, with options taking precedence
 // This is a convenience function that creates a new factory and uses it
-func CreateFromEnv(providerOverride, modelOverride string) (LLM, error) {
+func CreateFromEnv(providerOverride, modelOverride string, options EnvOptions) (LLM, error) {
 	factory := &Factory{}
 
 	// If provider is explicitly set, use that
@@ -99,18 +160,47 @@ func CreateFromEnv(providerOverride, modelOverride string) (LLM, error) {
 		provider := strings.ToLower(providerOverride)
 		switch provider {
 		case "openai":
-			apiKey := os.Getenv("OPENAI_API_KEY")
+			model := modelOverride
+			if model == "" {
+				model = os.Getenv("OPENAI_MODEL")
+			}
+			return newOpenAIFromEnv(model, options.OpenAIBaseURL)
+
+		case "gemini":
+			apiKey := os.Getenv("GEMINI_API_KEY")
 			if apiKey == "" {
-				return nil, fmt.Errorf("OPENAI_API_KEY environment variable not set")
+				return nil, fmt.Errorf("GEMINI_API_KEY environment variable not set")
 			}
 			model := modelOverride
 			if model == "" {
-				model = os.Getenv("OPENAI_MODEL")
+				model = os.Getenv("GEMINI_MODEL")
 			}
 			if model != "" {
-				return NewOpenAIWithModel(apiKey, model), nil
+				return NewGeminiWithModel(apiKey, model), nil
+			}
+			return NewGemini(apiKey), nil
+
+		case "ollama":
+			model := modelOverride
+			if model == "" {
+				model = os.Getenv("OLLAMA_MODEL")
 			}
-			return NewOpenAI(apiKey), nil
+			return newOllamaFromEnv(model)
+
+		case "azure":
+			// --model selects the Azure deployment
+			deployment := modelOverride
+			if deployment == "" {
+				deployment = os.Getenv("AZURE_OPENAI_DEPLOYMENT")
+			}
+			return newAzureOpenAIFromEnv(deployment)
+
+		case "bedrock":
+			model := modelOverride
+			if model == "" {
+				model = os.Getenv("BEDROCK_MODEL")
+			}
+			return newBedrock(os.Getenv("AWS_REGION"), model)
 
 		case "claude":
 			apiKey := os.Getenv("ANTHROPIC_API_KEY")
@@ -127,10 +217,90 @@ func CreateFromEnv(providerOverride, modelOverride string) (LLM, error) {
 			return NewClaude(apiKey), nil
 
 		default:
-			return nil, fmt.Errorf("unsupported provider: %s (supported: claude, openai)", provider)
+			return nil, fmt.Errorf("unsupported provider: %s (supported: claude, openai, gemini, ollama, azure, bedrock)", provider)
 		}
 	}
 
 	// Otherwise, auto-detect from environment
-	return factory.CreateFromEnv()
+	return factory.createFromEnv(options)
+}
+
+// newOpenAIFromEnv creates an OpenAI client using OPENAI_API_KEY and baseURL, or OPENAI_BASE_URL
+// when baseURL is empty. The key is optional with a base URL, since self-hosted gateways often
+// don't require one.
+func newOpenAIFromEnv(model, baseURL string) (LLM, error) {
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if baseURL == "" {
+		baseURL = os.Getenv("OPENAI_BASE_URL")
+	}
+	if apiKey == "" && baseURL == "" {
+		return nil, fmt.Errorf("OPENAI_API_KEY environment variable not set")
+	}
+	return NewOpenAIWithConfig(apiKey, model, baseURL), nil
+}
+
+// newOllamaFromEnv creates an Ollama client using OLLAMA_HOST and OLLAMA_TIMEOUT
+func newOllamaFromEnv(model string) (LLM, error) {
+	timeout, err := parseTimeout(os.Getenv("OLLAMA_TIMEOUT"))
+	if err != nil {
+		return nil, err
+	}
+	if model == "" {
+		model = "llama3"
+	}
+	return NewOllamaWithModel(os.Getenv("OLLAMA_HOST"), model, timeout), nil
+}
+
+// newAzureOpenAIFromEnv creates an Azure OpenAI client using AZURE_OPENAI_ENDPOINT,
+// AZURE_OPENAI_API_KEY and AZURE_OPENAI_API_VERSION
+func newAzureOpenAIFromEnv(deployment string) (LLM, error) {
+	endpoint := os.Getenv("AZURE_OPENAI_ENDPOINT")
+	if endpoint == "" {
+		return nil, fmt.Errorf("AZURE_OPENAI_ENDPOINT environment variable not set")
+	}
+	apiKey := os.Getenv("AZURE_OPENAI_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("AZURE_OPENAI_API_KEY environment variable not set")
+	}
+	if deployment == "" {
+		return nil, fmt.Errorf("AZURE_OPENAI_DEPLOYMENT environment variable not set")
+	}
+	return NewAzureOpenAI(endpoint, apiKey, deployment, os.Getenv("AZURE_OPENAI_API_VERSION")), nil
+}
+
+// newBedrock creates a Bedrock client for a Claude model, loading credentials from the
+// standard AWS chain (env vars, shared config/credentials files, SSO, web identity, IMDS)
+func newBedrock(region, model string) (LLM, error) {
+	if model == "" {
+		model = DefaultBedrockModel
+	}
+	if !strings.Contains(model, "anthropic.claude") {
+		return nil, fmt.Errorf("unsupported Bedrock model %q: only anthropic.claude-* models are supported", model)
+	}
+
+	var opts []func(*awsconfig.LoadOptions) error
+	if region != "" {
+		opts = append(opts, awsconfig.WithRegion(region))
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+	if cfg.Region == "" {
+		return nil, fmt.Errorf("AWS_REGION environment variable not set")
+	}
+
+	return NewBedrockWithModel(cfg.Region, cfg.Credentials, model), nil
+}
+
+// parseTimeout parses an optional timeout such as "90s" or "5m"; empty means default
+func parseTimeout(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout %q: %w", value, err)
+	}
+	return timeout, nil
 }
@@ -0,0 +1,137 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type Gemini struct {
+	apiKey     string
+	client     *http.Client
+	model      string
+	maxRetries int
+	options    GenerationOptions
+}
+
+func NewGemini(apiKey string) *Gemini {
+	return &Gemini{
+		apiKey:     apiKey,
+		client:     &http.Client{Timeout: 60 * time.Second},
+		model:      "gemini-1.5-pro",
+		maxRetries: DefaultMaxRetries,
+	}
+}
+
+func NewGeminiWithModel(apiKey, model string) *Gemini {
+	return &Gemini{
+		apiKey:     apiKey,
+		client:     &http.Client{Timeout: 60 * time.Second},
+		model:      model,
+		maxRetries: DefaultMaxRetries,
+	}
+}
+
+func (g *Gemini) Chat(prompt string) (string, error) {
+	return g.ChatMessages([]Message{{Role: RoleUser, Content: prompt}})
+}
+
+// ChatMessages sends a multi-turn conversation and returns the assistant's reply
+func (g *Gemini) ChatMessages(messages []Message) (string, error) {
+	system, messages := splitSystem(messages)
+
+	// Gemini calls the assistant role "model" and wraps text in parts
+	contents := make([]map[string]interface{}, 0, len(messages))
+	for _, m := range messages {
+		role := m.Role
+		if role == RoleAssistant {
+			role = "model"
+		}
+		contents = append(contents, map[string]interface{}{
+			"role":  role,
+			"parts": []map[string]string{{"text": m.Content}},
+		})
+	}
+
+	body := map[string]interface{}{
+		"contents": contents,
+		"generationConfig": map[string]interface{}{
+			"maxOutputTokens": g.options.maxTokens(),
+			"temperature":     g.options.Temperature,
+		},
+	}
+	if system != "" {
+		body["systemInstruction"] = map[string]interface{}{
+			"parts": []map[string]string{{"text": system}},
+		}
+	}
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+
+	endpoint := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent", url.PathEscape(g.model))
+	statusCode, respBytes, err := sendWithRetry(g.client, g.maxRetries, func() (*http.Request, error) {
+		req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(jsonBody))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("x-goog-api-key", g.apiKey)
+		return req, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if statusCode != http.StatusOK {
+		return "", newAPIError("Gemini", statusCode, respBytes)
+	}
+
+	// Gemini response structure
+	var geminiResp struct {
+		Candidates []struct {
+			Content struct {
+				Parts []struct {
+					Text string `json:"text"`
+				} `json:"parts"`
+			} `json:"content"`
+		} `json:"candidates"`
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(respBytes, &geminiResp); err != nil {
+		return "", err
+	}
+	if geminiResp.Error.Message != "" {
+		return "", newAPIError("Gemini", 0, respBytes)
+	}
+	if len(geminiResp.Candidates) == 0 || len(geminiResp.Candidates[0].Content.Parts) == 0 {
+		return "", fmt.Errorf("empty response from Gemini")
+	}
+	return geminiResp.Candidates[0].Content.Parts[0].Text, nil
+}
+
+// GetModel returns the model being used by this Gemini client
+func (g *Gemini) GetModel() string {
+	return g.model
+}
+
+// SetMaxRetries sets how many times a failed request is retried
+func (g *Gemini) SetMaxRetries(maxRetries int) {
+	g.maxRetries = maxRetries
+}
+
+// SetGenerationOptions sets the temperature and response length of each request
+func (g *Gemini) SetGenerationOptions(options GenerationOptions) {
+	g.options = options
+}
+
+// generationOptions returns the sampling settings set with SetGenerationOptions
+func (g *Gemini) generationOptions() GenerationOptions {
+	return g.options
+}
@@ -0,0 +1,71 @@
+package llm
+
+import "fmt"
+
+const (
+	// DefaultMaxTokens caps the length of a response unless --max-tokens overrides it
+	DefaultMaxTokens = 4000
+
+	// DefaultTemperature keeps analyses deterministic
+	DefaultTemperature = 0.0
+
+	// maxTemperature is the highest temperature accepted by OpenAI, Azure, Gemini and Ollama
+	maxTemperature = 2.0
+
+	// maxClaudeTemperature is the highest temperature Claude accepts, directly or through Bedrock
+	maxClaudeTemperature = 1.0
+)
+
+// GenerationOptions are the sampling settings sent with every request. A zero MaxTokens
+// means the provider default.
+type GenerationOptions struct {
+	Temperature float64
+	MaxTokens   int
+}
+
+// Validate checks the options are within the range the providers accept. Some providers accept
+// less, which SetGenerationOptions checks once the client is known.
+func (o GenerationOptions) Validate() error {
+	if o.Temperature < 0 || o.Temperature > maxTemperature {
+		return fmt.Errorf("temperature must be between 0 and %g, got %g", maxTemperature, o.Temperature)
+	}
+	if o.MaxTokens < 0 {
+		return fmt.Errorf("max tokens can't be negative, got %d", o.MaxTokens)
+	}
+	return nil
+}
+
+// maxTokens returns the response token limit, falling back to DefaultMaxTokens
+func (o GenerationOptions) maxTokens() int {
+	if o.MaxTokens > 0 {
+		return o.MaxTokens
+	}
+	return DefaultMaxTokens
+}
+
+// Tunable is implemented by LLM clients whose sampling settings can be changed
+type Tunable interface {
+	SetGenerationOptions(options GenerationOptions)
+}
+
+// tuned is implemented by LLM clients that report their sampling settings
+type tuned interface {
+	generationOptions() GenerationOptions
+}
+
+// temperatureLimited is implemented by LLM clients whose provider caps temperature below maxTemperature
+type temperatureLimited interface {
+	maxTemperature() float64
+}
+
+// SetGenerationOptions configures temperature and max tokens on the given LLM if it supports them.
+// A temperature above what the client's provider accepts is rejected instead of failing every request.
+func SetGenerationOptions(l LLM, options GenerationOptions) error {
+	if limited, ok := Unwrap(l).(temperatureLimited); ok && options.Temperature > limited.maxTemperature() {
+		return fmt.Errorf("temperature must be between 0 and %g with this provider, got %g", limited.maxTemperature(), options.Temperature)
+	}
+	if t, ok := l.(Tunable); ok {
+		t.SetGenerationOptions(options)
+	}
+	return nil
+}
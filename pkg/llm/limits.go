@@ -0,0 +1,45 @@
+package llm
+
+const (
+	// DefaultContextTokens is assumed for LLM clients with an unknown context window
+	DefaultContextTokens = 100000
+
+	// responseReserveTokens is kept free in the context window for the model's answer unless
+	// --max-tokens sets its size
+	responseReserveTokens = 4096
+)
+
+// MaxContextTokens returns the context window of the LLM's provider, in tokens
+func MaxContextTokens(l LLM) int {
+	switch Unwrap(l).(type) {
+	case *Claude, *Bedrock:
+		return 200000
+	case *OpenAI, *AzureOpenAI:
+		return 128000
+	case *Gemini:
+		return 1000000
+	case *Ollama:
+		// Ollama truncates silently past its num_ctx, which is small by default
+		return 8192
+	default:
+		return DefaultContextTokens
+	}
+}
+
+// ResponseReserveTokens returns how many tokens to keep free in the context window for the LLM's
+// answer: its configured max tokens, or responseReserveTokens when that isn't set
+func ResponseReserveTokens(l LLM) int {
+	if t, ok := l.(tuned); ok && t.generationOptions().MaxTokens > 0 {
+		return t.generationOptions().MaxTokens
+	}
+	return responseReserveTokens
+}
+
+// PromptTokenBudget returns how many tokens a prompt may use given a context window,
+// leaving reserveTokens for the response
+func PromptTokenBudget(contextTokens, reserveTokens int) int {
+	if contextTokens <= 2*reserveTokens {
+		return contextTokens / 2
+	}
+	return contextTokens - reserveTokens
+}
@@ -1,5 +1,110 @@
 package llm
 
+import (
+    "fmt"
+    "strings"
+)
+
 type LLM interface {
     Chat(prompt string) (string, error)
 }
+
+// Streamer is implemented by LLM clients that can stream partial responses.
+// ChatStream sends text chunks to out as they arrive and closes out when done.
+type Streamer interface {
+    ChatStream(prompt string, out chan<- string) error
+}
+
+// Message roles. System messages carry instructions and are mapped to each
+// provider's dedicated field where it has one.
+const (
+    RoleSystem    = "system"
+    RoleUser      = "user"
+    RoleAssistant = "assistant"
+)
+
+// Message is a single turn of a conversation
+type Message struct {
+    Role    string `json:"role"`
+    Content string `json:"content"`
+}
+
+// MessageChatter is implemented by LLM clients that accept a whole conversation.
+// ChatMessages returns the assistant's reply to the last message.
+type MessageChatter interface {
+    ChatMessages(messages []Message) (string, error)
+}
+
+// ChatMessages sends a conversation to the LLM. Clients without multi-turn support
+// get the conversation flattened into a single prompt.
+func ChatMessages(l LLM, messages []Message) (string, error) {
+    if m, ok := l.(MessageChatter); ok {
+        return m.ChatMessages(messages)
+    }
+    return l.Chat(flatten(messages))
+}
+
+// MessageStreamer is implemented by LLM clients that can stream the reply to a whole conversation
+type MessageStreamer interface {
+    ChatStreamMessages(messages []Message, out chan<- string) error
+}
+
+// ChatStreamMessages streams the reply to a conversation to out and closes it. Streamers without
+// multi-turn support get a lone user message as is and longer conversations flattened; clients
+// that can't stream send the whole reply as one chunk.
+func ChatStreamMessages(l LLM, messages []Message, out chan<- string) error {
+    if m, ok := l.(MessageStreamer); ok {
+        return m.ChatStreamMessages(messages, out)
+    }
+    if s, ok := l.(Streamer); ok {
+        if len(messages) == 1 && messages[0].Role == RoleUser {
+            return s.ChatStream(messages[0].Content, out)
+        }
+        return s.ChatStream(flatten(messages), out)
+    }
+
+    reply, err := ChatMessages(l, messages)
+    if err == nil {
+        out <- reply
+    }
+    close(out)
+    return err
+}
+
+// PromptCacher is implemented by LLM clients that cache the system prompt and the first user
+// message across requests, such as Claude with Anthropic's prompt caching
+type PromptCacher interface {
+    CachesPrompts() bool
+}
+
+// CachesPrompts reports whether static instructions are worth sending to l as a separate,
+// cacheable system message
+func CachesPrompts(l LLM) bool {
+    c, ok := l.(PromptCacher)
+    return ok && c.CachesPrompts()
+}
+
+// flatten joins a conversation into a single prompt for clients without multi-turn support
+func flatten(messages []Message) string {
+    var prompt strings.Builder
+    for _, m := range messages {
+        fmt.Fprintf(&prompt, "%s:\n%s\n\n", strings.ToUpper(m.Role), m.Content)
+    }
+    prompt.WriteString("ASSISTANT:\n")
+    return prompt.String()
+}
+
+// splitSystem separates system messages, joined into one instruction, from the
+// conversation for APIs that take the system prompt as a top-level field
+func splitSystem(messages []Message) (string, []Message) {
+    var system []string
+    conversation := make([]Message, 0, len(messages))
+    for _, m := range messages {
+        if m.Role == RoleSystem {
+            system = append(system, m.Content)
+            continue
+        }
+        conversation = append(conversation, m)
+    }
+    return strings.Join(system, "\n\n"), conversation
+}
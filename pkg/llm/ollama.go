@@ -0,0 +1,133 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultOllamaHost is the address a local Ollama server listens on by default
+	DefaultOllamaHost = "http://localhost:11434"
+	// DefaultOllamaTimeout is generous because local models are much slower than hosted APIs
+	DefaultOllamaTimeout = 5 * time.Minute
+)
+
+type Ollama struct {
+	host       string
+	client     *http.Client
+	model      string
+	maxRetries int
+	options    GenerationOptions
+}
+
+func NewOllama(host string) *Ollama {
+	return NewOllamaWithModel(host, "llama3", DefaultOllamaTimeout)
+}
+
+func NewOllamaWithModel(host, model string, timeout time.Duration) *Ollama {
+	if host == "" {
+		host = DefaultOllamaHost
+	}
+	if !strings.HasPrefix(host, "http") {
+		host = "http://" + host
+	}
+	if timeout <= 0 {
+		timeout = DefaultOllamaTimeout
+	}
+	return &Ollama{
+		host:       strings.TrimSuffix(host, "/"),
+		client:     &http.Client{Timeout: timeout},
+		model:      model,
+		maxRetries: DefaultMaxRetries,
+	}
+}
+
+func (o *Ollama) Chat(prompt string) (string, error) {
+	return o.ChatMessages([]Message{{Role: RoleUser, Content: prompt}})
+}
+
+// ChatMessages sends a multi-turn conversation and returns the assistant's reply
+func (o *Ollama) ChatMessages(messages []Message) (string, error) {
+	options := map[string]interface{}{
+		"temperature": o.options.Temperature,
+	}
+	// Ollama doesn't cap responses by default, so only an explicit limit is sent
+	if o.options.MaxTokens > 0 {
+		options["num_predict"] = o.options.MaxTokens
+	}
+
+	body := map[string]interface{}{
+		"model":    o.model,
+		"messages": messages,
+		// Ollama streams by default, we want a single JSON object back
+		"stream":  false,
+		"options": options,
+	}
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+
+	statusCode, respBytes, err := sendWithRetry(o.client, o.maxRetries, func() (*http.Request, error) {
+		req, err := http.NewRequest("POST", o.host+"/api/chat", bytes.NewBuffer(jsonBody))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if statusCode != http.StatusOK {
+		return "", newAPIError("Ollama", statusCode, respBytes)
+	}
+
+	// Ollama response structure
+	var ollamaResp struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(respBytes, &ollamaResp); err != nil {
+		return "", err
+	}
+	if ollamaResp.Error != "" {
+		return "", newAPIError("Ollama", 0, respBytes)
+	}
+	if ollamaResp.Message.Content == "" {
+		return "", fmt.Errorf("empty response from Ollama")
+	}
+	return ollamaResp.Message.Content, nil
+}
+
+// BaseURL returns the Ollama server the client sends requests to
+func (o *Ollama) BaseURL() string {
+	return o.host
+}
+
+// GetModel returns the model being used by this Ollama client
+func (o *Ollama) GetModel() string {
+	return o.model
+}
+
+// SetMaxRetries sets how many times a failed request is retried
+func (o *Ollama) SetMaxRetries(maxRetries int) {
+	o.maxRetries = maxRetries
+}
+
+// SetGenerationOptions sets the temperature and response length (num_predict) of each request
+func (o *Ollama) SetGenerationOptions(options GenerationOptions) {
+	o.options = options
+}
+
+// generationOptions returns the sampling settings set with SetGenerationOptions
+func (o *Ollama) generationOptions() GenerationOptions {
+	return o.options
+}
@@ -6,66 +6,103 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 )
 
+// DefaultOpenAIBaseURL is the OpenAI API, used unless a compatible gateway is configured
+const DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+
 type OpenAI struct {
-	apiKey string
-	client *http.Client
-	model  string
+	apiKey     string
+	baseURL    string
+	client     *http.Client
+	stream     *http.Client // No total timeout, see newStreamingClient
+	model      string
+	maxRetries int
+	options    GenerationOptions
 }
 
 func NewOpenAI(apiKey string) *OpenAI {
-	return &OpenAI{
-		apiKey: apiKey,
-		client: &http.Client{Timeout: 60 * time.Second},
-		model:  "gpt-4o", // Latest GPT-4 model
-	}
+	return NewOpenAIWithConfig(apiKey, "", "")
 }
 
 func NewOpenAIWithModel(apiKey, model string) *OpenAI {
+	return NewOpenAIWithConfig(apiKey, model, "")
+}
+
+// NewOpenAIWithConfig creates a client for any OpenAI-compatible API (LiteLLM, OpenRouter, vLLM,
+// Groq...) at baseURL, the URL that /chat/completions is appended to. Empty values use gpt-4o and
+// the OpenAI API. The API key may be empty for gateways without authentication.
+func NewOpenAIWithConfig(apiKey, model, baseURL string) *OpenAI {
+	if model == "" {
+		model = "gpt-4o" // Latest GPT-4 model
+	}
+	if baseURL == "" {
+		baseURL = DefaultOpenAIBaseURL
+	}
 	return &OpenAI{
-		apiKey: apiKey,
-		client: &http.Client{Timeout: 60 * time.Second},
-		model:  model,
+		apiKey:     apiKey,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		client:     &http.Client{Timeout: 60 * time.Second},
+		stream:     newStreamingClient(),
+		model:      model,
+		maxRetries: DefaultMaxRetries,
 	}
 }
 
 func (o *OpenAI) Chat(prompt string) (string, error) {
+	return o.ChatMessages([]Message{{Role: RoleUser, Content: prompt}})
+}
+
+// ChatMessages sends a multi-turn conversation and returns the assistant's reply
+func (o *OpenAI) ChatMessages(messages []Message) (string, error) {
+	return o.chat(messages, false)
+}
+
+// ChatJSON works like ChatMessages but enables JSON mode, so the reply is always a
+// valid JSON object. The messages must ask for JSON, as OpenAI rejects the request otherwise.
+func (o *OpenAI) ChatJSON(messages []Message) (string, error) {
+	return o.chat(messages, true)
+}
+
+// requestBody builds a chat completions request for the conversation
+func (o *OpenAI) requestBody(messages []Message, jsonMode, stream bool) map[string]interface{} {
 	body := map[string]interface{}{
-		"model": o.model,
-		"messages": []map[string]string{{
-			"role":    "user",
-			"content": prompt,
-		}},
-		"max_tokens":  4000,
-		"temperature": 0,
+		"model":       o.model,
+		"messages":    messages,
+		"max_tokens":  o.options.maxTokens(),
+		"temperature": o.options.Temperature,
 	}
-
-	jsonBody, err := json.Marshal(body)
-	if err != nil {
-		return "", err
+	if jsonMode {
+		body["response_format"] = map[string]string{"type": "json_object"}
 	}
-
-	req, err := http.NewRequest("POST", "https://api.openai.com/v1/chat/completions", bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return "", err
+	if stream {
+		body["stream"] = true
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", o.apiKey))
+	return body
+}
 
-	resp, err := o.client.Do(req)
+func (o *OpenAI) chat(messages []Message, jsonMode bool) (string, error) {
+	jsonBody, err := json.Marshal(o.requestBody(messages, jsonMode, false))
 	if err != nil {
 		return "", err
 	}
-	defer resp.Body.Close()
 
-	respBytes, err := io.ReadAll(resp.Body)
+	statusCode, respBytes, err := sendWithRetry(o.client, o.maxRetries, func() (*http.Request, error) {
+		req, err := http.NewRequest("POST", o.baseURL+"/chat/completions", bytes.NewBuffer(jsonBody))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		o.authorize(req)
+		return req, nil
+	})
 	if err != nil {
 		return "", err
 	}
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, string(respBytes))
+	if statusCode != http.StatusOK {
+		return "", newAPIError("OpenAI", statusCode, respBytes)
 	}
 
 	// OpenAI response structure
@@ -84,7 +121,7 @@ func (o *OpenAI) Chat(prompt string) (string, error) {
 		return "", err
 	}
 	if openaiResp.Error.Message != "" {
-		return "", fmt.Errorf("OpenAI API error: %s", openaiResp.Error.Message)
+		return "", newAPIError("OpenAI", 0, respBytes)
 	}
 	if len(openaiResp.Choices) == 0 {
 		return "", fmt.Errorf("empty response from OpenAI")
@@ -92,7 +129,95 @@ func (o *OpenAI) Chat(prompt string) (string, error) {
 	return openaiResp.Choices[0].Message.Content, nil
 }
 
+// authorize adds the API key, if any, as a bearer token
+func (o *OpenAI) authorize(req *http.Request) {
+	if o.apiKey != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", o.apiKey))
+	}
+}
+
+// BaseURL returns the API the client sends requests to
+func (o *OpenAI) BaseURL() string {
+	return o.baseURL
+}
+
 // GetModel returns the model being used by this OpenAI client
 func (o *OpenAI) GetModel() string {
 	return o.model
 }
+
+// SetMaxRetries sets how many times a failed request is retried
+func (o *OpenAI) SetMaxRetries(maxRetries int) {
+	o.maxRetries = maxRetries
+}
+
+// SetGenerationOptions sets the temperature and response length of each request
+func (o *OpenAI) SetGenerationOptions(options GenerationOptions) {
+	o.options = options
+}
+
+// generationOptions returns the sampling settings set with SetGenerationOptions
+func (o *OpenAI) generationOptions() GenerationOptions {
+	return o.options
+}
+
+// ChatStream sends the prompt with streaming enabled and forwards content deltas to out
+func (o *OpenAI) ChatStream(prompt string, out chan<- string) error {
+	return o.ChatStreamMessages([]Message{{Role: RoleUser, Content: prompt}}, out)
+}
+
+// ChatStreamMessages streams the reply to a conversation, keeping its roles, and forwards
+// content deltas to out
+func (o *OpenAI) ChatStreamMessages(messages []Message, out chan<- string) error {
+	defer close(out)
+
+	jsonBody, err := json.Marshal(o.requestBody(messages, false, true))
+	if err != nil {
+		return err
+	}
+
+	resp, err := doWithRetry(o.stream, o.maxRetries, func() (*http.Request, error) {
+		req, err := http.NewRequest("POST", o.baseURL+"/chat/completions", bytes.NewBuffer(jsonBody))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "text/event-stream")
+		o.authorize(req)
+		return req, nil
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBytes, _ := io.ReadAll(resp.Body)
+		return newAPIError("OpenAI", resp.StatusCode, respBytes)
+	}
+
+	return readSSE(resp.Body, func(data string) error {
+		var chunk struct {
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+			} `json:"choices"`
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return err
+		}
+		if chunk.Error.Message != "" {
+			return newAPIError("OpenAI", 0, []byte(data))
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content != "" {
+				out <- choice.Delta.Content
+			}
+		}
+		return nil
+	})
+}
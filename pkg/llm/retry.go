@@ -0,0 +1,154 @@
+package llm
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries is the number of retries after the first failed attempt
+	DefaultMaxRetries = 3
+
+	retryBaseDelay = 1 * time.Second
+	retryMaxDelay  = 30 * time.Second
+)
+
+// VerboseOutput receives a line per LLM API request, with its size and status, when set (e.g. by --verbose)
+var VerboseOutput io.Writer
+
+// Retryable is implemented by LLM clients whose retry behaviour can be tuned
+type Retryable interface {
+	SetMaxRetries(maxRetries int)
+}
+
+// SetMaxRetries configures retries on the given LLM if it supports them
+func SetMaxRetries(l LLM, maxRetries int) {
+	if r, ok := l.(Retryable); ok {
+		r.SetMaxRetries(maxRetries)
+	}
+}
+
+// sendWithRetry sends the request built by newRequest and retries transient failures
+// (network errors, 429 and 5xx gateway errors) with jittered exponential backoff.
+// It returns the final status code and response body.
+func sendWithRetry(client *http.Client, maxRetries int, newRequest func() (*http.Request, error)) (int, []byte, error) {
+	resp, err := doWithRetry(client, maxRetries, newRequest)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, body, nil
+}
+
+// doWithRetry is like sendWithRetry but hands back the open response so the caller
+// can consume the body incrementally (e.g. for streaming). The caller must close it.
+func doWithRetry(client *http.Client, maxRetries int, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+
+		start := time.Now()
+		resp, err := client.Do(req)
+		traceRequest(req, resp, err, time.Since(start))
+		if err != nil {
+			if attempt >= maxRetries {
+				return nil, err
+			}
+			time.Sleep(backoffDelay(attempt))
+			continue
+		}
+
+		if !isRetryableStatus(resp.StatusCode) || attempt >= maxRetries {
+			return resp, nil
+		}
+
+		delay := backoffDelay(attempt)
+		if resp.StatusCode == http.StatusTooManyRequests {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				delay = retryAfter
+			}
+		}
+		// Drain so the connection can be reused
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		time.Sleep(delay)
+	}
+}
+
+// traceRequest logs an LLM API request to VerboseOutput. Only the host and path are logged, so
+// query parameters that may carry credentials stay out of bug reports.
+func traceRequest(req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+	if VerboseOutput == nil {
+		return
+	}
+	elapsed = elapsed.Round(time.Millisecond)
+	target := req.URL.Host + req.URL.Path
+	if err != nil {
+		fmt.Fprintf(VerboseOutput, "[llm] %s %s (%d bytes) failed after %s: %v\n", req.Method, target, req.ContentLength, elapsed, err)
+		return
+	}
+	fmt.Fprintf(VerboseOutput, "[llm] %s %s (%d bytes) %d (%s)\n", req.Method, target, req.ContentLength, resp.StatusCode, elapsed)
+}
+
+// isRetryableStatus reports whether the status code indicates a transient failure
+func isRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoffDelay returns the exponential backoff delay for the given attempt with full jitter
+func backoffDelay(attempt int) time.Duration {
+	delay := retryBaseDelay << attempt
+	if delay <= 0 || delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	// Keep at least half the delay so retries don't collapse to zero
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return capDelay(time.Duration(seconds) * time.Second), true
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		delay := time.Until(when)
+		if delay < 0 {
+			delay = 0
+		}
+		return capDelay(delay), true
+	}
+	return 0, false
+}
+
+func capDelay(delay time.Duration) time.Duration {
+	if delay > retryMaxDelay {
+		return retryMaxDelay
+	}
+	return delay
+}
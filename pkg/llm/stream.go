@@ -0,0 +1,96 @@
+package llm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// streamIdleTimeout bounds the wait for the response headers and then for each part of a
+// streamed response. A streaming client has no total timeout, since http.Client.Timeout covers
+// reading the whole body and would cut long answers off; the command's --timeout bounds the call.
+const streamIdleTimeout = 60 * time.Second
+
+// newStreamingClient returns an HTTP client for streamed responses
+func newStreamingClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = streamIdleTimeout
+	return &http.Client{Transport: transport}
+}
+
+// idleTimeoutReader fails a stream that sends nothing for timeout by closing the body, so a
+// stalled connection doesn't hang until the command times out
+type idleTimeoutReader struct {
+	body    io.ReadCloser
+	timeout time.Duration
+	timer   *time.Timer
+
+	mu       sync.Mutex
+	timedOut bool
+}
+
+func newIdleTimeoutReader(body io.ReadCloser, timeout time.Duration) *idleTimeoutReader {
+	r := &idleTimeoutReader{body: body, timeout: timeout}
+	r.timer = time.AfterFunc(timeout, func() {
+		r.mu.Lock()
+		r.timedOut = true
+		r.mu.Unlock()
+		body.Close()
+	})
+	return r
+}
+
+func (r *idleTimeoutReader) Read(p []byte) (int, error) {
+	n, err := r.body.Read(p)
+	if n > 0 {
+		r.timer.Reset(r.timeout)
+	}
+	if err != nil && err != io.EOF {
+		r.mu.Lock()
+		timedOut := r.timedOut
+		r.mu.Unlock()
+		if timedOut {
+			return n, fmt.Errorf("stream stalled: no data for %s", r.timeout)
+		}
+	}
+	return n, err
+}
+
+// stop disarms the idle timer once the stream is done
+func (r *idleTimeoutReader) stop() {
+	r.timer.Stop()
+}
+
+// readSSE reads a server-sent events stream and calls handle with the payload
+// of every "data:" line. Reading stops at EOF, at a "[DONE]" marker, when
+// handle returns an error, or when nothing arrives for streamIdleTimeout.
+func readSSE(body io.ReadCloser, handle func(data string) error) error {
+	reader := newIdleTimeoutReader(body, streamIdleTimeout)
+	defer reader.stop()
+
+	scanner := bufio.NewScanner(reader)
+	// Individual events can be larger than the default 64KB token size
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "" {
+			continue
+		}
+		if data == "[DONE]" {
+			return nil
+		}
+		if err := handle(data); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
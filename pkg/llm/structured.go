@@ -0,0 +1,16 @@
+package llm
+
+// JSONChatter is implemented by LLM clients that can constrain a reply to a valid JSON
+// object, such as OpenAI's JSON mode.
+type JSONChatter interface {
+	ChatJSON(messages []Message) (string, error)
+}
+
+// ChatJSON sends a conversation whose reply must be a JSON object. Clients without a
+// structured output mode get a regular ChatMessages call and rely on the prompt alone.
+func ChatJSON(l LLM, messages []Message) (string, error) {
+	if j, ok := l.(JSONChatter); ok {
+		return j.ChatJSON(messages)
+	}
+	return ChatMessages(l, messages)
+}
@@ -3,13 +3,39 @@ package metrics
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/helmcode/kubectl-ai/pkg/formatter"
 	"github.com/helmcode/kubectl-ai/pkg/k8s"
 	"github.com/helmcode/kubectl-ai/pkg/llm"
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// scaledObjectGVR identifies KEDA ScaledObjects
+var scaledObjectGVR = schema.GroupVersionResource{Group: "keda.sh", Version: "v1alpha1", Resource: "scaledobjects"}
+
+// KEDA defaults applied when the ScaledObject leaves a field unset
+const (
+	kedaDefaultMinReplicas     = 0
+	kedaDefaultMaxReplicas     = 100
+	kedaDefaultPollingInterval = 30
+	kedaDefaultCooldownPeriod  = 300
+)
+
+// hoursPerMonth is the average number of hours in a month, used for cost estimates
+const hoursPerMonth = 730
+
+// Headroom applied to observed usage when recommending requests (over p95) and limits (over the peak)
+const (
+	requestHeadroom = 1.2
+	limitHeadroom   = 1.5
 )
 
 // Analyzer handles metrics analysis using AI
@@ -17,6 +43,15 @@ type Analyzer struct {
 	llm        llm.LLM
 	prometheus *PrometheusClient
 	k8sClient  *k8s.Client
+
+	// Cost of running one pod for an hour; 0 disables cost estimates
+	costPerPodHour float64
+
+	// Service mesh the request and error rate metrics come from, if any
+	mesh string
+
+	// Recommend KEDA scale-to-zero even for workloads that were never idle
+	allowScaleToZero bool
 }
 
 // NewAnalyzer creates a new metrics analyzer
@@ -28,103 +63,118 @@ func NewAnalyzer(llmClient llm.LLM, prometheusClient *PrometheusClient, k8sClien
 	}
 }
 
-// AnalyzeMetrics performs AI-powered metrics analysis
-func (a *Analyzer) AnalyzeMetrics(request *AnalysisRequest) (*AnalysisResult, error) {
-	results := make(map[string]*AnalysisResult)
+// SetCostPerPodHour enables monthly cost estimates in HPA and KEDA recommendations
+func (a *Analyzer) SetCostPerPodHour(cost float64) {
+	a.costPerPodHour = cost
+}
+
+// SetMesh sets the service mesh used for request rate metrics, so KEDA recommendations can
+// scale on traffic
+func (a *Analyzer) SetMesh(mesh string) {
+	a.mesh = mesh
+}
+
+// SetAllowScaleToZero lets KEDA recommendations use 0 min replicas regardless of observed traffic
+func (a *Analyzer) SetAllowScaleToZero(allow bool) {
+	a.allowScaleToZero = allow
+}
+
+// costEstimate describes the monthly cost of running between minReplicas and maxReplicas pods,
+// or returns "" when no cost per pod-hour is configured
+func (a *Analyzer) costEstimate(minReplicas, maxReplicas int32) string {
+	if a.costPerPodHour <= 0 {
+		return ""
+	}
+	minCost := float64(minReplicas) * a.costPerPodHour * hoursPerMonth
+	maxCost := float64(maxReplicas) * a.costPerPodHour * hoursPerMonth
+	return fmt.Sprintf(". Estimated monthly cost: %.2f at %d replicas to %.2f at %d replicas (%g per pod-hour, %d hours/month)",
+		minCost, minReplicas, maxCost, maxReplicas, a.costPerPodHour, hoursPerMonth)
+}
 
-	// Analyze each resource
+// AnalyzeMetrics analyzes every resource in the request and returns one result per resource,
+// keyed like request.MetricsData by namespace/name
+func (a *Analyzer) AnalyzeMetrics(ctx context.Context, request *AnalysisRequest) (map[string]*AnalysisResult, error) {
+	results := make(map[string]*AnalysisResult, len(request.MetricsData))
 	for key, metricsData := range request.MetricsData {
-		result, err := a.analyzeResource(metricsData, request)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		result, err := a.analyzeResource(ctx, metricsData, request)
 		if err != nil {
 			return nil, fmt.Errorf("failed to analyze resource %s: %w", key, err)
 		}
 		results[key] = result
 	}
 
-	// For now, return the first result. In the future, we might want to aggregate results
-	for _, result := range results {
-		return result, nil
-	}
-
-	return &AnalysisResult{
-		Summary:   "No resources found to analyze",
-		Timestamp: time.Now(),
-	}, nil
+	return results, nil
 }
 
 // analyzeResource analyzes a single resource
-func (a *Analyzer) analyzeResource(metricsData *MetricsData, request *AnalysisRequest) (*AnalysisResult, error) {
+func (a *Analyzer) analyzeResource(ctx context.Context, metricsData *MetricsData, request *AnalysisRequest) (*AnalysisResult, error) {
+	generatedAt := time.Now().UTC()
 	result := &AnalysisResult{
+		SchemaVersion:   SchemaVersion,
+		GeneratedAt:     generatedAt,
+		Timestamp:       generatedAt,
 		ResourceName:    metricsData.ResourceName,
 		ResourceType:    metricsData.ResourceType,
 		Namespace:       metricsData.Namespace,
 		Duration:        metricsData.Duration,
+		Start:           metricsData.Start,
+		End:             metricsData.End,
 		Recommendations: []Recommendation{},
 		MetricsSummary:  make(map[string]MetricSummary),
-		Timestamp:       time.Now(),
 	}
 
 	// Process metrics summary
 	for name, metric := range metricsData.Metrics {
-		// Convert TimestampedValue to separate arrays
-		values := make([]float64, len(metric.Values))
-		timestamps := make([]time.Time, len(metric.Values))
-
-		for i, tv := range metric.Values {
-			values[i] = tv.Value
-			timestamps[i] = tv.Timestamp
-		}
-
-		result.MetricsSummary[name] = MetricSummary{
-			Name:        metric.Name,
-			Unit:        metric.Unit,
-			Average:     metric.Average,
-			Peak:        metric.Peak,
-			Minimum:     metric.Minimum,
-			Current:     metric.Current,
-			Trend:       calculateTrend(metric.Values),
-			Utilization: calculateUtilization(metric.Average, metric.Peak),
-			Values:      values,     // Add historical values
-			Timestamps:  timestamps, // Add timestamps
-		}
-	}
-
-	// Extract scaling events from pod_replicas metric
-	if podReplicasMetric, exists := metricsData.Metrics["pod_replicas"]; exists {
-		result.ScalingEvents = make([]ScalingEvent, len(podReplicasMetric.Values))
-		for i, tv := range podReplicasMetric.Values {
-			result.ScalingEvents[i] = ScalingEvent{
-				Timestamp: tv.Timestamp,
-				Replicas:  int(tv.Value),
-				Reason:    "pod_scaling", // This could be enhanced to get actual reason from K8s events
+		result.MetricsSummary[name] = summarizeMetric(metric)
+	}
+	if len(metricsData.Containers) > 0 {
+		result.ContainerMetrics = make(map[string]map[string]MetricSummary, len(metricsData.Containers))
+		for container, containerMetrics := range metricsData.Containers {
+			result.ContainerMetrics[container] = make(map[string]MetricSummary, len(containerMetrics))
+			for name, metric := range containerMetrics {
+				result.ContainerMetrics[container][name] = summarizeMetric(metric)
 			}
 		}
 	}
 
+	// Extract scaling events from pod_replicas metric, explained by Kubernetes events
+	result.ScalingEvents = a.scalingEvents(ctx, metricsData)
+
+	// Flag volumes that are filling up
+	if rec := checkDiskPressure(metricsData); rec != nil {
+		result.Recommendations = append(result.Recommendations, *rec)
+	}
+
 	// Get current scaling configuration
-	currentConfig, err := a.getCurrentScalingConfig(metricsData.ResourceName, metricsData.Namespace)
-	if err != nil {
-		// Not an error, just means no scaling is configured
-		currentConfig = &ScalingConfig{
-			Type:        "none",
-			MinReplicas: 1,
-			MaxReplicas: 1,
-			CurrentSize: 1,
-		}
+	currentConfig := a.currentScalingConfigOrNone(ctx, metricsData)
+	if metricsData.ResourceType != "Pod" {
+		result.CurrentConfig = currentConfig
+	}
+
+	// Warn about HPAs fighting with KEDA over the same target
+	if rec := a.checkScalingConflict(ctx, metricsData.ResourceName, metricsData.ResourceType, metricsData.Namespace); rec != nil {
+		result.Recommendations = append(result.Recommendations, *rec)
 	}
-	result.CurrentConfig = currentConfig
 
 	// Perform AI analysis
 	if request.AnalyzeScaling || request.HPAAnalysis || request.KEDAAnalysis {
-		aiAnalysis, err := a.performAIAnalysis(metricsData, request, currentConfig)
+		aiAnalysis, err := a.performAIAnalysis(metricsData, request, currentConfig, result.ScalingEvents)
 		if err != nil {
 			return nil, fmt.Errorf("AI analysis failed: %w", err)
 		}
 		result.Summary = aiAnalysis
 	}
 
-	// Generate HPA recommendations if requested
-	if request.HPAAnalysis {
+	// Right-size requests and limits alongside the AI analysis
+	if request.AnalyzeScaling {
+		result.ResourceConfig = a.generateResourceRecommendation(ctx, metricsData)
+	}
+
+	// Generate HPA recommendations if requested (DaemonSets and batch workloads can't be autoscaled)
+	if request.HPAAnalysis && SupportsAutoscaling(metricsData.ResourceType) {
 		hpaRecommendation, err := a.generateHPARecommendation(metricsData, currentConfig)
 		if err != nil {
 			return nil, fmt.Errorf("HPA analysis failed: %w", err)
@@ -133,8 +183,8 @@ func (a *Analyzer) analyzeResource(metricsData *MetricsData, request *AnalysisRe
 	}
 
 	// Generate KEDA recommendations if requested
-	if request.KEDAAnalysis {
-		kedaRecommendation, err := a.generateKEDARecommendation(metricsData, currentConfig)
+	if request.KEDAAnalysis && SupportsAutoscaling(metricsData.ResourceType) {
+		kedaRecommendation, err := a.generateKEDARecommendation(ctx, metricsData, currentConfig)
 		if err != nil {
 			return nil, fmt.Errorf("KEDA analysis failed: %w", err)
 		}
@@ -144,9 +194,69 @@ func (a *Analyzer) analyzeResource(metricsData *MetricsData, request *AnalysisRe
 	return result, nil
 }
 
+// summarizeMetric converts a collected metric to its summary, with the values split out for charts
+func summarizeMetric(metric MetricValue) MetricSummary {
+	values := make([]float64, len(metric.Values))
+	timestamps := make([]time.Time, len(metric.Values))
+	for i, tv := range metric.Values {
+		values[i] = tv.Value
+		timestamps[i] = tv.Timestamp
+	}
+
+	return MetricSummary{
+		Name:        metric.Name,
+		Unit:        metric.Unit,
+		Average:     metric.Average,
+		Peak:        metric.Peak,
+		Minimum:     metric.Minimum,
+		Current:     metric.Current,
+		P50:         metric.P50,
+		P95:         metric.P95,
+		P99:         metric.P99,
+		Trend:       calculateTrend(metric.Values),
+		Utilization: calculateUtilization(metric.Average, metric.Peak),
+		Values:      values,
+		Timestamps:  timestamps,
+	}
+}
+
+// BuildPrompts returns the AI analysis prompt AnalyzeMetrics would send for each resource,
+// keyed like request.MetricsData. It is empty when no AI analysis is requested.
+func (a *Analyzer) BuildPrompts(ctx context.Context, request *AnalysisRequest) (map[string]string, error) {
+	prompts := make(map[string]string)
+	if !request.AnalyzeScaling && !request.HPAAnalysis && !request.KEDAAnalysis {
+		return prompts, nil
+	}
+
+	for key, metricsData := range request.MetricsData {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		currentConfig := a.currentScalingConfigOrNone(ctx, metricsData)
+		prompts[key] = a.buildAnalysisPrompt(metricsData, request, currentConfig, a.scalingEvents(ctx, metricsData))
+	}
+	return prompts, nil
+}
+
+// currentScalingConfigOrNone returns the resource's scaling configuration, or a fixed
+// single-replica config when no HPA or KEDA ScaledObject is found
+func (a *Analyzer) currentScalingConfigOrNone(ctx context.Context, metricsData *MetricsData) *ScalingConfig {
+	currentConfig, err := a.getCurrentScalingConfig(ctx, metricsData.ResourceName, metricsData.ResourceType, metricsData.Namespace)
+	if err != nil {
+		// Not an error, just means no scaling is configured
+		return &ScalingConfig{
+			Type:        "none",
+			MinReplicas: 1,
+			MaxReplicas: 1,
+			CurrentSize: 1,
+		}
+	}
+	return currentConfig
+}
+
 // performAIAnalysis uses AI to analyze metrics and provide recommendations
-func (a *Analyzer) performAIAnalysis(metricsData *MetricsData, request *AnalysisRequest, currentConfig *ScalingConfig) (string, error) {
-	prompt := a.buildAnalysisPrompt(metricsData, request, currentConfig)
+func (a *Analyzer) performAIAnalysis(metricsData *MetricsData, request *AnalysisRequest, currentConfig *ScalingConfig, scalingEvents []ScalingEvent) (string, error) {
+	prompt := a.buildAnalysisPrompt(metricsData, request, currentConfig, scalingEvents)
 
 	response, err := a.llm.Chat(prompt)
 	if err != nil {
@@ -157,44 +267,94 @@ func (a *Analyzer) performAIAnalysis(metricsData *MetricsData, request *Analysis
 }
 
 // buildAnalysisPrompt creates the prompt for AI analysis
-func (a *Analyzer) buildAnalysisPrompt(metricsData *MetricsData, request *AnalysisRequest, currentConfig *ScalingConfig) string {
+func (a *Analyzer) buildAnalysisPrompt(metricsData *MetricsData, request *AnalysisRequest, currentConfig *ScalingConfig, scalingEvents []ScalingEvent) string {
 	var prompt strings.Builder
 
 	prompt.WriteString("You are a Kubernetes expert analyzing metrics for scaling recommendations.\n\n")
 	prompt.WriteString(fmt.Sprintf("Resource: %s/%s (type: %s)\n", metricsData.Namespace, metricsData.ResourceName, metricsData.ResourceType))
-	prompt.WriteString(fmt.Sprintf("Analysis Duration: %s\n\n", metricsData.Duration))
+	prompt.WriteString(fmt.Sprintf("Analysis Duration: %s\n", metricsData.Duration))
+	if metricsData.Start != nil && metricsData.End != nil {
+		prompt.WriteString(fmt.Sprintf("Analysis Window: %s to %s (a fixed past window, e.g. an incident, not the latest data)\n",
+			metricsData.Start.UTC().Format(time.RFC3339), metricsData.End.UTC().Format(time.RFC3339)))
+	}
+	prompt.WriteString("\n")
+	if metricsData.Source == SourceMetricsServer {
+		prompt.WriteString("NOTE: These are point-in-time values from metrics-server, with no history. Trends, peaks and\n")
+		prompt.WriteString("daily patterns are unknown; say so and keep recommendations conservative.\n\n")
+	}
+	if metricsData.Source == SourceNone {
+		prompt.WriteString("NOTE: No metrics were collected, the cluster was analyzed without Prometheus or metrics-server.\n")
+		prompt.WriteString("Base the analysis on the spec, resource requests and limits, autoscaler configuration and events,\n")
+		prompt.WriteString("don't invent usage numbers, and recommend collecting metrics before resizing anything.\n\n")
+	}
 
 	// Add metrics data
-	prompt.WriteString("METRICS DATA:\n")
-	for name, metric := range metricsData.Metrics {
-		prompt.WriteString(fmt.Sprintf("- %s (%s): avg=%.2f, peak=%.2f, min=%.2f, current=%.2f\n",
-			name, metric.Unit, metric.Average, metric.Peak, metric.Minimum, metric.Current))
+	if metricsData.Source != SourceNone {
+		prompt.WriteString("METRICS DATA:\n")
+		for name, metric := range metricsData.Metrics {
+			prompt.WriteString(fmt.Sprintf("- %s (%s): avg=%.2f, p50=%.2f, p95=%.2f, p99=%.2f, peak=%.2f, min=%.2f, current=%.2f\n",
+				name, metric.Unit, metric.Average, metric.P50, metric.P95, metric.P99, metric.Peak, metric.Minimum, metric.Current))
+		}
+		prompt.WriteString("Size requests and autoscaling targets from p95 rather than peak; peaks are often brief spikes that over-provision.\n")
+	}
+	if _, ok := metricsData.Metrics[RequestRateMetric]; ok {
+		prompt.WriteString("request_rate (req/s) and error_rate (% of 5xx responses) come from the service mesh. Relate CPU and\n")
+		prompt.WriteString("memory to traffic: consider scaling on requests per replica when CPU doesn't track load, and treat\n")
+		prompt.WriteString("error_rate rising with traffic as a sign of under-provisioning.\n")
 	}
+	writeAnomalies(&prompt, metricsData.Metrics)
+	writeContainerBreakdown(&prompt, metricsData.Containers)
+	writeScalingChanges(&prompt, scalingEvents)
 	prompt.WriteString("\n")
 
-	// Add current scaling configuration
-	prompt.WriteString("CURRENT SCALING CONFIGURATION:\n")
-	prompt.WriteString(fmt.Sprintf("- Type: %s\n", currentConfig.Type))
-	prompt.WriteString(fmt.Sprintf("- Min Replicas: %d\n", currentConfig.MinReplicas))
-	prompt.WriteString(fmt.Sprintf("- Max Replicas: %d\n", currentConfig.MaxReplicas))
-	prompt.WriteString(fmt.Sprintf("- Current Size: %d\n", currentConfig.CurrentSize))
-	if currentConfig.TargetCPU > 0 {
-		prompt.WriteString(fmt.Sprintf("- Target CPU: %d%%\n", currentConfig.TargetCPU))
+	// Add current scaling configuration, which a bare pod doesn't have
+	if metricsData.ResourceType != "Pod" {
+		prompt.WriteString("CURRENT SCALING CONFIGURATION:\n")
+		prompt.WriteString(fmt.Sprintf("- Type: %s\n", currentConfig.Type))
+		prompt.WriteString(fmt.Sprintf("- Min Replicas: %d\n", currentConfig.MinReplicas))
+		prompt.WriteString(fmt.Sprintf("- Max Replicas: %d\n", currentConfig.MaxReplicas))
+		prompt.WriteString(fmt.Sprintf("- Current Size: %d\n", currentConfig.CurrentSize))
+		if currentConfig.TargetCPU > 0 {
+			prompt.WriteString(fmt.Sprintf("- Target CPU: %d%%\n", currentConfig.TargetCPU))
+		}
+		if currentConfig.TargetMemory > 0 {
+			prompt.WriteString(fmt.Sprintf("- Target Memory: %d%%\n", currentConfig.TargetMemory))
+		}
+		if currentConfig.Type == "keda" {
+			prompt.WriteString(fmt.Sprintf("- Polling Interval: %ds\n", currentConfig.PollingInterval))
+			prompt.WriteString(fmt.Sprintf("- Cooldown Period: %ds\n", currentConfig.CooldownPeriod))
+			prompt.WriteString(fmt.Sprintf("- Triggers: %s\n", strings.Join(currentConfig.Scalers, ", ")))
+			prompt.WriteString("- KEDA already manages an HPA for this resource; do not recommend adding a separate HPA\n")
+		}
+		prompt.WriteString("\n")
 	}
-	if currentConfig.TargetMemory > 0 {
-		prompt.WriteString(fmt.Sprintf("- Target Memory: %d%%\n", currentConfig.TargetMemory))
+
+	switch metricsData.ResourceType {
+	case "DaemonSet":
+		prompt.WriteString("NOTE: This is a DaemonSet. It runs one pod per eligible node, so HPA and KEDA do not apply.\n")
+		prompt.WriteString("Do not recommend HPA, KEDA or replica counts. pod_replicas is the desired number of nodes,\n")
+		prompt.WriteString("pod_ready and pod_available are the nodes with a ready/available pod. Focus on resource\n")
+		prompt.WriteString("requests/limits, rollout health and node coverage instead.\n\n")
+	case "Pod":
+		prompt.WriteString("NOTE: This is a single pod, analyzed on its own rather than through its workload, so HPA, KEDA and\n")
+		prompt.WriteString("replica counts do not apply. Focus on what is wrong with this pod: whether its usage fits its requests\n")
+		prompt.WriteString("and limits, leaks (steadily growing memory), throttling and spikes, and how it compares to a healthy pod.\n\n")
+	case "Job", "CronJob":
+		prompt.WriteString(fmt.Sprintf("NOTE: This is a %s. Its pods run to completion, so HPA and KEDA do not apply.\n", metricsData.ResourceType))
+		prompt.WriteString("Do not recommend HPA, KEDA or replica counts. pod_replicas is the number of running pods (jobs for a CronJob),\n")
+		prompt.WriteString("job_succeeded and job_failed count completed and failed pods. Focus on resource requests/limits,\n")
+		prompt.WriteString("failure rate, run duration and parallelism instead.\n\n")
 	}
-	prompt.WriteString("\n")
 
 	// Add analysis requirements
 	prompt.WriteString("ANALYSIS REQUIREMENTS:\n")
 	if request.AnalyzeScaling {
 		prompt.WriteString("- Provide general scaling analysis and recommendations\n")
 	}
-	if request.HPAAnalysis {
+	if request.HPAAnalysis && SupportsAutoscaling(metricsData.ResourceType) {
 		prompt.WriteString("- Provide HPA (Horizontal Pod Autoscaler) specific recommendations\n")
 	}
-	if request.KEDAAnalysis {
+	if request.KEDAAnalysis && SupportsAutoscaling(metricsData.ResourceType) {
 		prompt.WriteString("- Provide KEDA scaling recommendations\n")
 	}
 	if request.CompareScaling {
@@ -206,8 +366,12 @@ func (a *Analyzer) buildAnalysisPrompt(metricsData *MetricsData, request *Analys
 	prompt.WriteString("Please provide:\n")
 	prompt.WriteString("1. Analysis of current resource utilization patterns\n")
 	prompt.WriteString("2. Specific scaling recommendations with reasoning\n")
-	prompt.WriteString("3. Optimal scaling parameters (min/max replicas, thresholds)\n")
-	prompt.WriteString("4. Any potential issues or improvements\n")
+	if SupportsAutoscaling(metricsData.ResourceType) {
+		prompt.WriteString("3. Optimal scaling parameters (min/max replicas, thresholds)\n")
+	} else {
+		prompt.WriteString("3. Optimal resource requests and limits per pod\n")
+	}
+	prompt.WriteString("4. Any potential issues or improvements, including network saturation (network_receive/network_transmit) as a bottleneck that adding replicas may not fix\n")
 	prompt.WriteString("5. Concrete kubectl commands for implementation\n\n")
 
 	prompt.WriteString("Focus on practical, actionable recommendations based on the actual metrics data.")
@@ -215,16 +379,48 @@ func (a *Analyzer) buildAnalysisPrompt(metricsData *MetricsData, request *Analys
 	return prompt.String()
 }
 
+// writeAnomalies lists when each metric spiked above mean + 3σ, so the model can tie a spike to a
+// deploy or a scaling change instead of spotting it in the averages
+func writeAnomalies(prompt *strings.Builder, metrics map[string]MetricValue) {
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var lines []string
+	for _, name := range names {
+		metric := metrics[name]
+		values := make([]float64, len(metric.Values))
+		timestamps := make([]time.Time, len(metric.Values))
+		for i, v := range metric.Values {
+			values[i] = v.Value
+			timestamps[i] = v.Timestamp.UTC()
+		}
+		for _, anomaly := range formatter.DetectAnomalies(values, timestamps) {
+			lines = append(lines, fmt.Sprintf("- %s: %.2f %s at %s (%.1fσ above the mean)",
+				name, anomaly.Peak, metric.Unit, formatter.FormatAnomalyTime(anomaly, time.RFC3339), anomaly.Sigma))
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	prompt.WriteString(fmt.Sprintf("\nANOMALIES (points above mean + %.0f standard deviations):\n", formatter.AnomalySigma))
+	prompt.WriteString(strings.Join(lines, "\n") + "\n")
+	prompt.WriteString("Say whether each spike lines up with another metric, a change in pod_replicas or a likely deploy, and whether it is a one-off or recurring.\n")
+}
+
 // getCurrentScalingConfig retrieves current scaling configuration
-func (a *Analyzer) getCurrentScalingConfig(resourceName, namespace string) (*ScalingConfig, error) {
+func (a *Analyzer) getCurrentScalingConfig(ctx context.Context, resourceName, resourceType, namespace string) (*ScalingConfig, error) {
 	// Check for HPA first
-	hpaConfig, err := a.getHPAConfig(resourceName, namespace)
+	hpaConfig, err := a.getHPAConfig(ctx, resourceName, namespace)
 	if err == nil {
 		return hpaConfig, nil
 	}
 
 	// Check for KEDA ScaledObject
-	kedaConfig, err := a.getKEDAConfig(resourceName, namespace)
+	kedaConfig, err := a.getKEDAConfig(ctx, resourceName, resourceType, namespace)
 	if err == nil {
 		return kedaConfig, nil
 	}
@@ -233,10 +429,54 @@ func (a *Analyzer) getCurrentScalingConfig(resourceName, namespace string) (*Sca
 	return nil, fmt.Errorf("no scaling configuration found")
 }
 
+// checkScalingConflict returns a high-priority recommendation when a KEDA ScaledObject and a
+// separately created HPA both target the resource. KEDA manages its own HPA, so the two fight over replicas.
+func (a *Analyzer) checkScalingConflict(ctx context.Context, resourceName, resourceType, namespace string) *Recommendation {
+	kedaConfig, err := a.getKEDAConfig(ctx, resourceName, resourceType, namespace)
+	if err != nil {
+		return nil
+	}
+
+	hpas, err := a.k8sClient.GetClientset().AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil
+	}
+
+	kedaHPAName, _, _ := unstructured.NestedString(kedaConfig.RawConfig, "status", "hpaName")
+	scaledObjectName, _, _ := unstructured.NestedString(kedaConfig.RawConfig, "metadata", "name")
+
+	var conflicting []string
+	for _, hpa := range hpas.Items {
+		if hpa.Spec.ScaleTargetRef.Name != resourceName || (resourceType != "" && hpa.Spec.ScaleTargetRef.Kind != resourceType) {
+			continue
+		}
+		// Skip the HPA KEDA created for the ScaledObject itself
+		if hpa.Name == kedaHPAName || hpa.Labels["scaledobject.keda.sh/name"] != "" {
+			continue
+		}
+		conflicting = append(conflicting, hpa.Name)
+	}
+
+	if len(conflicting) == 0 {
+		return nil
+	}
+
+	return &Recommendation{
+		Type:     "hpa",
+		Priority: "high",
+		Title:    "Conflicting HPA and KEDA ScaledObject",
+		Description: fmt.Sprintf("HPA %s and KEDA ScaledObject %s both scale %s/%s. KEDA already manages its own HPA, "+
+			"so the two autoscalers will overwrite each other's replica count.",
+			strings.Join(conflicting, ", "), scaledObjectName, resourceType, resourceName),
+		Command:   fmt.Sprintf("kubectl delete hpa %s -n %s", conflicting[0], namespace),
+		Reasoning: "Only one autoscaler should own a workload's replica count; keep the KEDA ScaledObject or the HPA, not both",
+	}
+}
+
 // getHPAConfig retrieves HPA configuration
-func (a *Analyzer) getHPAConfig(resourceName, namespace string) (*ScalingConfig, error) {
+func (a *Analyzer) getHPAConfig(ctx context.Context, resourceName, namespace string) (*ScalingConfig, error) {
 	// Try v2 HPA first
-	hpaV2, err := a.k8sClient.GetClientset().AutoscalingV2().HorizontalPodAutoscalers(namespace).Get(context.TODO(), resourceName, metav1.GetOptions{})
+	hpaV2, err := a.k8sClient.GetClientset().AutoscalingV2().HorizontalPodAutoscalers(namespace).Get(ctx, resourceName, metav1.GetOptions{})
 	if err == nil {
 		config := &ScalingConfig{
 			Type:        "hpa",
@@ -261,7 +501,7 @@ func (a *Analyzer) getHPAConfig(resourceName, namespace string) (*ScalingConfig,
 	}
 
 	// Try v1 HPA
-	hpaV1, err := a.k8sClient.GetClientset().AutoscalingV1().HorizontalPodAutoscalers(namespace).Get(context.TODO(), resourceName, metav1.GetOptions{})
+	hpaV1, err := a.k8sClient.GetClientset().AutoscalingV1().HorizontalPodAutoscalers(namespace).Get(ctx, resourceName, metav1.GetOptions{})
 	if err == nil {
 		config := &ScalingConfig{
 			Type:        "hpa",
@@ -280,19 +520,111 @@ func (a *Analyzer) getHPAConfig(resourceName, namespace string) (*ScalingConfig,
 	return nil, fmt.Errorf("HPA not found")
 }
 
-// getKEDAConfig retrieves KEDA ScaledObject configuration
-func (a *Analyzer) getKEDAConfig(resourceName, namespace string) (*ScalingConfig, error) {
-	// For now, we'll implement a simplified version
-	// In a real implementation, we would query the KEDA API properly
+// getKEDAConfig retrieves the configuration of the KEDA ScaledObject targeting the resource
+func (a *Analyzer) getKEDAConfig(ctx context.Context, resourceName, resourceType, namespace string) (*ScalingConfig, error) {
+	// ScaledObjects can have any name, so find the one whose scaleTargetRef points at the resource.
+	// Listing fails if KEDA isn't installed, which just means there is no ScaledObject.
+	list, err := a.k8sClient.GetDynamicClient().Resource(scaledObjectGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("KEDA ScaledObject not found: %w", err)
+	}
 
-	// This is a placeholder - in a real implementation we would:
-	// 1. Use the dynamic client to query KEDA CRDs
-	// 2. Parse the ScaledObject configuration
-	// 3. Extract scaling parameters
+	for i := range list.Items {
+		obj := &list.Items[i]
+		if !scaledObjectTargets(obj, resourceName, resourceType) {
+			continue
+		}
+		return a.parseScaledObject(ctx, obj, namespace), nil
+	}
 
 	return nil, fmt.Errorf("KEDA ScaledObject not found")
 }
 
+// scaledObjectTargets reports whether the ScaledObject scales the given resource
+func scaledObjectTargets(obj *unstructured.Unstructured, resourceName, resourceType string) bool {
+	name, _, _ := unstructured.NestedString(obj.Object, "spec", "scaleTargetRef", "name")
+	if name != resourceName {
+		return false
+	}
+
+	// KEDA defaults the target kind to Deployment
+	kind, _, _ := unstructured.NestedString(obj.Object, "spec", "scaleTargetRef", "kind")
+	if kind == "" {
+		kind = "Deployment"
+	}
+	return resourceType == "" || kind == resourceType
+}
+
+// parseScaledObject converts a ScaledObject into a ScalingConfig
+func (a *Analyzer) parseScaledObject(ctx context.Context, obj *unstructured.Unstructured, namespace string) *ScalingConfig {
+	config := &ScalingConfig{
+		Type:            "keda",
+		MinReplicas:     nestedInt32(obj, kedaDefaultMinReplicas, "spec", "minReplicaCount"),
+		MaxReplicas:     nestedInt32(obj, kedaDefaultMaxReplicas, "spec", "maxReplicaCount"),
+		PollingInterval: nestedInt32(obj, kedaDefaultPollingInterval, "spec", "pollingInterval"),
+		CooldownPeriod:  nestedInt32(obj, kedaDefaultCooldownPeriod, "spec", "cooldownPeriod"),
+		RawConfig:       obj.Object,
+	}
+
+	triggers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "triggers")
+	for _, t := range triggers {
+		trigger, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		triggerType, _, _ := unstructured.NestedString(trigger, "type")
+		if name, _, _ := unstructured.NestedString(trigger, "name"); name != "" {
+			triggerType = fmt.Sprintf("%s (%s)", triggerType, name)
+		}
+		config.Scalers = append(config.Scalers, triggerType)
+	}
+
+	// KEDA manages an HPA for the ScaledObject, which knows the current replica count
+	if hpaName, _, _ := unstructured.NestedString(obj.Object, "status", "hpaName"); hpaName != "" {
+		hpa, err := a.k8sClient.GetClientset().AutoscalingV2().HorizontalPodAutoscalers(namespace).Get(ctx, hpaName, metav1.GetOptions{})
+		if err == nil {
+			config.CurrentSize = hpa.Status.CurrentReplicas
+		}
+	}
+
+	return config
+}
+
+// nestedInt32 reads an integer field from an unstructured object, returning def when unset
+func nestedInt32(obj *unstructured.Unstructured, def int32, fields ...string) int32 {
+	value, found, err := unstructured.NestedInt64(obj.Object, fields...)
+	if err != nil || !found {
+		return def
+	}
+	return int32(value)
+}
+
+// RecommendManifest returns the recommended autoscaler manifest for a resource, either an
+// HPA ("hpa") or a KEDA ScaledObject ("keda")
+func (a *Analyzer) RecommendManifest(ctx context.Context, metricsData *MetricsData, scalerType string) (string, error) {
+	if !SupportsAutoscaling(metricsData.ResourceType) {
+		return "", fmt.Errorf("%s %s/%s can't be autoscaled", metricsData.ResourceType, metricsData.Namespace, metricsData.ResourceName)
+	}
+
+	currentConfig := a.currentScalingConfigOrNone(ctx, metricsData)
+	switch scalerType {
+	case "hpa":
+		recommendation, err := a.generateHPARecommendation(metricsData, currentConfig)
+		if err != nil {
+			return "", err
+		}
+		return recommendation.YAMLConfig, nil
+	case "keda":
+		recommendation, err := a.generateKEDARecommendation(ctx, metricsData, currentConfig)
+		if err != nil {
+			return "", err
+		}
+		return recommendation.YAMLConfig, nil
+	default:
+		return "", fmt.Errorf("unsupported scaler type %q (supported: hpa, keda)", scalerType)
+	}
+}
+
 // generateHPARecommendation generates HPA recommendations
 func (a *Analyzer) generateHPARecommendation(metricsData *MetricsData, currentConfig *ScalingConfig) (*HPARecommendation, error) {
 	recommendation := &HPARecommendation{
@@ -330,17 +662,19 @@ func (a *Analyzer) generateHPARecommendation(metricsData *MetricsData, currentCo
 	}
 
 	// Generate YAML configuration
-	recommendation.YAMLConfig = a.generateHPAYAML(metricsData.ResourceName, metricsData.Namespace, recommendation)
-	recommendation.Reasoning = "Based on observed CPU and memory patterns over the specified duration"
+	recommendation.YAMLConfig = a.generateHPAYAML(metricsData.ResourceName, metricsData.ResourceType, metricsData.Namespace, recommendation)
+	recommendation.Reasoning = "Based on observed CPU and memory patterns over the specified duration" +
+		a.costEstimate(recommendation.MinReplicas, recommendation.MaxReplicas)
 
 	return recommendation, nil
 }
 
 // generateKEDARecommendation generates KEDA recommendations
-func (a *Analyzer) generateKEDARecommendation(metricsData *MetricsData, currentConfig *ScalingConfig) (*KEDARecommendation, error) {
+func (a *Analyzer) generateKEDARecommendation(ctx context.Context, metricsData *MetricsData, currentConfig *ScalingConfig) (*KEDARecommendation, error) {
+	minReplicas, floorReason := a.kedaMinReplicas(metricsData)
 	recommendation := &KEDARecommendation{
 		Enabled:         true,
-		MinReplicas:     0,
+		MinReplicas:     minReplicas,
 		MaxReplicas:     10,
 		PollingInterval: 30,
 		CooldownPeriod:  300,
@@ -348,6 +682,10 @@ func (a *Analyzer) generateKEDARecommendation(metricsData *MetricsData, currentC
 	}
 
 	// Add Prometheus scaler based on available metrics
+	serverAddress := "PROMETHEUS_URL" // Placeholder when metrics come from metrics-server
+	if a.prometheus != nil {
+		serverAddress = a.prometheus.GetURL()
+	}
 	if _, ok := metricsData.Metrics["cpu_utilization"]; ok {
 		scaler := KEDAScaler{
 			Type:      "prometheus",
@@ -355,23 +693,70 @@ func (a *Analyzer) generateKEDARecommendation(metricsData *MetricsData, currentC
 			Threshold: "70",
 			Query:     fmt.Sprintf(`rate(container_cpu_usage_seconds_total{pod=~"%s.*", namespace="%s"}[5m]) * 100`, metricsData.ResourceName, metricsData.Namespace),
 			Metadata: map[string]string{
-				"serverAddress": a.prometheus.GetURL(),
+				"serverAddress": serverAddress,
 				"threshold":     "70",
 				"query":         fmt.Sprintf(`rate(container_cpu_usage_seconds_total{pod=~"%s.*", namespace="%s"}[5m]) * 100`, metricsData.ResourceName, metricsData.Namespace),
 			},
 		}
 		recommendation.Scalers = append(recommendation.Scalers, scaler)
 	}
+	if requestRate, ok := metricsData.Metrics[RequestRateMetric]; ok && a.mesh != "" {
+		// Target the p95 traffic each replica handles today, so scaling keeps that per-pod load
+		replicas := 1.0
+		if replicaMetric, ok := metricsData.Metrics["pod_replicas"]; ok && replicaMetric.Average > 1 {
+			replicas = replicaMetric.Average
+		}
+		threshold := strconv.Itoa(int(math.Max(1, math.Ceil(requestRate.P95/replicas))))
+		podRegex := podRegexFor(ctx, a.k8sClient, metricsData.ResourceName, metricsData.ResourceType, metricsData.Namespace)
+		query := meshRequestRateQuery(a.mesh, metricsData.ResourceName, metricsData.Namespace, podRegex)
+		recommendation.Scalers = append(recommendation.Scalers, KEDAScaler{
+			Type:      "prometheus",
+			Name:      "request-rate-scaler",
+			Threshold: threshold,
+			Query:     query,
+			Metadata: map[string]string{
+				"serverAddress": serverAddress,
+				"threshold":     threshold,
+				"query":         query,
+			},
+		})
+	}
 
 	// Generate YAML configuration
-	recommendation.YAMLConfig = a.generateKEDAYAML(metricsData.ResourceName, metricsData.Namespace, recommendation)
-	recommendation.Reasoning = "KEDA allows more flexible scaling with custom metrics from Prometheus"
+	recommendation.YAMLConfig = a.generateKEDAYAML(metricsData.ResourceName, metricsData.ResourceType, metricsData.Namespace, recommendation)
+	recommendation.Reasoning = "KEDA allows more flexible scaling with custom metrics from Prometheus" + floorReason +
+		a.costEstimate(recommendation.MinReplicas, recommendation.MaxReplicas)
 
 	return recommendation, nil
 }
 
+// kedaMinReplicas picks the KEDA replica floor: scaling to zero is only recommended for workloads
+// that were idle during the analysis window, since otherwise every scale-up pays a cold start
+func (a *Analyzer) kedaMinReplicas(metricsData *MetricsData) (int32, string) {
+	switch {
+	case a.allowScaleToZero:
+		return 0, ". Scales to zero as requested with --allow-scale-to-zero; the first request after an idle period waits for a cold start"
+	case wasIdle(metricsData):
+		return 0, ". Scales to zero because the workload was idle during the analysis window"
+	default:
+		return 1, ". Keeps a floor of 1 replica because the workload was never idle during the analysis window, so scaling to zero would add cold-start latency (use --allow-scale-to-zero to opt in)"
+	}
+}
+
+// wasIdle reports whether the workload served no requests, or ran no replicas, at some point during
+// the analysis window. Without request or replica data it is assumed to be busy.
+func wasIdle(metricsData *MetricsData) bool {
+	if requestRate, ok := metricsData.Metrics[RequestRateMetric]; ok && len(requestRate.Values) > 0 {
+		return requestRate.Minimum == 0
+	}
+	if replicas, ok := metricsData.Metrics["pod_replicas"]; ok && len(replicas.Values) > 0 {
+		return replicas.Minimum == 0
+	}
+	return false
+}
+
 // generateHPAYAML generates HPA YAML configuration
-func (a *Analyzer) generateHPAYAML(resourceName, namespace string, config *HPARecommendation) string {
+func (a *Analyzer) generateHPAYAML(resourceName, resourceType, namespace string, config *HPARecommendation) string {
 	yaml := fmt.Sprintf(`apiVersion: autoscaling/v2
 kind: HorizontalPodAutoscaler
 metadata:
@@ -380,11 +765,11 @@ metadata:
 spec:
   scaleTargetRef:
     apiVersion: apps/v1
-    kind: Deployment
+    kind: %s
     name: %s
   minReplicas: %d
   maxReplicas: %d
-  metrics:`, resourceName, namespace, resourceName, config.MinReplicas, config.MaxReplicas)
+  metrics:`, resourceName, namespace, resourceType, resourceName, config.MinReplicas, config.MaxReplicas)
 
 	if config.TargetCPU > 0 {
 		yaml += fmt.Sprintf(`
@@ -410,7 +795,7 @@ spec:
 }
 
 // generateKEDAYAML generates KEDA YAML configuration
-func (a *Analyzer) generateKEDAYAML(resourceName, namespace string, config *KEDARecommendation) string {
+func (a *Analyzer) generateKEDAYAML(resourceName, resourceType, namespace string, config *KEDARecommendation) string {
 	yaml := fmt.Sprintf(`apiVersion: keda.sh/v1alpha1
 kind: ScaledObject
 metadata:
@@ -418,12 +803,14 @@ metadata:
   namespace: %s
 spec:
   scaleTargetRef:
+    apiVersion: apps/v1
+    kind: %s
     name: %s
   minReplicaCount: %d
   maxReplicaCount: %d
   pollingInterval: %d
   cooldownPeriod: %d
-  triggers:`, resourceName, namespace, resourceName, config.MinReplicas, config.MaxReplicas, config.PollingInterval, config.CooldownPeriod)
+  triggers:`, resourceName, namespace, resourceType, resourceName, config.MinReplicas, config.MaxReplicas, config.PollingInterval, config.CooldownPeriod)
 
 	for _, scaler := range config.Scalers {
 		yaml += fmt.Sprintf(`
@@ -431,25 +818,233 @@ spec:
     metadata:
       serverAddress: %s
       threshold: '%s'
-      query: %s`, scaler.Type, scaler.Metadata["serverAddress"], scaler.Threshold, scaler.Query)
+      query: %q`, scaler.Type, scaler.Metadata["serverAddress"], scaler.Threshold, scaler.Query)
 	}
 
 	return yaml
 }
 
-// calculateTrend calculates the trend of metric values
+// diskPressureThreshold is the fraction of PVC capacity at which usage is flagged
+const diskPressureThreshold = 0.8
+
+// generateResourceRecommendation proposes container requests from the observed p95 CPU and memory
+// usage plus requestHeadroom, and limits from the peak plus limitHeadroom. It returns nil when
+// neither usage metric has data.
+func (a *Analyzer) generateResourceRecommendation(ctx context.Context, metricsData *MetricsData) *ResourceRecommendation {
+	cpu, hasCPU := metricsData.Metrics["cpu_utilization"]
+	memory, hasMemory := metricsData.Metrics["memory_utilization"]
+	hasCPU = hasCPU && cpu.P95 > 0
+	hasMemory = hasMemory && memory.P95 > 0
+	if !hasCPU && !hasMemory {
+		return nil
+	}
+
+	recommendation := &ResourceRecommendation{Containers: []string{"CONTAINER_NAME"}}
+	if a.k8sClient != nil {
+		names, err := a.k8sClient.ContainerNames(ctx, metricsData.Namespace, metricsData.ResourceType, metricsData.ResourceName)
+		if err == nil && len(names) > 0 {
+			recommendation.Containers = names
+		}
+	}
+
+	samples := 0
+	if hasCPU {
+		// cpu_utilization is a percentage of one core
+		request := roundUp(cpu.P95/100*1000*requestHeadroom, 10)
+		limit := math.Max(roundUp(math.Max(cpu.Peak, cpu.P95)/100*1000*limitHeadroom, 10), request)
+		recommendation.CPURequest = formatMillicores(request)
+		recommendation.CPULimit = formatMillicores(limit)
+		samples = len(cpu.Values)
+	}
+	if hasMemory {
+		request := roundUp(memory.P95*requestHeadroom, 16)
+		limit := math.Max(roundUp(math.Max(memory.Peak, memory.P95)*limitHeadroom, 16), request)
+		recommendation.MemoryRequest = formatMebibytes(request)
+		recommendation.MemoryLimit = formatMebibytes(limit)
+		if samples == 0 || len(memory.Values) < samples {
+			samples = len(memory.Values)
+		}
+	}
+
+	// Current settings, when kube-state-metrics reports them
+	current := func(name string, format func(float64) string) string {
+		if metric, ok := metricsData.Metrics[name]; ok && metric.Current > 0 {
+			return format(metric.Current)
+		}
+		return ""
+	}
+	recommendation.CurrentCPURequest = current("cpu_requests", func(cores float64) string { return formatMillicores(math.Ceil(cores * 1000)) })
+	recommendation.CurrentCPULimit = current("cpu_limits", func(cores float64) string { return formatMillicores(math.Ceil(cores * 1000)) })
+	recommendation.CurrentMemoryRequest = current("memory_requests", func(mb float64) string { return formatMebibytes(math.Ceil(mb)) })
+	recommendation.CurrentMemoryLimit = current("memory_limits", func(mb float64) string { return formatMebibytes(math.Ceil(mb)) })
+
+	reasoning := fmt.Sprintf("Requests are the observed p95 usage plus %.0f%% headroom, limits the observed peak plus %.0f%%, over %s. "+
+		"Usage is averaged across containers, so split it by hand for pods with sidecars",
+		(requestHeadroom-1)*100, (limitHeadroom-1)*100, metricsData.Duration)
+	if samples < 2 {
+		reasoning += ". Based on a single usage sample (no history), so treat it as a starting point"
+	}
+	if recommendation.Containers[0] == "CONTAINER_NAME" {
+		reasoning += ". The container names couldn't be read, replace CONTAINER_NAME in the patch"
+	}
+
+	switch metricsData.ResourceType {
+	case "Deployment", "StatefulSet", "DaemonSet", "CronJob":
+		recommendation.PatchYAML = generateResourcePatchYAML(metricsData.ResourceType, recommendation)
+		recommendation.Command = fmt.Sprintf("kubectl patch %s %s -n %s --patch-file resources-patch.yaml",
+			strings.ToLower(metricsData.ResourceType), metricsData.ResourceName, metricsData.Namespace)
+	case "Job":
+		// The API server rejects changes to a Job's pod template
+		reasoning += ". A Job's pod template can't be changed once created: set these resources in its manifest, or in the CronJob that creates it, for the next run"
+	default:
+		// A pod has no template and its container resources are immutable, so no patch can apply
+		reasoning += ". A pod's resources can't be changed in place: set them on the workload that owns it, or recreate the pod with them"
+	}
+	recommendation.Reasoning = reasoning
+
+	return recommendation
+}
+
+// generateResourcePatchYAML builds a strategic merge patch setting the recommended requests and
+// limits on every container of the workload's pod template
+func generateResourcePatchYAML(resourceType string, config *ResourceRecommendation) string {
+	patch := "spec:\n  template:\n    spec:\n      containers:"
+	indent := "      "
+	if resourceType == "CronJob" {
+		// The pod template is nested in the job template
+		patch = "spec:\n  jobTemplate:\n    spec:\n      template:\n        spec:\n          containers:"
+		indent = "          "
+	}
+
+	for _, name := range config.Containers {
+		patch += fmt.Sprintf("\n%s- name: %s\n%s  resources:", indent, name, indent)
+		for _, section := range []struct {
+			name        string
+			cpu, memory string
+		}{
+			{"requests", config.CPURequest, config.MemoryRequest},
+			{"limits", config.CPULimit, config.MemoryLimit},
+		} {
+			patch += fmt.Sprintf("\n%s    %s:", indent, section.name)
+			if section.cpu != "" {
+				patch += fmt.Sprintf("\n%s      cpu: %s", indent, section.cpu)
+			}
+			if section.memory != "" {
+				patch += fmt.Sprintf("\n%s      memory: %s", indent, section.memory)
+			}
+		}
+	}
+	return patch
+}
+
+// roundUp rounds value up to a multiple of step, with step as the minimum
+func roundUp(value, step float64) float64 {
+	return math.Max(math.Ceil(value/step)*step, step)
+}
+
+// formatMillicores formats a CPU quantity, e.g. 250m or 2 for whole cores
+func formatMillicores(millicores float64) string {
+	if int64(millicores)%1000 == 0 {
+		return fmt.Sprintf("%d", int64(millicores)/1000)
+	}
+	return fmt.Sprintf("%dm", int64(millicores))
+}
+
+// formatMebibytes formats a memory quantity, e.g. 512Mi or 2Gi for whole gibibytes
+func formatMebibytes(mebibytes float64) string {
+	if int64(mebibytes)%1024 == 0 {
+		return fmt.Sprintf("%dGi", int64(mebibytes)/1024)
+	}
+	return fmt.Sprintf("%dMi", int64(mebibytes))
+}
+
+// checkDiskPressure returns a high-priority recommendation when volume usage is close to,
+// or trending toward, the PVC capacity within the analyzed window
+func checkDiskPressure(metricsData *MetricsData) *Recommendation {
+	capacity, ok := metricsData.Metrics["pvc_capacity"]
+	if !ok || capacity.Current <= 0 {
+		return nil
+	}
+
+	// Prefer the kubelet's volume stats, fall back to container filesystem usage
+	usage, ok := metricsData.Metrics["pvc_usage"]
+	if !ok {
+		usage, ok = metricsData.Metrics["filesystem_usage"]
+	}
+	if !ok || len(usage.Values) == 0 {
+		return nil
+	}
+
+	ratio := usage.Current / capacity.Current
+
+	// Project usage one analysis window ahead using the growth over the window
+	projected := usage.Current
+	if len(usage.Values) >= 2 {
+		growth := usage.Values[len(usage.Values)-1].Value - usage.Values[0].Value
+		if growth > 0 {
+			projected += growth
+		}
+	}
+
+	switch {
+	case ratio >= diskPressureThreshold:
+		return &Recommendation{
+			Type:     "resource",
+			Priority: "high",
+			Title:    "Persistent volume almost full",
+			Description: fmt.Sprintf("Volume usage is %.0f MB of %.0f MB (%.0f%% of capacity).",
+				usage.Current, capacity.Current, ratio*100),
+			Reasoning: "Workloads fail to write once the volume is full; expand the PVC or clean up data",
+		}
+	case projected >= capacity.Current:
+		return &Recommendation{
+			Type:     "resource",
+			Priority: "high",
+			Title:    "Persistent volume trending toward capacity",
+			Description: fmt.Sprintf("Volume usage grew to %.0f MB of %.0f MB over %s and will reach capacity within the next %s at the current rate.",
+				usage.Current, capacity.Current, metricsData.Duration, metricsData.Duration),
+			Reasoning: "Usage growth over the analyzed window projects past the PVC capacity",
+		}
+	}
+
+	return nil
+}
+
+// trendThreshold is the change over the window, relative to the series' mean magnitude,
+// above which a series counts as increasing or decreasing
+const trendThreshold = 0.1
+
+// calculateTrend fits a least-squares line through the values and reports whether the change it
+// predicts over the whole window exceeds trendThreshold of the mean magnitude. Unlike comparing
+// the first and last points, this is robust to a zero first value, single spikes and noise.
 func calculateTrend(values []TimestampedValue) string {
-	if len(values) < 2 {
+	n := float64(len(values))
+	if n < 2 {
+		return "stable"
+	}
+
+	var sumX, sumY, sumXY, sumXX, sumAbs float64
+	for i, v := range values {
+		x := float64(i)
+		sumX += x
+		sumY += v.Value
+		sumXY += x * v.Value
+		sumXX += x * x
+		sumAbs += math.Abs(v.Value)
+	}
+
+	meanAbs := sumAbs / n
+	if meanAbs == 0 {
+		// All zero, e.g. an idle metric
 		return "stable"
 	}
 
-	first := values[0].Value
-	last := values[len(values)-1].Value
-	diff := last - first
+	slope := (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+	change := slope * (n - 1)
 
-	if diff > first*0.1 {
+	if change > meanAbs*trendThreshold {
 		return "increasing"
-	} else if diff < -first*0.1 {
+	} else if change < -meanAbs*trendThreshold {
 		return "decreasing"
 	}
 	return "stable"
@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func series(values ...float64) []TimestampedValue {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	points := make([]TimestampedValue, len(values))
+	for i, value := range values {
+		points[i] = TimestampedValue{Timestamp: start.Add(time.Duration(i) * time.Minute), Value: value}
+	}
+	return points
+}
+
+func TestCalculateTrend(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []TimestampedValue
+		want   string
+	}{
+		{"single point", series(42), "stable"},
+		{"flat zero", series(0, 0, 0, 0, 0, 0), "stable"},
+		{"flat", series(30, 30, 30, 30, 30, 30), "stable"},
+		{"ramp up from zero", series(0, 10, 20, 30, 40, 50, 60, 70, 80, 90), "increasing"},
+		{"starts idle then rises", series(0, 0, 0, 0, 5, 10, 15, 20), "increasing"},
+		{"ramp down", series(90, 80, 70, 60, 50, 40, 30, 20, 10, 0), "decreasing"},
+		{"spike", series(10, 10, 10, 10, 10, 80, 10, 10, 10, 10, 10), "stable"},
+		{"noisy", series(50, 58, 43, 55, 46, 53, 44, 57, 47, 52, 45, 54), "stable"},
+		{"noisy ramp up", series(20, 28, 24, 35, 31, 42, 38, 50, 46, 58), "increasing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calculateTrend(tt.values); got != tt.want {
+				t.Errorf("calculateTrend() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateResourceRecommendationPatch(t *testing.T) {
+	tests := []struct {
+		resourceType string
+		wantPatch    bool
+	}{
+		{"Deployment", true},
+		{"StatefulSet", true},
+		{"DaemonSet", true},
+		{"CronJob", true},
+		{"Job", false},
+		{"Pod", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.resourceType, func(t *testing.T) {
+			metricsData := &MetricsData{
+				ResourceName: "api",
+				ResourceType: tt.resourceType,
+				Namespace:    "default",
+				Duration:     "1h",
+				Metrics: map[string]MetricValue{
+					"cpu_utilization": {P95: 50, Peak: 80, Values: series(40, 50, 80)},
+				},
+			}
+			recommendation := (&Analyzer{}).generateResourceRecommendation(context.Background(), metricsData)
+			if recommendation == nil {
+				t.Fatal("expected a recommendation")
+			}
+			if gotPatch := recommendation.PatchYAML != "" && recommendation.Command != ""; gotPatch != tt.wantPatch {
+				t.Errorf("patch = %q, command = %q, want a patch: %v", recommendation.PatchYAML, recommendation.Command, tt.wantPatch)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// AuthConfig holds the TLS and authentication settings used to reach Prometheus
+type AuthConfig struct {
+	BearerToken        string
+	CACertFile         string
+	InsecureSkipVerify bool
+	Username           string
+	Password           string
+}
+
+// WithEnvDefaults fills unset fields from PROMETHEUS_TOKEN, PROMETHEUS_CA_CERT,
+// PROMETHEUS_INSECURE_SKIP_VERIFY, PROMETHEUS_USERNAME and PROMETHEUS_PASSWORD
+// so flags always take precedence over the environment
+func (a AuthConfig) WithEnvDefaults() AuthConfig {
+	if a.BearerToken == "" {
+		a.BearerToken = os.Getenv("PROMETHEUS_TOKEN")
+	}
+	if a.CACertFile == "" {
+		a.CACertFile = os.Getenv("PROMETHEUS_CA_CERT")
+	}
+	if !a.InsecureSkipVerify {
+		a.InsecureSkipVerify, _ = strconv.ParseBool(os.Getenv("PROMETHEUS_INSECURE_SKIP_VERIFY"))
+	}
+	if a.Username == "" {
+		a.Username = os.Getenv("PROMETHEUS_USERNAME")
+	}
+	if a.Password == "" {
+		a.Password = os.Getenv("PROMETHEUS_PASSWORD")
+	}
+	return a
+}
+
+// newTransport builds an HTTP transport honoring the TLS settings
+func (a AuthConfig) newTransport() (http.RoundTripper, error) {
+	if a.CACertFile == "" && !a.InsecureSkipVerify {
+		return http.DefaultTransport, nil
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: a.InsecureSkipVerify,
+	}
+
+	if a.CACertFile != "" {
+		caCert, err := os.ReadFile(a.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate %s: %w", a.CACertFile, err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no valid PEM certificates found in %s", a.CACertFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// apply adds authentication headers to an outgoing request.
+// A bearer token wins over basic auth since both use the Authorization header.
+func (a AuthConfig) apply(req *http.Request) {
+	switch {
+	case a.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+a.BearerToken)
+	case a.Username != "" || a.Password != "":
+		req.SetBasicAuth(a.Username, a.Password)
+	}
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CompareResults asks the AI to explain how the same resource behaves in two kube contexts.
+// Each label names the context its result was gathered from.
+func (a *Analyzer) CompareResults(primaryLabel string, primary *AnalysisResult, compareLabel string, compare *AnalysisResult) (string, error) {
+	prompt := buildComparisonPrompt(primaryLabel, primary, compareLabel, compare)
+
+	response, err := a.llm.Chat(prompt)
+	if err != nil {
+		return "", fmt.Errorf("AI comparison failed: %w", err)
+	}
+
+	return response, nil
+}
+
+// buildComparisonPrompt creates the prompt for comparing a resource across two contexts
+func buildComparisonPrompt(primaryLabel string, primary *AnalysisResult, compareLabel string, compare *AnalysisResult) string {
+	var prompt strings.Builder
+
+	prompt.WriteString("You are a Kubernetes expert comparing how the same workload behaves in two clusters.\n\n")
+	prompt.WriteString(fmt.Sprintf("Resource: %s/%s (type: %s)\n", primary.Namespace, primary.ResourceName, primary.ResourceType))
+	prompt.WriteString(fmt.Sprintf("Analysis Duration: %s\n", primary.Duration))
+	prompt.WriteString(fmt.Sprintf("Cluster A is kube context %q, cluster B is kube context %q.\n\n", primaryLabel, compareLabel))
+
+	writeComparisonSide(&prompt, "A", primaryLabel, primary)
+	writeComparisonSide(&prompt, "B", compareLabel, compare)
+
+	prompt.WriteString("Please provide:\n")
+	prompt.WriteString(fmt.Sprintf("1. The most significant differences between %q and %q, citing the numbers from each context\n", primaryLabel, compareLabel))
+	prompt.WriteString("2. Likely causes of the divergence (traffic, resource requests/limits, scaling configuration, node types)\n")
+	prompt.WriteString("3. Whether the scaling configurations should be aligned, and how\n")
+	prompt.WriteString("4. Concrete kubectl commands for implementation, stating which context each applies to\n\n")
+
+	prompt.WriteString("Always refer to the clusters by their context names, never just as A or B.")
+
+	return prompt.String()
+}
+
+// writeComparisonSide writes one context's metrics and scaling configuration
+func writeComparisonSide(prompt *strings.Builder, side, label string, result *AnalysisResult) {
+	prompt.WriteString(fmt.Sprintf("CLUSTER %s METRICS (context %q):\n", side, label))
+
+	names := make([]string, 0, len(result.MetricsSummary))
+	for name := range result.MetricsSummary {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		metric := result.MetricsSummary[name]
+		prompt.WriteString(fmt.Sprintf("- [%s] %s (%s): avg=%.2f, p95=%.2f, peak=%.2f, min=%.2f, current=%.2f, trend=%s\n",
+			label, name, metric.Unit, metric.Average, metric.P95, metric.Peak, metric.Minimum, metric.Current, metric.Trend))
+	}
+	if len(names) == 0 {
+		prompt.WriteString("- no metrics data available\n")
+	}
+
+	if config := result.CurrentConfig; config != nil {
+		prompt.WriteString(fmt.Sprintf("- [%s] scaling: type=%s, min=%d, max=%d, current=%d", label, config.Type, config.MinReplicas, config.MaxReplicas, config.CurrentSize))
+		if config.TargetCPU > 0 {
+			prompt.WriteString(fmt.Sprintf(", target CPU=%d%%", config.TargetCPU))
+		}
+		if config.TargetMemory > 0 {
+			prompt.WriteString(fmt.Sprintf(", target memory=%d%%", config.TargetMemory))
+		}
+		prompt.WriteString("\n")
+	}
+	prompt.WriteString("\n")
+}
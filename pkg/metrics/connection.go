@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+)
+
+// normalizePrometheusURL validates a user-provided Prometheus URL and returns it with a scheme
+// and trailing slash. A missing scheme defaults to http.
+func normalizePrometheusURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid Prometheus URL %q: %w", raw, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("invalid Prometheus URL %q: scheme must be http or https", raw)
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("invalid Prometheus URL %q: missing host", raw)
+	}
+
+	if !strings.HasSuffix(raw, "/") {
+		raw += "/"
+	}
+	return raw, nil
+}
+
+// describeConnectionError turns a failed request to Prometheus into an error saying which
+// step failed (DNS, TCP, TLS) and what to check next
+func describeConnectionError(prometheusURL string, err error) error {
+	host, port := "", ""
+	if u, parseErr := url.Parse(prometheusURL); parseErr == nil {
+		host = u.Hostname()
+		port = u.Port()
+		if port == "" {
+			port = "80"
+			if u.Scheme == "https" {
+				port = "443"
+			}
+		}
+	}
+
+	var dnsErr *net.DNSError
+	var certErr *tls.CertificateVerificationError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var recordHeaderErr tls.RecordHeaderError
+	var netErr net.Error
+
+	switch {
+	case errors.As(err, &dnsErr):
+		return fmt.Errorf("DNS lookup failed: host %q could not be resolved (check the --prometheus-url host for typos): %w", host, err)
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return fmt.Errorf("connection refused: host %q resolved but nothing is listening on port %s (check the port, or whether Prometheus is running): %w", host, port, err)
+	case errors.As(err, &certErr), errors.As(err, &unknownAuthority), errors.As(err, &hostnameErr):
+		return fmt.Errorf("TLS error: the certificate of %s could not be verified (use --prometheus-ca-cert, or --prometheus-insecure-skip-verify for testing): %w", host, err)
+	case errors.As(err, &recordHeaderErr):
+		return fmt.Errorf("TLS error: %s:%s did not answer with TLS (try http:// instead of https://): %w", host, port, err)
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return fmt.Errorf("connection timed out: host %q resolved but port %s did not respond (check firewalls or network policies): %w", host, port, err)
+	default:
+		return fmt.Errorf("connection failed: %w", err)
+	}
+}
+
+// describeHTTPStatus explains a non-200 response from the Prometheus query API
+func describeHTTPStatus(resp *http.Response) error {
+	hint := ""
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		hint = " (check --prometheus-token or --prometheus-username/--prometheus-password)"
+	case http.StatusNotFound:
+		hint = " (the URL may be missing a path prefix, e.g. /prometheus, or not point at Prometheus)"
+	}
+	return fmt.Errorf("server reachable but returned HTTP %s%s", resp.Status, hint)
+}
@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Per-container queries for --by-container. They match the workload CPU and memory queries but
+// keep one series per container instead of averaging the whole pod.
+var (
+	ContainerCPUQuery = PrometheusQuery{
+		Name:        "cpu_utilization",
+		Query:       `avg by (container) (rate(container_cpu_usage_seconds_total{pod=~"POD_REGEX", namespace="NAMESPACE", container!="", container!="POD"}[5m])) * 100`,
+		Unit:        "percent",
+		Description: "CPU utilization percentage per container",
+	}
+
+	ContainerMemoryQuery = PrometheusQuery{
+		Name:        "memory_utilization",
+		Query:       `avg by (container) (container_memory_usage_bytes{pod=~"POD_REGEX", namespace="NAMESPACE", container!="", container!="POD"}) / 1024 / 1024`,
+		Unit:        "MB",
+		Description: "Memory utilization in MB per container",
+	}
+)
+
+// SetByContainer also collects CPU and memory per container into MetricsData.Containers, to tell
+// an app from its sidecars in multi-container pods
+func (p *PrometheusClient) SetByContainer(enabled bool) {
+	p.byContainer = enabled
+}
+
+// collectContainerMetrics queries CPU and memory grouped by the container label and returns them
+// keyed by container and metric name. A failed query is skipped like in collectResourceMetrics.
+func (p *PrometheusClient) collectContainerMetrics(ctx context.Context, resourceName, resourceType, namespace, duration string) (map[string]map[string]MetricValue, error) {
+	startTime, endTime, err := p.timeRange(duration)
+	if err != nil {
+		return nil, err
+	}
+	podRegex := p.podRegex(ctx, resourceName, resourceType, namespace)
+
+	containers := make(map[string]map[string]MetricValue)
+	for _, query := range []PrometheusQuery{ContainerCPUQuery, ContainerMemoryQuery} {
+		series, err := p.querySeries(ctx, expandResourceQuery(query.Query, resourceName, resourceType, namespace, podRegex), startTime, endTime)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			fmt.Fprintf(os.Stderr, "Warning: skipping per-container %s for %s/%s: %v\n", query.Name, namespace, resourceName, err)
+			continue
+		}
+		for _, s := range series {
+			container := s.Labels["container"]
+			if container == "" || len(s.Values) == 0 {
+				continue
+			}
+			if containers[container] == nil {
+				containers[container] = make(map[string]MetricValue)
+			}
+			containers[container][query.Name] = newMetricValue(query, s.Values)
+		}
+	}
+	return containers, nil
+}
+
+// containerNames returns the containers of a per-container breakdown in alphabetical order
+func containerNames(containers map[string]map[string]MetricValue) []string {
+	names := make([]string, 0, len(containers))
+	for name := range containers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writeContainerBreakdown adds the per-container CPU and memory to the analysis prompt
+func writeContainerBreakdown(prompt *strings.Builder, containers map[string]map[string]MetricValue) {
+	if len(containers) == 0 {
+		return
+	}
+
+	prompt.WriteString("\nPER-CONTAINER BREAKDOWN (average per pod):\n")
+	for _, container := range containerNames(containers) {
+		var parts []string
+		for _, name := range []string{ContainerCPUQuery.Name, ContainerMemoryQuery.Name} {
+			if metric, ok := containers[container][name]; ok {
+				parts = append(parts, fmt.Sprintf("%s (%s) avg=%.2f, p95=%.2f, peak=%.2f", name, metric.Unit, metric.Average, metric.P95, metric.Peak))
+			}
+		}
+		prompt.WriteString(fmt.Sprintf("- %s: %s\n", container, strings.Join(parts, "; ")))
+	}
+	prompt.WriteString("Attribute the usage to the container responsible. When a sidecar (e.g. istio-proxy, envoy, a log shipper)\n")
+	prompt.WriteString("uses a large share, recommend tuning that container's requests, limits or configuration rather than scaling the workload.\n")
+}
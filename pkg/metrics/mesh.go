@@ -0,0 +1,90 @@
+package metrics
+
+import "fmt"
+
+// Service meshes supported by --mesh
+const (
+	MeshIstio   = "istio"
+	MeshLinkerd = "linkerd"
+)
+
+// Request and error rate metric names, shared by every mesh
+const (
+	RequestRateMetric = "request_rate"
+	ErrorRateMetric   = "error_rate"
+)
+
+// Service mesh traffic metrics. The error rate is the percentage of 5xx responses; the 5xx side
+// falls back to 0 so an error-free workload still reports a rate, while a workload without mesh
+// metrics returns no data at all.
+var (
+	IstioRequestRateQuery = PrometheusQuery{
+		Name:        RequestRateMetric,
+		Query:       `sum(rate(istio_requests_total{reporter="destination", destination_workload="RESOURCE_NAME", destination_workload_namespace="NAMESPACE"}[5m]))`,
+		Unit:        "req/s",
+		Description: "Inbound requests per second reported by Istio",
+	}
+
+	IstioErrorRateQuery = PrometheusQuery{
+		Name:        ErrorRateMetric,
+		Query:       `(sum(rate(istio_requests_total{reporter="destination", destination_workload="RESOURCE_NAME", destination_workload_namespace="NAMESPACE", response_code=~"5.."}[5m])) or vector(0)) / sum(rate(istio_requests_total{reporter="destination", destination_workload="RESOURCE_NAME", destination_workload_namespace="NAMESPACE"}[5m])) * 100`,
+		Unit:        "percent",
+		Description: "Percentage of inbound requests answered with a 5xx, reported by Istio",
+	}
+
+	LinkerdRequestRateQuery = PrometheusQuery{
+		Name:        RequestRateMetric,
+		Query:       `sum(rate(request_total{direction="inbound", pod=~"POD_REGEX", namespace="NAMESPACE"}[5m]))`,
+		Unit:        "req/s",
+		Description: "Inbound requests per second reported by the Linkerd proxy",
+	}
+
+	LinkerdErrorRateQuery = PrometheusQuery{
+		Name:        ErrorRateMetric,
+		Query:       `(sum(rate(response_total{direction="inbound", pod=~"POD_REGEX", namespace="NAMESPACE", status_code=~"5.."}[5m])) or vector(0)) / sum(rate(response_total{direction="inbound", pod=~"POD_REGEX", namespace="NAMESPACE"}[5m])) * 100`,
+		Unit:        "percent",
+		Description: "Percentage of inbound requests answered with a 5xx, reported by the Linkerd proxy",
+	}
+)
+
+// MeshQueries returns the request and error rate queries of a service mesh
+func MeshQueries(mesh string) ([]PrometheusQuery, error) {
+	switch mesh {
+	case "":
+		return nil, nil
+	case MeshIstio:
+		return []PrometheusQuery{IstioRequestRateQuery, IstioErrorRateQuery}, nil
+	case MeshLinkerd:
+		return []PrometheusQuery{LinkerdRequestRateQuery, LinkerdErrorRateQuery}, nil
+	default:
+		return nil, fmt.Errorf("unsupported mesh %q (supported: istio, linkerd)", mesh)
+	}
+}
+
+// WithMeshQueries adds the mesh traffic queries to queries, starting from the standard set when
+// queries is empty. Queries of the same name, e.g. from --queries-file, take precedence.
+func WithMeshQueries(queries []PrometheusQuery, mesh string) ([]PrometheusQuery, error) {
+	meshQueries, err := MeshQueries(mesh)
+	if err != nil || len(meshQueries) == 0 {
+		return queries, err
+	}
+	if len(queries) == 0 {
+		queries = GetStandardQueries()
+	}
+	return mergeQueries(meshQueries, queries), nil
+}
+
+// IsMeshMetric reports whether the metric name is one of the service mesh traffic metrics
+func IsMeshMetric(name string) bool {
+	return name == RequestRateMetric || name == ErrorRateMetric
+}
+
+// meshRequestRateQuery returns the request rate query of a mesh expanded for a workload with the
+// same pod regex as the collected metrics, for use in KEDA scalers
+func meshRequestRateQuery(mesh, resourceName, namespace, podRegex string) string {
+	queries, err := MeshQueries(mesh)
+	if err != nil || len(queries) == 0 {
+		return ""
+	}
+	return expandQuery(queries[0].Query, resourceName, namespace, podRegex)
+}
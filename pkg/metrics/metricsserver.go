@@ -0,0 +1,149 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/helmcode/kubectl-ai/pkg/k8s"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// podMetricsGVR is the metrics.k8s.io resource served by metrics-server
+var podMetricsGVR = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+
+// MetricsServerClient reads current pod usage from the metrics.k8s.io API. metrics-server keeps
+// no history, so every metric is a single point in time.
+type MetricsServerClient struct {
+	k8sClient *k8s.Client
+}
+
+// NewMetricsServerClient creates a metrics-server client, checking that the metrics.k8s.io API is served
+func NewMetricsServerClient(ctx context.Context, k8sClient *k8s.Client) (*MetricsServerClient, error) {
+	_, err := k8sClient.GetDynamicClient().Resource(podMetricsGVR).List(ctx, metav1.ListOptions{Limit: 1})
+	if err != nil {
+		return nil, fmt.Errorf("metrics.k8s.io API not available (is metrics-server installed?): %w", err)
+	}
+	return &MetricsServerClient{k8sClient: k8sClient}, nil
+}
+
+// Close is a no-op, metrics-server is reached through the API server
+func (m *MetricsServerClient) Close() error {
+	return nil
+}
+
+// GatherMetrics collects current CPU and memory usage and the pod count for the specified resources
+func (m *MetricsServerClient) GatherMetrics(ctx context.Context, resources []interface{}, duration string) (map[string]*MetricsData, error) {
+	metricsData := make(map[string]*MetricsData)
+
+	for _, resource := range expandLists(resources) {
+		resourceName, resourceType, namespace, err := extractResourceInfoFromK8sObject(resource)
+		if err != nil || !IsSupportedResourceType(resourceType) {
+			continue
+		}
+
+		metrics, err := m.collectResourceMetrics(ctx, resourceName, resourceType, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect metrics for %s/%s: %w", namespace, resourceName, err)
+		}
+
+		key := fmt.Sprintf("%s/%s", namespace, resourceName)
+		metricsData[key] = &MetricsData{
+			ResourceName: resourceName,
+			ResourceType: resourceType,
+			Namespace:    namespace,
+			Metrics:      metrics,
+			Duration:     duration,
+			Timestamp:    time.Now(),
+			Source:       SourceMetricsServer,
+		}
+	}
+
+	return metricsData, nil
+}
+
+// collectResourceMetrics averages the current usage of the workload's pods
+func (m *MetricsServerClient) collectResourceMetrics(ctx context.Context, resourceName, resourceType, namespace string) (map[string]MetricValue, error) {
+	pattern, err := m.k8sClient.PodNamePattern(ctx, namespace, resourceType, resourceName)
+	if err != nil {
+		pattern = regexp.QuoteMeta(resourceName) + "-.*"
+	}
+	podName, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := m.k8sClient.GetDynamicClient().Resource(podMetricsGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var pods int
+	var cpuPercent, memoryMB float64
+	timestamp := time.Now()
+	for _, item := range list.Items {
+		if !podName.MatchString(item.GetName()) {
+			continue
+		}
+		cpu, memory := podUsage(&item)
+		cpuPercent += cpu * 100
+		memoryMB += memory / 1024 / 1024
+		pods++
+		if ts, found, _ := unstructured.NestedString(item.Object, "timestamp"); found {
+			if parsed, err := time.Parse(time.RFC3339, ts); err == nil {
+				timestamp = parsed
+			}
+		}
+	}
+
+	metrics := make(map[string]MetricValue)
+	if pods == 0 {
+		return metrics, nil
+	}
+
+	pointInTime := func(query PrometheusQuery, value float64) {
+		metrics[query.Name] = MetricValue{
+			Name:    query.Name,
+			Unit:    query.Unit,
+			Values:  []TimestampedValue{{Timestamp: timestamp, Value: value}},
+			Average: value,
+			Peak:    value,
+			Minimum: value,
+			Current: value,
+			P50:     value,
+			P95:     value,
+			P99:     value,
+			Labels:  make(map[string]string),
+		}
+	}
+	pointInTime(CPUUtilizationQuery, cpuPercent/float64(pods))
+	pointInTime(MemoryUtilizationQuery, memoryMB/float64(pods))
+	if resourceType != "Pod" {
+		pointInTime(PodReplicasQuery, float64(pods))
+	}
+
+	return metrics, nil
+}
+
+// podUsage sums the CPU (cores) and memory (bytes) usage of a PodMetrics object's containers
+func podUsage(podMetrics *unstructured.Unstructured) (cpu, memory float64) {
+	containers, _, _ := unstructured.NestedSlice(podMetrics.Object, "containers")
+	for _, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		usage, _, _ := unstructured.NestedStringMap(container, "usage")
+		if q, err := resource.ParseQuantity(usage["cpu"]); err == nil {
+			cpu += q.AsApproximateFloat64()
+		}
+		if q, err := resource.ParseQuantity(usage["memory"]); err == nil {
+			memory += q.AsApproximateFloat64()
+		}
+	}
+	return cpu, memory
+}
@@ -3,32 +3,70 @@ package metrics
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/url"
 	"os"
-	"os/exec"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/helmcode/kubectl-ai/pkg/formatter"
 	"github.com/helmcode/kubectl-ai/pkg/k8s"
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// ProgressOutput receives connection progress messages. Commands whose stdout must stay
+// machine-readable point it at stderr.
+var ProgressOutput io.Writer = color.Output
+
+// errQueryTimeout marks a range query that exceeded the query timeout
+var errQueryTimeout = errors.New("timed out")
+
+// VerboseOutput receives each executed Prometheus query with its result count when set (e.g. by --verbose)
+var VerboseOutput io.Writer
+
 // PrometheusClient handles communication with Prometheus
 type PrometheusClient struct {
-	url            string
-	client         *http.Client
-	portForwardCmd *exec.Cmd
-	localPort      string
-	isPortForward  bool
+	url           string
+	client        *http.Client
+	portForward   *k8s.PortForward
+	localPort     string
+	isPortForward bool
+	concurrency   int
+	requests      chan struct{}
+	auth          AuthConfig
+	step          time.Duration
+	queries       []PrometheusQuery
+	showQueries   bool
+	byContainer   bool
+	progress      ProgressFunc
+	queryTimeout  time.Duration
+	start         time.Time
+	end           time.Time
+	k8sClient     *k8s.Client
 }
 
+const (
+	// DefaultQueryTimeout bounds each range query; heavy queries over long durations may need more
+	DefaultQueryTimeout = 30 * time.Second
+
+	// connectionTestTimeout bounds each connection check, which only runs a trivial query
+	connectionTestTimeout = 30 * time.Second
+)
+
 // PrometheusResponse represents the response from Prometheus API
 type PrometheusResponse struct {
 	Status string `json:"status"`
@@ -44,82 +82,103 @@ type PrometheusResponse struct {
 	ErrorType string `json:"errorType,omitempty"`
 }
 
-// NewPrometheusClient creates a new Prometheus client with auto-detection and port-forward support
-func NewPrometheusClient(prometheusURL, prometheusNamespace, kubeconfig string, k8sClient *k8s.Client) (*PrometheusClient, error) {
+// NewPrometheusClient creates a new Prometheus client with auto-detection and port-forward support.
+// serviceNames replaces the default service names tried during auto-detection when not empty.
+func NewPrometheusClient(ctx context.Context, prometheusURL, prometheusNamespace string, serviceNames []string, k8sClient *k8s.Client, auth AuthConfig) (*PrometheusClient, error) {
 	var finalURL string
-	var portForwardCmd *exec.Cmd
+	var portForward *k8s.PortForward
 	var localPort string
 	var isPortForward bool
 
 	if prometheusURL != "" {
-		// Use provided URL
-		finalURL = prometheusURL
+		// Use provided URL, rejecting malformed ones before any request is made
+		var err error
+		finalURL, err = normalizePrometheusURL(prometheusURL)
+		if err != nil {
+			return nil, err
+		}
 		green := color.New(color.FgGreen)
-		green.Printf("✓ Using provided Prometheus URL: %s\n", prometheusURL)
+		green.Fprintf(ProgressOutput, "✓ Using provided Prometheus URL: %s\n", prometheusURL)
 	} else {
 		// Auto-detect Prometheus
-		serviceName, serviceNamespace, servicePort, err := detectPrometheusService(k8sClient, prometheusNamespace)
+		serviceName, serviceNamespace, servicePort, err := detectPrometheusService(ctx, k8sClient, prometheusNamespace, serviceNames)
 		if err != nil {
-			fmt.Printf("❌ Failed to auto-detect Prometheus\n")
+			fmt.Fprintf(ProgressOutput, "❌ Failed to auto-detect Prometheus\n")
 			return nil, fmt.Errorf("failed to auto-detect Prometheus: %w", err)
 		}
 		green := color.New(color.FgGreen)
-		green.Printf("✓ Found Prometheus: %s/%s:%d\n", serviceNamespace, serviceName, servicePort)
+		green.Fprintf(ProgressOutput, "✓ Found Prometheus: %s/%s:%d\n", serviceNamespace, serviceName, servicePort)
 
 		// Check if we're running in-cluster or outside
 		if isRunningInCluster() {
 			// Use cluster-internal URL
-			finalURL = fmt.Sprintf("http://%s.%s.svc.cluster.local:%d", serviceName, serviceNamespace, servicePort)
+			finalURL = fmt.Sprintf("http://%s.%s.svc.cluster.local:%d/%s", serviceName, serviceNamespace, servicePort, apiPathPrefix(serviceName))
 			green := color.New(color.FgGreen)
-			green.Printf("✓ Running in-cluster, using internal URL\n")
+			green.Fprintf(ProgressOutput, "✓ Running in-cluster, using internal URL\n")
 		} else {
-			// Set up port-forward for external access
-			localPort = "9090" // Use a known port
+			// Set up port-forward for external access on a free local port
 			green := color.New(color.FgGreen)
-			green.Printf("✓ Setting up port-forward %s/%s:%d -> localhost:%s\n",
-				serviceNamespace, serviceName, servicePort, localPort)
-			portForwardCmd, err = setupPortForward(serviceName, serviceNamespace, servicePort, localPort, kubeconfig)
+			green.Fprintf(ProgressOutput, "✓ Setting up port-forward to %s/%s:%d\n", serviceNamespace, serviceName, servicePort)
+			portForward, err = k8sClient.PortForwardService(ctx, serviceNamespace, serviceName, servicePort)
 			if err != nil {
-				fmt.Printf("❌ Failed to setup port-forward\n")
+				fmt.Fprintf(ProgressOutput, "❌ Failed to setup port-forward\n")
 				return nil, fmt.Errorf("failed to setup port-forward: %w", err)
 			}
-			finalURL = fmt.Sprintf("http://localhost:%s", localPort)
+			localPort = strconv.Itoa(portForward.LocalPort)
+			finalURL = fmt.Sprintf("http://localhost:%s/%s", localPort, apiPathPrefix(serviceName))
 			isPortForward = true
-
-			// Wait a bit for port-forward to be ready
-			time.Sleep(2 * time.Second)
 		}
 	}
 
 	// Ensure URL has proper format
-	if !strings.HasPrefix(finalURL, "http") {
-		finalURL = "http://" + finalURL
-	}
 	if !strings.HasSuffix(finalURL, "/") {
 		finalURL += "/"
 	}
 
+	transport, err := auth.newTransport()
+	if err != nil {
+		if portForward != nil {
+			portForward.Close()
+		}
+		return nil, err
+	}
+
+	// Requests are bounded by per-request context deadlines instead of a client-wide timeout
 	client := &PrometheusClient{
-		url:            finalURL,
-		client:         &http.Client{Timeout: 30 * time.Second},
-		portForwardCmd: portForwardCmd,
-		localPort:      localPort,
-		isPortForward:  isPortForward,
+		url:           finalURL,
+		client:        &http.Client{Transport: transport},
+		portForward:   portForward,
+		localPort:     localPort,
+		isPortForward: isPortForward,
+		concurrency:   k8s.DefaultConcurrency,
+		requests:      make(chan struct{}, k8s.DefaultConcurrency),
+		queryTimeout:  DefaultQueryTimeout,
+		auth:          auth,
+		k8sClient:     k8sClient,
 	}
 
-	// Test connection
-	if err := client.testConnection(); err != nil {
-		fmt.Printf("❌ Failed to connect to Prometheus at %s\n", finalURL)
+	// Test connection, giving a fresh port-forward some time to become ready
+	testConnection := client.testConnection
+	if isPortForward {
+		testConnection = func(ctx context.Context) error {
+			return client.waitForReady(ctx, portForwardReadyTimeout)
+		}
+	}
+	if err := testConnection(ctx); err != nil {
+		fmt.Fprintf(ProgressOutput, "❌ Failed to connect to Prometheus at %s\n", finalURL)
+		if prometheusURL != "" {
+			fmt.Fprintln(ProgressOutput, "💡 Omit --prometheus-url to auto-detect Prometheus in the cluster (narrow the search with --prometheus-namespace)")
+		}
 		client.Close() // Clean up port-forward if it was created
 		return nil, fmt.Errorf("failed to connect to Prometheus at %s: %w", finalURL, err)
 	}
 
 	if isPortForward {
 		green := color.New(color.FgGreen)
-		green.Printf("✓ Port-forward active: %s -> localhost:%s\n", finalURL, localPort)
+		green.Fprintf(ProgressOutput, "✓ Port-forward active: %s -> localhost:%s\n", finalURL, localPort)
 	} else {
 		green := color.New(color.FgGreen)
-		green.Printf("✓ Connected to Prometheus: %s\n", finalURL)
+		green.Fprintf(ProgressOutput, "✓ Connected to Prometheus: %s\n", finalURL)
 	}
 
 	return client, nil
@@ -127,12 +186,38 @@ func NewPrometheusClient(prometheusURL, prometheusNamespace, kubeconfig string,
 
 // Close cleans up resources, including stopping port-forward if active
 func (p *PrometheusClient) Close() error {
-	if p.portForwardCmd != nil && p.portForwardCmd.Process != nil {
-		return p.portForwardCmd.Process.Kill()
+	if p.portForward != nil {
+		p.portForward.Close()
 	}
 	return nil
 }
 
+// portForwardReadyTimeout bounds how long we wait for a new port-forward to accept connections
+const portForwardReadyTimeout = 15 * time.Second
+
+// waitForReady polls testConnection until it succeeds, the timeout expires or ctx is cancelled
+func (p *PrometheusClient) waitForReady(ctx context.Context, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		err := p.testConnection(ctx)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("port-forward not ready after %s: %w", timeout, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
+
 // isRunningInCluster checks if we're running inside a Kubernetes cluster
 func isRunningInCluster() bool {
 	// Check for service account token (standard way to detect in-cluster)
@@ -142,15 +227,127 @@ func isRunningInCluster() bool {
 	return false
 }
 
+// DefaultPrometheusServiceNames are the service names tried during auto-detection. Besides
+// Prometheus itself they cover the Prometheus-compatible query APIs of VictoriaMetrics and Thanos.
+var DefaultPrometheusServiceNames = []string{
+	"prometheus-server",
+	"prometheus-service",
+	"prometheus",
+	"kube-prometheus-stack-prometheus",
+	"prometheus-kube-prometheus-prometheus",
+	"vmselect",
+	"victoria-metrics",
+	"victoria-metrics-single-server",
+	"vmsingle",
+	"thanos-query",
+	"thanos-querier",
+	"thanos-query-frontend",
+}
+
+// apiPathPrefix returns the path the Prometheus API is served under for a detected service.
+// Clustered VictoriaMetrics serves it per tenant below /select/<tenant>/prometheus.
+func apiPathPrefix(serviceName string) string {
+	if strings.Contains(serviceName, "vmselect") {
+		return "select/0/prometheus/"
+	}
+	return ""
+}
+
+// httpPort picks the HTTP port of a service, since query services such as Thanos also expose gRPC
+func httpPort(service *corev1.Service) int {
+	for _, port := range service.Spec.Ports {
+		name := strings.ToLower(port.Name)
+		if strings.Contains(name, "http") || strings.Contains(name, "web") {
+			return int(port.Port)
+		}
+	}
+	if len(service.Spec.Ports) > 0 {
+		return int(service.Spec.Ports[0].Port)
+	}
+	return 80
+}
+
+// prometheusPort is the port Prometheus serves its API on by default
+const prometheusPort = 9090
+
+// nonQueryServiceNames mark services that carry "prometheus" in their name without serving the
+// query API, such as exporters and the operator deployed by kube-prometheus-stack
+var nonQueryServiceNames = []string{
+	"exporter",
+	"operator",
+	"alertmanager",
+	"kube-state-metrics",
+	"pushgateway",
+	"grafana",
+}
+
+// matchPrometheusService picks the Prometheus service out of the services in a namespace and the
+// port to reach it on. Services named like one of the patterns win, then services whose name
+// contains "prometheus", preferring the ones exposing port 9090, then any service exposing 9090.
+func matchPrometheusService(services []corev1.Service, servicePatterns []string) (*corev1.Service, int, bool) {
+	for _, pattern := range servicePatterns {
+		for i := range services {
+			if services[i].Name == pattern {
+				return &services[i], httpPort(&services[i]), true
+			}
+		}
+	}
+
+	var named, namedOnPort, onPort *corev1.Service
+	for i := range services {
+		service := &services[i]
+		if isNonQueryService(service.Name) {
+			continue
+		}
+		exposesPort := hasPort(service, prometheusPort)
+		isNamed := strings.Contains(strings.ToLower(service.Name), "prometheus")
+		switch {
+		case isNamed && exposesPort && namedOnPort == nil:
+			namedOnPort = service
+		case isNamed && named == nil:
+			named = service
+		case exposesPort && onPort == nil:
+			onPort = service
+		}
+	}
+
+	switch {
+	case namedOnPort != nil:
+		return namedOnPort, prometheusPort, true
+	case named != nil:
+		return named, httpPort(named), true
+	case onPort != nil:
+		return onPort, prometheusPort, true
+	}
+	return nil, 0, false
+}
+
+// isNonQueryService reports whether a service name looks like a Prometheus component other than the server
+func isNonQueryService(name string) bool {
+	name = strings.ToLower(name)
+	for _, marker := range nonQueryServiceNames {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPort reports whether a service exposes the given port
+func hasPort(service *corev1.Service, port int) bool {
+	for _, p := range service.Spec.Ports {
+		if int(p.Port) == port {
+			return true
+		}
+	}
+	return false
+}
+
 // detectPrometheusService detects the Prometheus service and returns its details
-func detectPrometheusService(k8sClient *k8s.Client, prometheusNamespace string) (string, string, int, error) {
-	// Common Prometheus service patterns
-	servicePatterns := []string{
-		"prometheus-server",
-		"prometheus-service",
-		"prometheus",
-		"kube-prometheus-stack-prometheus",
-		"prometheus-kube-prometheus-prometheus",
+func detectPrometheusService(ctx context.Context, k8sClient *k8s.Client, prometheusNamespace string, serviceNames []string) (string, string, int, error) {
+	servicePatterns := DefaultPrometheusServiceNames
+	if len(serviceNames) > 0 {
+		servicePatterns = serviceNames
 	}
 
 	// Common Prometheus namespaces
@@ -160,84 +357,71 @@ func detectPrometheusService(k8sClient *k8s.Client, prometheusNamespace string)
 		"monitoring",
 		"kube-prometheus-stack",
 		"observability",
+		"victoria-metrics",
+		"vm",
+		"thanos",
 		"default",
 	}
 
 	if prometheusNamespace != "" {
 		namespaces = []string{prometheusNamespace}
+
+		// The namespace is known, so look at what is actually deployed there instead of
+		// relying on the service being named like one of the patterns
+		services, err := k8sClient.GetClientset().CoreV1().Services(prometheusNamespace).List(ctx, metav1.ListOptions{})
+		if err == nil {
+			if service, port, ok := matchPrometheusService(services.Items, servicePatterns); ok {
+				return service.Name, prometheusNamespace, port, nil
+			}
+		}
 	}
 
 	for _, ns := range namespaces {
 		for _, pattern := range servicePatterns {
 			// Try to find the service
-			service, err := k8sClient.GetClientset().CoreV1().Services(ns).Get(context.TODO(), pattern, metav1.GetOptions{})
+			service, err := k8sClient.GetClientset().CoreV1().Services(ns).Get(ctx, pattern, metav1.GetOptions{})
 			if err == nil {
 				// Found service, return details
-				port := 80
-				if len(service.Spec.Ports) > 0 {
-					port = int(service.Spec.Ports[0].Port)
-				}
-				return service.Name, ns, port, nil
+				return service.Name, ns, httpPort(service), nil
 			}
 		}
 	}
 
-	return "", "", 0, fmt.Errorf("could not auto-detect Prometheus service in any of the following namespaces: %v", namespaces)
-}
-
-// setupPortForward creates a kubectl port-forward to the Prometheus service
-func setupPortForward(serviceName, namespace string, servicePort int, localPort, kubeconfig string) (*exec.Cmd, error) {
-	// Build kubectl port-forward command
-	args := []string{
-		"port-forward",
-		fmt.Sprintf("service/%s", serviceName),
-		fmt.Sprintf("%s:%d", localPort, servicePort),
-		"-n", namespace,
-	}
-
-	// Add kubeconfig if provided
-	if kubeconfig != "" {
-		args = append(args, "--kubeconfig", kubeconfig)
-	}
-
-	cmd := exec.Command("kubectl", args...)
-
-	// Set up output pipes for debugging
-	cmd.Stdout = nil // Suppress output
-	cmd.Stderr = nil // Suppress error output
-
-	// Start the port-forward in the background
-	err := cmd.Start()
-	if err != nil {
-		return nil, fmt.Errorf("failed to start port-forward: %w", err)
-	}
-
-	return cmd, nil
+	return "", "", 0, fmt.Errorf("could not auto-detect any of the services %v in the namespaces %v (set --prometheus-service-name or --prometheus-url)", servicePatterns, namespaces)
 }
 
 // detectPrometheus attempts to auto-detect Prometheus in the cluster (legacy function)
-func detectPrometheus(k8sClient *k8s.Client, prometheusNamespace string) (string, error) {
-	serviceName, serviceNamespace, servicePort, err := detectPrometheusService(k8sClient, prometheusNamespace)
+func detectPrometheus(ctx context.Context, k8sClient *k8s.Client, prometheusNamespace string) (string, error) {
+	serviceName, serviceNamespace, servicePort, err := detectPrometheusService(ctx, k8sClient, prometheusNamespace, nil)
 	if err != nil {
 		return "", err
 	}
 
 	// Return cluster-internal URL
-	return fmt.Sprintf("http://%s.%s.svc.cluster.local:%d", serviceName, serviceNamespace, servicePort), nil
+	return fmt.Sprintf("http://%s.%s.svc.cluster.local:%d/%s", serviceName, serviceNamespace, servicePort, apiPathPrefix(serviceName)), nil
 }
 
 // testConnection tests the connection to Prometheus
-func (p *PrometheusClient) testConnection() error {
+func (p *PrometheusClient) testConnection(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, connectionTestTimeout)
+	defer cancel()
+
 	testURL := p.url + "api/v1/query?query=up"
 
-	resp, err := p.client.Get(testURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", testURL, nil)
+	if err != nil {
+		return err
+	}
+	p.auth.apply(req)
+
+	resp, err := p.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("connection failed: %w", err)
+		return describeConnectionError(p.url, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+		return describeHTTPStatus(resp)
 	}
 
 	// Try to parse response
@@ -258,40 +442,124 @@ func (p *PrometheusClient) GetURL() string {
 	return p.url
 }
 
+// SetQueryTimeout bounds each range query, so one slow query is skipped instead of stalling the run
+func (p *PrometheusClient) SetQueryTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultQueryTimeout
+	}
+	p.queryTimeout = timeout
+}
+
+// SetConcurrency sets how many resources are collected in parallel and how many queries are in
+// flight at once across all of them
+func (p *PrometheusClient) SetConcurrency(n int) {
+	if n < 1 {
+		n = 1
+	}
+	p.concurrency = n
+	p.requests = make(chan struct{}, n)
+}
+
 // GatherMetrics collects metrics for the specified resources
-func (p *PrometheusClient) GatherMetrics(resources []interface{}, duration string) (map[string]*MetricsData, error) {
+func (p *PrometheusClient) GatherMetrics(ctx context.Context, resources []interface{}, duration string) (map[string]*MetricsData, error) {
 	metricsData := make(map[string]*MetricsData)
+	var mu sync.Mutex
 
-	for _, resource := range resources {
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(p.concurrency)
+
+	for _, resource := range expandLists(resources) {
 		resourceName, resourceType, namespace, err := extractResourceInfoFromK8sObject(resource)
 		if err != nil {
 			// Skip resources that can't be processed (like Lists)
 			continue
 		}
 
-		// Only analyze deployments for now
-		if resourceType != "Deployment" {
+		// Only workloads with replica metrics in kube-state-metrics are analyzed
+		if !IsSupportedResourceType(resourceType) {
 			continue
 		}
 
-		// Collect metrics for this resource
-		metrics, err := p.collectResourceMetrics(resourceName, namespace, duration)
-		if err != nil {
-			return nil, fmt.Errorf("failed to collect metrics for %s/%s: %w", namespace, resourceName, err)
+		g.Go(func() error {
+			// Collect metrics for this resource
+			metrics, queries, err := p.collectResourceMetrics(gctx, resourceName, resourceType, namespace, duration)
+			if err != nil {
+				return fmt.Errorf("failed to collect metrics for %s/%s: %w", namespace, resourceName, err)
+			}
+			var containers map[string]map[string]MetricValue
+			if p.byContainer {
+				containers, err = p.collectContainerMetrics(gctx, resourceName, resourceType, namespace, duration)
+				if err != nil {
+					return fmt.Errorf("failed to collect container metrics for %s/%s: %w", namespace, resourceName, err)
+				}
+			}
+
+			key := fmt.Sprintf("%s/%s", namespace, resourceName)
+			mu.Lock()
+			metricsData[key] = &MetricsData{
+				ResourceName: resourceName,
+				ResourceType: resourceType,
+				Namespace:    namespace,
+				Metrics:      metrics,
+				Duration:     duration,
+				Timestamp:    time.Now(),
+				Queries:      queries,
+				Containers:   containers,
+			}
+			if !p.start.IsZero() {
+				metricsData[key].Start, metricsData[key].End = &p.start, &p.end
+			}
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return metricsData, nil
+}
+
+// expandLists replaces list objects (e.g. from --all) with their individual items. Jobs created by
+// a CronJob are left out, since the CronJob's metrics already cover them.
+func expandLists(resources []interface{}) []interface{} {
+	expanded := make([]interface{}, 0, len(resources))
+	for _, resource := range resources {
+		obj, ok := resource.(runtime.Object)
+		if !ok || !meta.IsListType(obj) {
+			expanded = append(expanded, resource)
+			continue
 		}
 
-		key := fmt.Sprintf("%s/%s", namespace, resourceName)
-		metricsData[key] = &MetricsData{
-			ResourceName: resourceName,
-			ResourceType: resourceType,
-			Namespace:    namespace,
-			Metrics:      metrics,
-			Duration:     duration,
-			Timestamp:    time.Now(),
+		items, err := meta.ExtractList(obj)
+		if err != nil {
+			continue
+		}
+		for _, item := range items {
+			// Listed pods belong to a workload or a whole namespace; only pods named with
+			// pod/<name> are analyzed on their own
+			if _, isPod := item.(*corev1.Pod); isPod || ownedByCronJob(item) {
+				continue
+			}
+			expanded = append(expanded, item)
 		}
 	}
+	return expanded
+}
 
-	return metricsData, nil
+// ownedByCronJob reports whether the object has a CronJob owner
+func ownedByCronJob(obj runtime.Object) bool {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+	for _, owner := range accessor.GetOwnerReferences() {
+		if owner.Kind == "CronJob" {
+			return true
+		}
+	}
+	return false
 }
 
 // extractResourceInfoFromK8sObject extracts resource information from Kubernetes native objects
@@ -366,97 +634,325 @@ func extractResourceInfo(resource map[string]interface{}) (string, string, strin
 	return name, kind, namespace, nil
 }
 
-// collectResourceMetrics collects metrics for a specific resource
-func (p *PrometheusClient) collectResourceMetrics(resourceName, namespace, duration string) (map[string]MetricValue, error) {
-	metrics := make(map[string]MetricValue)
-
+// collectResourceMetrics collects metrics for a specific resource. Its queries run
+// concurrently and share the client's request budget with the other resources.
+func (p *PrometheusClient) collectResourceMetrics(ctx context.Context, resourceName, resourceType, namespace, duration string) (map[string]MetricValue, []QueryDiagnostic, error) {
 	// Get time range
-	endTime := time.Now()
-	startTime, err := parseDuration(duration)
+	startTime, endTime, err := p.timeRange(duration)
 	if err != nil {
-		return nil, fmt.Errorf("invalid duration: %w", err)
+		return nil, nil, err
+	}
+	if err := p.validateStep(startTime, endTime); err != nil {
+		return nil, nil, err
 	}
 
-	// Collect standard metrics
-	queries := GetStandardQueries()
-
-	for _, query := range queries {
-		// Replace placeholders in query
-		finalQuery := strings.ReplaceAll(query.Query, "RESOURCE_NAME", resourceName)
-		finalQuery = strings.ReplaceAll(finalQuery, "NAMESPACE", namespace)
+	// Collect standard metrics (or the configured custom set)
+	queries := p.queries
+	if len(queries) == 0 {
+		queries = GetStandardQueries()
+	}
+	queries = queriesForResourceType(queries, resourceType)
+	podRegex := p.podRegex(ctx, resourceName, resourceType, namespace)
 
-		// Execute query
-		values, err := p.queryRange(finalQuery, startTime, endTime)
+	metrics := make(map[string]MetricValue)
+	// Diagnostics are kept per query so they are reported in query order
+	diagnostics := make([][]QueryDiagnostic, len(queries))
+	completed := 0
+	var mu sync.Mutex
+
+	record := func(i int, name, query string, values []TimestampedValue, err error) {
+		if !p.showQueries {
+			return
+		}
+		diagnostic := QueryDiagnostic{Name: name, Query: query, Points: len(values)}
 		if err != nil {
-			// Log error but continue with other metrics
-			continue
+			diagnostic.Error = err.Error()
 		}
+		mu.Lock()
+		diagnostics[i] = append(diagnostics[i], diagnostic)
+		mu.Unlock()
+	}
 
-		if len(values) == 0 {
-			continue
-		}
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(p.concurrency)
+
+	for i, query := range queries {
+		g.Go(func() error {
+			defer func() {
+				if p.progress == nil {
+					return
+				}
+				mu.Lock()
+				completed++
+				n := completed
+				mu.Unlock()
+				p.progress(fmt.Sprintf("%s/%s", namespace, resourceName), query.Name, n, len(queries))
+			}()
+
+			// Replace placeholders in query
+			finalQuery := expandResourceQuery(query.Query, resourceName, resourceType, namespace, podRegex)
+
+			// Execute query
+			values, err := p.queryRange(gctx, finalQuery, query.Aggregation, startTime, endTime)
+			record(i, query.Name, finalQuery, values, err)
+			if err != nil {
+				if gctx.Err() != nil {
+					return gctx.Err()
+				}
+				if errors.Is(err, errQueryTimeout) {
+					fmt.Fprintf(os.Stderr, "Warning: skipping %s for %s/%s, the query %v (raise --prometheus-query-timeout)\n", query.Name, namespace, resourceName, err)
+				}
+				// Skip this metric but continue with the others
+				return nil
+			}
 
-		// If we have very few data points for CPU/Memory, try alternative queries
-		if len(values) < 10 && (query.Name == "cpu_utilization" || query.Name == "memory_utilization") {
-			// Try alternative query for recent data
-			alternativeQuery := ""
-			if query.Name == "cpu_utilization" {
-				alternativeQuery = `avg(rate(container_cpu_usage_seconds_total{pod=~"RESOURCE_NAME.*", namespace="NAMESPACE"}[1m])) * 100`
-			} else if query.Name == "memory_utilization" {
-				alternativeQuery = `avg(container_memory_usage_bytes{pod=~"RESOURCE_NAME.*", namespace="NAMESPACE"}) / 1024 / 1024`
+			if len(values) == 0 {
+				return nil
 			}
 
-			if alternativeQuery != "" {
-				altQuery := strings.ReplaceAll(alternativeQuery, "RESOURCE_NAME", resourceName)
-				altQuery = strings.ReplaceAll(altQuery, "NAMESPACE", namespace)
+			// If we have very few data points for CPU/Memory, try alternative queries
+			if len(values) < 10 && (query.Name == "cpu_utilization" || query.Name == "memory_utilization") {
+				// Try alternative query for recent data
+				alternativeQuery := ""
+				if query.Name == "cpu_utilization" {
+					alternativeQuery = `avg(rate(container_cpu_usage_seconds_total{pod=~"POD_REGEX", namespace="NAMESPACE"}[1m])) * 100`
+				} else if query.Name == "memory_utilization" {
+					alternativeQuery = `avg(container_memory_usage_bytes{pod=~"POD_REGEX", namespace="NAMESPACE"}) / 1024 / 1024`
+				}
+
+				if alternativeQuery != "" {
+					altQuery := expandResourceQuery(alternativeQuery, resourceName, resourceType, namespace, podRegex)
 
-				// Try with a shorter time range (last 24 hours)
-				altStartTime := endTime.Add(-24 * time.Hour)
-				altValues, altErr := p.queryRange(altQuery, altStartTime, endTime)
+					// Try with a shorter time range (last 24 hours)
+					altStartTime := endTime.Add(-24 * time.Hour)
+					altValues, altErr := p.queryRange(gctx, altQuery, query.Aggregation, altStartTime, endTime)
+					record(i, query.Name+" (fallback, last 24h)", altQuery, altValues, altErr)
 
-				if altErr == nil && len(altValues) > len(values) {
-					values = altValues
+					if altErr == nil && len(altValues) > len(values) {
+						values = altValues
+					}
 				}
 			}
-		}
 
-		// Calculate statistics
-		avg, peak, min, current := calculateStats(values)
+			mu.Lock()
+			metrics[query.Name] = newMetricValue(query, values)
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	var flattened []QueryDiagnostic
+	for _, d := range diagnostics {
+		flattened = append(flattened, d...)
+	}
+	return metrics, flattened, nil
+}
+
+// newMetricValue calculates the statistics of a query's values
+func newMetricValue(query PrometheusQuery, values []TimestampedValue) MetricValue {
+	avg, peak, min, current := calculateStats(values)
+	p50, p95, p99 := calculatePercentiles(values)
+	return MetricValue{
+		Name:    query.Name,
+		Unit:    query.Unit,
+		Values:  values,
+		Average: avg,
+		Peak:    peak,
+		Minimum: min,
+		Current: current,
+		P50:     p50,
+		P95:     p95,
+		P99:     p99,
+		Labels:  make(map[string]string),
+	}
+}
+
+// podRegex returns a PromQL-escaped regex matching the workload's pods, see podRegexFor
+func (p *PrometheusClient) podRegex(ctx context.Context, resourceName, resourceType, namespace string) string {
+	return podRegexFor(ctx, p.k8sClient, resourceName, resourceType, namespace)
+}
 
-		metrics[query.Name] = MetricValue{
-			Name:    query.Name,
-			Unit:    query.Unit,
-			Values:  values,
-			Average: avg,
-			Peak:    peak,
-			Minimum: min,
-			Current: current,
-			Labels:  make(map[string]string),
+// podRegexFor returns a PromQL-escaped regex matching the workload's pods. When the exact pod names
+// can't be derived it falls back to "<name>-.*", which may over-match similarly named workloads.
+func podRegexFor(ctx context.Context, k8sClient *k8s.Client, resourceName, resourceType, namespace string) string {
+	pattern := regexp.QuoteMeta(resourceName) + "-.*"
+	if k8sClient != nil {
+		if exact, err := k8sClient.PodNamePattern(ctx, namespace, resourceType, resourceName); err == nil {
+			pattern = exact
 		}
 	}
+	// Backslashes must be escaped inside a PromQL double-quoted string
+	return strings.ReplaceAll(pattern, `\`, `\\`)
+}
 
-	return metrics, nil
+// expandQuery substitutes the POD_REGEX, RESOURCE_NAME and NAMESPACE placeholders.
+// The legacy pod=~"RESOURCE_NAME.*" selector is upgraded to the exact pod regex.
+func expandQuery(query, resourceName, namespace, podRegex string) string {
+	query = strings.ReplaceAll(query, `pod=~"RESOURCE_NAME.*"`, `pod=~"POD_REGEX"`)
+	query = strings.ReplaceAll(query, "POD_REGEX", podRegex)
+	query = strings.ReplaceAll(query, "RESOURCE_NAME", resourceName)
+	return strings.ReplaceAll(query, "NAMESPACE", namespace)
 }
 
-// queryRange executes a range query against Prometheus
-func (p *PrometheusClient) queryRange(query string, startTime, endTime time.Time) ([]TimestampedValue, error) {
-	// Build URL
-	queryURL := p.url + "api/v1/query_range"
+// expandResourceQuery expands a query for a resource. A bare pod is selected with an exact
+// pod="<name>" matcher instead of the pod regex.
+func expandResourceQuery(query, resourceName, resourceType, namespace, podRegex string) string {
+	if resourceType == "Pod" {
+		query = strings.ReplaceAll(query, `pod=~"RESOURCE_NAME.*"`, `pod="RESOURCE_NAME"`)
+		query = strings.ReplaceAll(query, `pod=~"POD_REGEX"`, `pod="RESOURCE_NAME"`)
+	}
+	return expandQuery(query, resourceName, namespace, podRegex)
+}
+
+// maxQueryPoints is the maximum number of points Prometheus returns for a single range query
+const maxQueryPoints = 11000
+
+// SetStep overrides the automatically selected query resolution (0 keeps the default heuristic)
+func (p *PrometheusClient) SetStep(step time.Duration) {
+	p.step = step
+}
+
+// SetTimeRange queries the fixed window from start to end instead of a lookback ending now. The
+// duration passed to GatherMetrics is then only used as the window's label.
+func (p *PrometheusClient) SetTimeRange(start, end time.Time) {
+	p.start, p.end = start, end
+}
+
+// timeRange returns the window to query: the fixed range if one is set, otherwise the
+// lookback duration ending now
+func (p *PrometheusClient) timeRange(duration string) (time.Time, time.Time, error) {
+	if !p.start.IsZero() {
+		return p.start, p.end, nil
+	}
+	startTime, err := parseDuration(duration)
+	return startTime, time.Now(), err
+}
+
+// SetShowQueries records each executed query and its data point count in MetricsData.Queries
+func (p *PrometheusClient) SetShowQueries(show bool) {
+	p.showQueries = show
+}
+
+// SetQueries replaces the set of queries collected for each resource
+func (p *PrometheusClient) SetQueries(queries []PrometheusQuery) {
+	p.queries = queries
+}
+
+// SetProgress sets a callback that is notified as each query completes (nil disables it)
+func (p *PrometheusClient) SetProgress(progress ProgressFunc) {
+	p.progress = progress
+}
+
+// stepFor returns the configured step, or one derived from the queried time range
+func (p *PrometheusClient) stepFor(startTime, endTime time.Time) time.Duration {
+	return stepFor(p.step, startTime, endTime)
+}
+
+// stepFor returns step, or one derived from the queried time range when step is 0
+func stepFor(step time.Duration, startTime, endTime time.Time) time.Duration {
+	if step > 0 {
+		return step
+	}
 
 	// Calculate appropriate step based on duration
 	duration := endTime.Sub(startTime)
-	var step string
-
 	switch {
 	case duration <= 6*time.Hour:
-		step = "300" // 5 minutes for short periods
+		return 5 * time.Minute // 5 minutes for short periods
 	case duration <= 24*time.Hour:
-		step = "900" // 15 minutes for 1 day
+		return 15 * time.Minute // 15 minutes for 1 day
 	case duration <= 7*24*time.Hour:
-		step = "3600" // 1 hour for 1 week
+		return time.Hour // 1 hour for 1 week
 	default:
-		step = "7200" // 2 hours for longer periods
+		return 2 * time.Hour // 2 hours for longer periods
+	}
+}
+
+// validateStep makes sure the range query stays within Prometheus's point limit
+func (p *PrometheusClient) validateStep(startTime, endTime time.Time) error {
+	return ValidateStep(p.step, startTime, endTime)
+}
+
+// ValidateStep makes sure a range query from startTime to endTime at step (0 selects it from the
+// range like SetStep does) stays within Prometheus's point limit
+func ValidateStep(step time.Duration, startTime, endTime time.Time) error {
+	step = stepFor(step, startTime, endTime)
+	if step < time.Second {
+		return fmt.Errorf("step %s is too small, it must be at least 1s", step)
+	}
+	if points := int64(endTime.Sub(startTime) / step); points > maxQueryPoints {
+		minStep := (endTime.Sub(startTime) / maxQueryPoints).Round(time.Second) + time.Second
+		return fmt.Errorf("step %s over %s would return %d points, exceeding Prometheus's limit of %d; use a step of at least %s",
+			step, endTime.Sub(startTime).Round(time.Second), points, maxQueryPoints, minStep)
+	}
+	return nil
+}
+
+// queryRange executes a range query against Prometheus
+// When the query returns several series (e.g. one per pod) they are combined per timestamp
+// using aggregation, see aggregateSeries.
+func (p *PrometheusClient) queryRange(ctx context.Context, query, aggregation string, startTime, endTime time.Time) ([]TimestampedValue, error) {
+	series, err := p.querySeries(ctx, query, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([][]TimestampedValue, len(series))
+	for i, s := range series {
+		values[i] = s.Values
 	}
+	return aggregateSeries(values, aggregation)
+}
+
+// querySeries executes a range query within the query timeout and returns each series with its labels
+func (p *PrometheusClient) querySeries(ctx context.Context, query string, startTime, endTime time.Time) ([]rangeSeries, error) {
+	// Wait for a request slot first, so the timeout only covers the query itself
+	select {
+	case p.requests <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-p.requests }()
+
+	start := time.Now()
+	queryCtx, cancel := context.WithTimeout(ctx, p.queryTimeout)
+	defer cancel()
+
+	series, err := p.fetchRange(queryCtx, query, startTime, endTime)
+	if err != nil && ctx.Err() == nil && errors.Is(queryCtx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("%w after %s", errQueryTimeout, p.queryTimeout)
+	}
+	if VerboseOutput != nil {
+		elapsed := time.Since(start).Round(time.Millisecond)
+		if err != nil {
+			fmt.Fprintf(VerboseOutput, "[prometheus] %s failed after %s: %v\n", query, elapsed, err)
+		} else {
+			points := 0
+			for _, s := range series {
+				points += len(s.Values)
+			}
+			fmt.Fprintf(VerboseOutput, "[prometheus] %s returned %d series, %d points (%s)\n", query, len(series), points, elapsed)
+		}
+	}
+	return series, err
+}
+
+// rangeSeries is one series of a range query result with its labels
+type rangeSeries struct {
+	Labels map[string]string
+	Values []TimestampedValue
+}
+
+// fetchRange runs a range query and parses each returned series
+func (p *PrometheusClient) fetchRange(ctx context.Context, query string, startTime, endTime time.Time) ([]rangeSeries, error) {
+	// Build URL
+	queryURL := p.url + "api/v1/query_range"
+
+	step := strconv.FormatInt(int64(p.stepFor(startTime, endTime).Seconds()), 10)
 
 	params := url.Values{}
 	params.Add("query", query)
@@ -466,7 +962,13 @@ func (p *PrometheusClient) queryRange(query string, startTime, endTime time.Time
 
 	fullURL := queryURL + "?" + params.Encode()
 
-	resp, err := p.client.Get(fullURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	p.auth.apply(req)
+
+	resp, err := p.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("query failed: %w", err)
 	}
@@ -487,15 +989,16 @@ func (p *PrometheusClient) queryRange(query string, startTime, endTime time.Time
 	}
 
 	// Parse results
-	var values []TimestampedValue
-	if len(promResp.Data.Result) > 0 {
-		result := promResp.Data.Result[0]
+	series := make([]rangeSeries, 0, len(promResp.Data.Result))
+	for _, result := range promResp.Data.Result {
+		var values []TimestampedValue
 		for _, valuePoint := range result.Values {
 			if len(valuePoint) >= 2 {
 				timestamp, _ := valuePoint[0].(float64)
 				valueStr, _ := valuePoint[1].(string)
 				value, err := strconv.ParseFloat(valueStr, 64)
-				if err != nil {
+				if err != nil || math.IsNaN(value) {
+					// NaN comes from ratios over no traffic, e.g. an error rate with zero requests
 					continue
 				}
 
@@ -505,38 +1008,140 @@ func (p *PrometheusClient) queryRange(query string, startTime, endTime time.Time
 				})
 			}
 		}
+		series = append(series, rangeSeries{Labels: result.Metric, Values: values})
+	}
+
+	return series, nil
+}
+
+// aggregateSeries combines series point by point: "avg" (the default) suits per-pod values such
+// as utilization, "sum" suits totals such as throughput, and "max" surfaces the hottest series.
+// Timestamps missing from some series are aggregated over the series that have them.
+func aggregateSeries(series [][]TimestampedValue, aggregation string) ([]TimestampedValue, error) {
+	switch aggregation {
+	case "", "avg", "sum", "max":
+	default:
+		return nil, fmt.Errorf("unsupported aggregation %q (supported: avg, sum, max)", aggregation)
+	}
+	if len(series) == 0 {
+		return nil, nil
+	}
+	if len(series) == 1 {
+		return series[0], nil
 	}
 
+	type point struct {
+		sum   float64
+		max   float64
+		count int
+	}
+	points := make(map[int64]*point)
+	for _, values := range series {
+		for _, v := range values {
+			ts := v.Timestamp.Unix()
+			pt, ok := points[ts]
+			if !ok {
+				pt = &point{max: v.Value}
+				points[ts] = pt
+			}
+			pt.sum += v.Value
+			pt.count++
+			if v.Value > pt.max {
+				pt.max = v.Value
+			}
+		}
+	}
+
+	timestamps := make([]int64, 0, len(points))
+	for ts := range points {
+		timestamps = append(timestamps, ts)
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+
+	values := make([]TimestampedValue, 0, len(timestamps))
+	for _, ts := range timestamps {
+		pt := points[ts]
+		value := pt.sum / float64(pt.count)
+		switch aggregation {
+		case "sum":
+			value = pt.sum
+		case "max":
+			value = pt.max
+		}
+		values = append(values, TimestampedValue{Timestamp: time.Unix(ts, 0), Value: value})
+	}
 	return values, nil
 }
 
-// parseDuration parses duration string to time.Time
-func parseDuration(duration string) (time.Time, error) {
-	now := time.Now()
+// durationPattern matches lookback durations such as 30m, 24h, 7d, 2w or composites like 7d12h
+var durationPattern = regexp.MustCompile(`^(\d+[wdhm])+$`)
+
+// durationPart matches a single number and unit within a duration
+var durationPart = regexp.MustCompile(`(\d+)([wdhm])`)
 
-	// Parse duration string
-	re := regexp.MustCompile(`^(\d+)([hdm])$`)
-	matches := re.FindStringSubmatch(duration)
-	if len(matches) != 3 {
-		return now, fmt.Errorf("invalid duration format: %s", duration)
+// durationUnits maps duration units to their length
+var durationUnits = map[string]time.Duration{
+	"w": 7 * 24 * time.Hour,
+	"d": 24 * time.Hour,
+	"h": time.Hour,
+	"m": time.Minute,
+}
+
+// parseLookback parses a duration made of one or more <number><unit> parts, where the unit is
+// w (weeks), d (days), h (hours) or m (minutes)
+func parseLookback(duration string) (time.Duration, error) {
+	invalid := fmt.Errorf("invalid duration %q: use a number followed by w, d, h or m (e.g. 30m, 24h, 7d, 2w), optionally combined like 7d12h", duration)
+	if !durationPattern.MatchString(duration) {
+		return 0, invalid
 	}
 
-	value, err := strconv.Atoi(matches[1])
-	if err != nil {
-		return now, fmt.Errorf("invalid duration value: %s", matches[1])
+	var total time.Duration
+	for _, part := range durationPart.FindAllStringSubmatch(duration, -1) {
+		value, err := strconv.Atoi(part[1])
+		if err != nil {
+			return 0, invalid
+		}
+		unit := durationUnits[part[2]]
+		// Reject values that would overflow time.Duration
+		if value > 0 && time.Duration(value) > (1<<63-1-total)/unit {
+			return 0, fmt.Errorf("invalid duration %q: too long", duration)
+		}
+		total += time.Duration(value) * unit
 	}
+	if total == 0 {
+		return 0, fmt.Errorf("invalid duration %q: must be greater than zero", duration)
+	}
+	return total, nil
+}
 
-	unit := matches[2]
-	switch unit {
-	case "h":
-		return now.Add(-time.Duration(value) * time.Hour), nil
-	case "d":
-		return now.Add(-time.Duration(value) * 24 * time.Hour), nil
-	case "m":
-		return now.Add(-time.Duration(value) * time.Minute), nil
-	default:
-		return now, fmt.Errorf("invalid duration unit: %s", unit)
+// FormatLookback formats a window length like the durations parseLookback accepts (e.g. 1h30m,
+// 2d6h), rounded down to the minute
+func FormatLookback(window time.Duration) string {
+	window = window.Truncate(time.Minute)
+	var b strings.Builder
+	for _, unit := range []struct {
+		suffix string
+		length time.Duration
+	}{{"d", 24 * time.Hour}, {"h", time.Hour}, {"m", time.Minute}} {
+		if n := window / unit.length; n > 0 {
+			fmt.Fprintf(&b, "%d%s", n, unit.suffix)
+			window -= n * unit.length
+		}
 	}
+	if b.Len() == 0 {
+		return "0m"
+	}
+	return b.String()
+}
+
+// parseDuration returns the start of the lookback window ending now
+func parseDuration(duration string) (time.Time, error) {
+	now := time.Now()
+	lookback, err := parseLookback(duration)
+	if err != nil {
+		return now, err
+	}
+	return now.Add(-lookback), nil
 }
 
 // calculateStats calculates basic statistics for metric values
@@ -563,3 +1168,17 @@ func calculateStats(values []TimestampedValue) (avg, peak, min, current float64)
 	avg = sum / float64(len(values))
 	return avg, peak, min, current
 }
+
+// calculatePercentiles returns the 50th, 95th and 99th percentiles of the values
+func calculatePercentiles(values []TimestampedValue) (p50, p95, p99 float64) {
+	if len(values) == 0 {
+		return 0, 0, 0
+	}
+
+	sorted := make([]float64, len(values))
+	for i, v := range values {
+		sorted[i] = v.Value
+	}
+	sort.Float64s(sorted)
+	return formatter.Percentile(sorted, 50), formatter.Percentile(sorted, 95), formatter.Percentile(sorted, 99)
+}
@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLookback(t *testing.T) {
+	tests := []struct {
+		duration string
+		want     time.Duration
+	}{
+		{"30m", 30 * time.Minute},
+		{"24h", 24 * time.Hour},
+		{"30d", 30 * 24 * time.Hour},
+		{"2w", 14 * 24 * time.Hour},
+		{"7d12h", 7*24*time.Hour + 12*time.Hour},
+		{"1w2d3h4m", 9*24*time.Hour + 3*time.Hour + 4*time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.duration, func(t *testing.T) {
+			got, err := parseLookback(tt.duration)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("parseLookback(%q) = %s, want %s", tt.duration, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLookbackInvalid(t *testing.T) {
+	for _, duration := range []string{"", "30", "h", "30s", "1.5h", "-1h", "7d 12h", "0h", "0d0m", "999999999w"} {
+		t.Run(duration, func(t *testing.T) {
+			if _, err := parseLookback(duration); err == nil {
+				t.Errorf("parseLookback(%q) succeeded, want an error", duration)
+			}
+		})
+	}
+
+	_, err := parseLookback("30s")
+	if err == nil || !strings.Contains(err.Error(), "w, d, h or m") {
+		t.Errorf("error %v doesn't list the accepted units", err)
+	}
+}
+
+func TestStepForDuration(t *testing.T) {
+	end := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		duration string
+		want     time.Duration
+	}{
+		{"6h", 5 * time.Minute},
+		{"6h1m", 15 * time.Minute},
+		{"1d", 15 * time.Minute},
+		{"1d1m", time.Hour},
+		{"1w", time.Hour},
+		{"7d1m", 2 * time.Hour},
+		{"30d", 2 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.duration, func(t *testing.T) {
+			lookback, err := parseLookback(tt.duration)
+			if err != nil {
+				t.Fatal(err)
+			}
+			start := end.Add(-lookback)
+			if got := stepFor(0, start, end); got != tt.want {
+				t.Errorf("stepFor(%s) = %s, want %s", tt.duration, got, tt.want)
+			}
+			if err := ValidateStep(0, start, end); err != nil {
+				t.Errorf("ValidateStep(%s) = %v", tt.duration, err)
+			}
+		})
+	}
+
+	if got := stepFor(time.Minute, end.Add(-30*24*time.Hour), end); got != time.Minute {
+		t.Errorf("an explicit step is kept, got %s", got)
+	}
+}
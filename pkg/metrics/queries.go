@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// QueriesFile is the format of a custom queries file passed with --queries-file
+type QueriesFile struct {
+	// Replace drops the standard queries instead of merging with them
+	Replace bool              `yaml:"replace"`
+	Queries []PrometheusQuery `yaml:"queries"`
+}
+
+// LoadQueriesFile reads custom Prometheus queries from a YAML file and returns the
+// resulting query set. Custom queries override standard ones with the same name.
+func LoadQueriesFile(path string) ([]PrometheusQuery, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read queries file: %w", err)
+	}
+
+	var file QueriesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse queries file %s: %w", path, err)
+	}
+
+	for i, query := range file.Queries {
+		if query.Name == "" || query.Query == "" {
+			return nil, fmt.Errorf("query #%d in %s must have a name and a query", i+1, path)
+		}
+		if _, err := aggregateSeries(nil, query.Aggregation); err != nil {
+			return nil, fmt.Errorf("query %s in %s: %w", query.Name, path, err)
+		}
+	}
+
+	if file.Replace {
+		if len(file.Queries) == 0 {
+			return nil, fmt.Errorf("queries file %s replaces the standard queries but defines none", path)
+		}
+		return file.Queries, nil
+	}
+
+	return mergeQueries(GetStandardQueries(), file.Queries), nil
+}
+
+// mergeQueries appends custom queries to the base set, replacing entries with the same name
+func mergeQueries(base, custom []PrometheusQuery) []PrometheusQuery {
+	merged := make([]PrometheusQuery, 0, len(base)+len(custom))
+	index := make(map[string]int)
+
+	for _, query := range base {
+		index[query.Name] = len(merged)
+		merged = append(merged, query)
+	}
+	for _, query := range custom {
+		if i, ok := index[query.Name]; ok {
+			merged[i] = query
+			continue
+		}
+		index[query.Name] = len(merged)
+		merged = append(merged, query)
+	}
+
+	return merged
+}
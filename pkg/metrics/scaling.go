@@ -0,0 +1,160 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/helmcode/kubectl-ai/pkg/k8s"
+)
+
+// ScalingReasonUnknown is the reason of replica samples that no Kubernetes event explains
+const ScalingReasonUnknown = "pod_scaling"
+
+// maxScalingChanges caps the replica changes listed in the analysis prompt to the latest ones
+const maxScalingChanges = 20
+
+// hpaNewSizePattern extracts the replica count from an HPA SuccessfulRescale message
+var hpaNewSizePattern = regexp.MustCompile(`New size: (\d+)`)
+
+// scalingEvents turns the pod_replicas metric into scaling events and explains each replica count
+// change with the Kubernetes events recorded around it
+func (a *Analyzer) scalingEvents(ctx context.Context, metricsData *MetricsData) []ScalingEvent {
+	metric, ok := metricsData.Metrics["pod_replicas"]
+	if !ok {
+		return nil
+	}
+
+	events := make([]ScalingEvent, len(metric.Values))
+	changed := false
+	for i, tv := range metric.Values {
+		events[i] = ScalingEvent{Timestamp: tv.Timestamp, Replicas: int(tv.Value), Reason: ScalingReasonUnknown}
+		if i > 0 && events[i].Replicas != events[i-1].Replicas {
+			changed = true
+		}
+	}
+	if !changed || a.k8sClient == nil || !SupportsAutoscaling(metricsData.ResourceType) {
+		return events
+	}
+
+	k8sEvents, err := a.k8sClient.ScalingEvents(ctx, metricsData.Namespace, metricsData.ResourceType, metricsData.ResourceName)
+	if err != nil {
+		return events
+	}
+	explainScaling(events, k8sEvents)
+	return events
+}
+
+// explainScaling sets the reason of every replica count change from the Kubernetes event closest
+// to it. A change is only known to happen between two samples, so events from one sample interval
+// before the previous sample to one after the change are considered, allowing for scrape lag.
+func explainScaling(events []ScalingEvent, k8sEvents []k8s.ScalingEvent) {
+	for i := 1; i < len(events); i++ {
+		if events[i].Replicas == events[i-1].Replicas {
+			continue
+		}
+		step := events[i].Timestamp.Sub(events[i-1].Timestamp)
+		from, to := events[i-1].Timestamp.Add(-step), events[i].Timestamp.Add(step)
+
+		var best *k8s.ScalingEvent
+		for j := range k8sEvents {
+			candidate := &k8sEvents[j]
+			if candidate.Time.Before(from) || candidate.Time.After(to) {
+				continue
+			}
+			if best == nil || betterScalingMatch(candidate, best, events[i]) {
+				best = candidate
+			}
+		}
+		if best != nil {
+			events[i].Reason = scalingReason(*best)
+		}
+	}
+}
+
+// betterScalingMatch reports whether candidate explains a change better than best: autoscaler
+// events beat the workload's own, then events naming the new replica count, then the closest one
+func betterScalingMatch(candidate, best *k8s.ScalingEvent, change ScalingEvent) bool {
+	if candidate.FromHPA() != best.FromHPA() {
+		return candidate.FromHPA()
+	}
+	candidateSize, bestSize := hpaNewSize(candidate.Message) == change.Replicas, hpaNewSize(best.Message) == change.Replicas
+	if candidateSize != bestSize {
+		return candidateSize
+	}
+	return absDuration(candidate.Time.Sub(change.Timestamp)) < absDuration(best.Time.Sub(change.Timestamp))
+}
+
+// hpaNewSize returns the replica count an HPA rescaled to, or -1 when the message has none
+func hpaNewSize(message string) int {
+	match := hpaNewSizePattern.FindStringSubmatch(message)
+	if match == nil {
+		return -1
+	}
+	size, err := strconv.Atoi(match[1])
+	if err != nil {
+		return -1
+	}
+	return size
+}
+
+// scalingReason describes what caused a scaling event: the metric an HPA or KEDA reacted to, or
+// a manual scale or rollout when only the workload recorded it
+func scalingReason(event k8s.ScalingEvent) string {
+	if !event.FromHPA() {
+		return fmt.Sprintf("manual scale or rollout (%s): %s", event.Object, event.Message)
+	}
+
+	reason := event.Message
+	if _, after, ok := strings.Cut(event.Message, "reason: "); ok {
+		reason = after
+	}
+	// KEDA names the HPA it manages keda-hpa-<ScaledObject>
+	if scaledObject, ok := strings.CutPrefix(event.Object, "hpa/keda-hpa-"); ok {
+		return fmt.Sprintf("KEDA scaledobject/%s: %s", scaledObject, reason)
+	}
+	return fmt.Sprintf("HPA %s: %s", strings.TrimPrefix(event.Object, "hpa/"), reason)
+}
+
+// ScalingChanges returns the events where the replica count changed, with the count before each
+func ScalingChanges(events []ScalingEvent) (changes []ScalingEvent, previous []int) {
+	for i := 1; i < len(events); i++ {
+		if events[i].Replicas != events[i-1].Replicas {
+			changes = append(changes, events[i])
+			previous = append(previous, events[i-1].Replicas)
+		}
+	}
+	return changes, previous
+}
+
+// writeScalingChanges adds the replica count changes and their causes to the analysis prompt
+func writeScalingChanges(prompt *strings.Builder, events []ScalingEvent) {
+	changes, previous := ScalingChanges(events)
+	if len(changes) == 0 {
+		return
+	}
+	if len(changes) > maxScalingChanges {
+		changes, previous = changes[len(changes)-maxScalingChanges:], previous[len(previous)-maxScalingChanges:]
+	}
+
+	prompt.WriteString("\nSCALING CHANGES (replica count changes and their cause from Kubernetes events):\n")
+	for i, change := range changes {
+		cause := change.Reason
+		if cause == ScalingReasonUnknown {
+			cause = "cause unknown (no matching Kubernetes event, they expire after about an hour)"
+		}
+		prompt.WriteString(fmt.Sprintf("- %s: %d -> %d replicas, %s\n", change.Timestamp.UTC().Format(time.RFC3339), previous[i], change.Replicas, cause))
+	}
+	prompt.WriteString("Use the causes to judge whether autoscaling reacts to the right signal, and flag manual scaling that\n")
+	prompt.WriteString("fights an autoscaler or scaling that flaps up and down within minutes.\n")
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// SaveSnapshot writes an analysis result to path as JSON so a later run can compare against it
+func SaveSnapshot(path string, result *AnalysisResult) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write snapshot %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadSnapshot reads an analysis result previously written by SaveSnapshot
+func LoadSnapshot(path string) (*AnalysisResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", path, err)
+	}
+
+	var result AnalysisResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s: %w", path, err)
+	}
+	// Snapshots saved before schema_version existed only recorded the time as "timestamp"
+	if result.GeneratedAt.IsZero() {
+		result.GeneratedAt = result.Timestamp
+	}
+	return &result, nil
+}
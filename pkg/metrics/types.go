@@ -6,12 +6,25 @@ import (
 
 // MetricsData represents collected metrics for a resource
 type MetricsData struct {
-	ResourceName string                 `json:"resource_name"`
-	ResourceType string                 `json:"resource_type"`
-	Namespace    string                 `json:"namespace"`
-	Metrics      map[string]MetricValue `json:"metrics"`
-	Duration     string                 `json:"duration"`
-	Timestamp    time.Time              `json:"timestamp"`
+	ResourceName string                            `json:"resource_name"`
+	ResourceType string                            `json:"resource_type"`
+	Namespace    string                            `json:"namespace"`
+	Metrics      map[string]MetricValue            `json:"metrics"`
+	Duration     string                            `json:"duration"`
+	Timestamp    time.Time                         `json:"timestamp"`
+	Queries      []QueryDiagnostic                 `json:"queries,omitempty"` // Only recorded with SetShowQueries
+	Source       string                            `json:"source,omitempty"`  // SourceMetricsServer for point-in-time data, SourceNone without metrics, empty for Prometheus
+	Start        *time.Time                        `json:"start,omitempty"`   // Only set for a fixed window, see SetTimeRange
+	End          *time.Time                        `json:"end,omitempty"`
+	Containers   map[string]map[string]MetricValue `json:"containers,omitempty"` // Only collected with SetByContainer, keyed by container then metric
+}
+
+// QueryDiagnostic records a fully substituted PromQL query and what it returned
+type QueryDiagnostic struct {
+	Name   string `json:"name"`
+	Query  string `json:"query"`
+	Points int    `json:"points"`
+	Error  string `json:"error,omitempty"`
 }
 
 // MetricValue represents a single metric with its values over time
@@ -23,6 +36,9 @@ type MetricValue struct {
 	Peak    float64            `json:"peak"`
 	Minimum float64            `json:"minimum"`
 	Current float64            `json:"current"`
+	P50     float64            `json:"p50"`
+	P95     float64            `json:"p95"`
+	P99     float64            `json:"p99"`
 	Labels  map[string]string  `json:"labels"`
 }
 
@@ -44,20 +60,30 @@ type AnalysisRequest struct {
 	Namespace      string                  `json:"namespace"`
 }
 
+// SchemaVersion identifies the layout of AnalysisResult in JSON output and snapshots. Bump it on
+// any breaking change (renamed or removed fields, changed types) so consumers can detect it.
+const SchemaVersion = "v1"
+
 // AnalysisResult represents the result of metrics analysis
 type AnalysisResult struct {
-	ResourceName    string                   `json:"resource_name"`
-	ResourceType    string                   `json:"resource_type"`
-	Namespace       string                   `json:"namespace"`
-	Duration        string                   `json:"duration"`
-	Summary         string                   `json:"summary"`
-	Recommendations []Recommendation         `json:"recommendations"`
-	HPAConfig       *HPARecommendation       `json:"hpa_config,omitempty"`
-	KEDAConfig      *KEDARecommendation      `json:"keda_config,omitempty"`
-	CurrentConfig   *ScalingConfig           `json:"current_config,omitempty"`
-	MetricsSummary  map[string]MetricSummary `json:"metrics_summary"`
-	ScalingEvents   []ScalingEvent           `json:"scaling_events"`
-	Timestamp       time.Time                `json:"timestamp"`
+	SchemaVersion    string                              `json:"schema_version"`
+	GeneratedAt      time.Time                           `json:"generated_at"`
+	Timestamp        time.Time                           `json:"timestamp"` // Deprecated: same as GeneratedAt, kept for v1 consumers
+	ResourceName     string                              `json:"resource_name"`
+	ResourceType     string                              `json:"resource_type"`
+	Namespace        string                              `json:"namespace"`
+	Duration         string                              `json:"duration"`
+	Start            *time.Time                          `json:"start,omitempty"`
+	End              *time.Time                          `json:"end,omitempty"`
+	Summary          string                              `json:"summary"`
+	Recommendations  []Recommendation                    `json:"recommendations"`
+	HPAConfig        *HPARecommendation                  `json:"hpa_config,omitempty"`
+	KEDAConfig       *KEDARecommendation                 `json:"keda_config,omitempty"`
+	ResourceConfig   *ResourceRecommendation             `json:"resource_config,omitempty"`
+	CurrentConfig    *ScalingConfig                      `json:"current_config,omitempty"`
+	MetricsSummary   map[string]MetricSummary            `json:"metrics_summary"`
+	ScalingEvents    []ScalingEvent                      `json:"scaling_events"`
+	ContainerMetrics map[string]map[string]MetricSummary `json:"container_metrics,omitempty"` // Per-container CPU and memory with --by-container
 }
 
 // Recommendation represents a scaling recommendation
@@ -95,6 +121,23 @@ type KEDARecommendation struct {
 	Reasoning       string       `json:"reasoning"`
 }
 
+// ResourceRecommendation proposes container requests and limits from observed usage. Quantities
+// use Kubernetes notation (e.g. 250m, 512Mi); current values are empty when not reported.
+type ResourceRecommendation struct {
+	Containers           []string `json:"containers"`
+	CPURequest           string   `json:"cpu_request,omitempty"`
+	CPULimit             string   `json:"cpu_limit,omitempty"`
+	MemoryRequest        string   `json:"memory_request,omitempty"`
+	MemoryLimit          string   `json:"memory_limit,omitempty"`
+	CurrentCPURequest    string   `json:"current_cpu_request,omitempty"`
+	CurrentCPULimit      string   `json:"current_cpu_limit,omitempty"`
+	CurrentMemoryRequest string   `json:"current_memory_request,omitempty"`
+	CurrentMemoryLimit   string   `json:"current_memory_limit,omitempty"`
+	PatchYAML            string   `json:"patch_yaml"` // Empty for a Job or bare pod, which can't be patched
+	Command              string   `json:"command"`
+	Reasoning            string   `json:"reasoning"`
+}
+
 // KEDAScaler represents a KEDA scaler configuration
 type KEDAScaler struct {
 	Type      string            `json:"type"`
@@ -114,14 +157,16 @@ type ScalingPolicy struct {
 
 // ScalingConfig represents current scaling configuration
 type ScalingConfig struct {
-	Type         string                 `json:"type"` // "hpa", "keda", "none"
-	MinReplicas  int32                  `json:"min_replicas"`
-	MaxReplicas  int32                  `json:"max_replicas"`
-	CurrentSize  int32                  `json:"current_size"`
-	TargetCPU    int32                  `json:"target_cpu,omitempty"`
-	TargetMemory int32                  `json:"target_memory,omitempty"`
-	Scalers      []string               `json:"scalers,omitempty"`
-	RawConfig    map[string]interface{} `json:"raw_config,omitempty"`
+	Type            string                 `json:"type"` // "hpa", "keda", "none"
+	MinReplicas     int32                  `json:"min_replicas"`
+	MaxReplicas     int32                  `json:"max_replicas"`
+	CurrentSize     int32                  `json:"current_size"`
+	TargetCPU       int32                  `json:"target_cpu,omitempty"`
+	TargetMemory    int32                  `json:"target_memory,omitempty"`
+	PollingInterval int32                  `json:"polling_interval,omitempty"` // KEDA only, seconds
+	CooldownPeriod  int32                  `json:"cooldown_period,omitempty"`  // KEDA only, seconds
+	Scalers         []string               `json:"scalers,omitempty"`
+	RawConfig       map[string]interface{} `json:"raw_config,omitempty"`
 }
 
 // MetricSummary represents a summary of a specific metric
@@ -132,6 +177,9 @@ type MetricSummary struct {
 	Peak        float64     `json:"peak"`
 	Minimum     float64     `json:"minimum"`
 	Current     float64     `json:"current"`
+	P50         float64     `json:"p50"`
+	P95         float64     `json:"p95"`
+	P99         float64     `json:"p99"`
 	Trend       string      `json:"trend"`       // "increasing", "decreasing", "stable"
 	Utilization string      `json:"utilization"` // "low", "medium", "high", "critical"
 	Values      []float64   `json:"values"`      // Historical values for charts
@@ -142,37 +190,42 @@ type MetricSummary struct {
 type ScalingEvent struct {
 	Timestamp time.Time `json:"timestamp"`
 	Replicas  int       `json:"replicas"`
-	Reason    string    `json:"reason"`
+	Reason    string    `json:"reason"` // What changed the replica count, or ScalingReasonUnknown
 }
 
 // PrometheusQuery represents a Prometheus query configuration
 type PrometheusQuery struct {
-	Name        string `json:"name"`
-	Query       string `json:"query"`
-	Unit        string `json:"unit"`
-	Description string `json:"description"`
+	Name        string `json:"name" yaml:"name"`
+	Query       string `json:"query" yaml:"query"`
+	Unit        string `json:"unit" yaml:"unit"`
+	Description string `json:"description" yaml:"description"`
+	Aggregation string `json:"aggregation,omitempty" yaml:"aggregation,omitempty"` // How multiple returned series are combined: avg (default), sum or max
 }
 
+// workloadClaims selects the PVCs mounted by the workload's pods, one series per claim, to join
+// kubelet volume stats against
+const workloadClaims = `max by (namespace, persistentvolumeclaim) (kube_pod_spec_volumes_persistentvolumeclaims_info{pod=~"POD_REGEX", namespace="NAMESPACE"})`
+
 // Standard Prometheus queries for common metrics
 var (
 	// CPU metrics - improved with better rate and container selector
 	CPUUtilizationQuery = PrometheusQuery{
 		Name:        "cpu_utilization",
-		Query:       `avg(rate(container_cpu_usage_seconds_total{pod=~"RESOURCE_NAME.*", namespace="NAMESPACE", container!="", container!="POD"}[5m])) * 100`,
+		Query:       `avg(rate(container_cpu_usage_seconds_total{pod=~"POD_REGEX", namespace="NAMESPACE", container!="", container!="POD"}[5m])) * 100`,
 		Unit:        "percent",
 		Description: "CPU utilization percentage",
 	}
 
 	CPURequestsQuery = PrometheusQuery{
 		Name:        "cpu_requests",
-		Query:       `avg(kube_pod_container_resource_requests{pod=~"RESOURCE_NAME.*", namespace="NAMESPACE", resource="cpu"})`,
+		Query:       `avg(kube_pod_container_resource_requests{pod=~"POD_REGEX", namespace="NAMESPACE", resource="cpu"})`,
 		Unit:        "cores",
 		Description: "CPU requests",
 	}
 
 	CPULimitsQuery = PrometheusQuery{
 		Name:        "cpu_limits",
-		Query:       `avg(kube_pod_container_resource_limits{pod=~"RESOURCE_NAME.*", namespace="NAMESPACE", resource="cpu"})`,
+		Query:       `avg(kube_pod_container_resource_limits{pod=~"POD_REGEX", namespace="NAMESPACE", resource="cpu"})`,
 		Unit:        "cores",
 		Description: "CPU limits",
 	}
@@ -180,25 +233,78 @@ var (
 	// Memory metrics - improved with better container selector
 	MemoryUtilizationQuery = PrometheusQuery{
 		Name:        "memory_utilization",
-		Query:       `avg(container_memory_usage_bytes{pod=~"RESOURCE_NAME.*", namespace="NAMESPACE", container!="", container!="POD"}) / 1024 / 1024`,
+		Query:       `avg(container_memory_usage_bytes{pod=~"POD_REGEX", namespace="NAMESPACE", container!="", container!="POD"}) / 1024 / 1024`,
 		Unit:        "MB",
 		Description: "Memory utilization in MB",
 	}
 
 	MemoryRequestsQuery = PrometheusQuery{
 		Name:        "memory_requests",
-		Query:       `avg(kube_pod_container_resource_requests{pod=~"RESOURCE_NAME.*", namespace="NAMESPACE", resource="memory"}) / 1024 / 1024`,
+		Query:       `avg(kube_pod_container_resource_requests{pod=~"POD_REGEX", namespace="NAMESPACE", resource="memory"}) / 1024 / 1024`,
 		Unit:        "MB",
 		Description: "Memory requests in MB",
 	}
 
 	MemoryLimitsQuery = PrometheusQuery{
 		Name:        "memory_limits",
-		Query:       `avg(kube_pod_container_resource_limits{pod=~"RESOURCE_NAME.*", namespace="NAMESPACE", resource="memory"}) / 1024 / 1024`,
+		Query:       `avg(kube_pod_container_resource_limits{pod=~"POD_REGEX", namespace="NAMESPACE", resource="memory"}) / 1024 / 1024`,
 		Unit:        "MB",
 		Description: "Memory limits in MB",
 	}
 
+	// Network metrics
+	NetworkReceiveQuery = PrometheusQuery{
+		Name:        "network_receive",
+		Query:       `sum(rate(container_network_receive_bytes_total{pod=~"POD_REGEX", namespace="NAMESPACE"}[5m])) / 1024 / 1024`,
+		Unit:        "MB/s",
+		Description: "Network receive throughput in MB/s",
+	}
+
+	NetworkTransmitQuery = PrometheusQuery{
+		Name:        "network_transmit",
+		Query:       `sum(rate(container_network_transmit_bytes_total{pod=~"POD_REGEX", namespace="NAMESPACE"}[5m])) / 1024 / 1024`,
+		Unit:        "MB/s",
+		Description: "Network transmit throughput in MB/s",
+	}
+
+	// Disk metrics
+	FilesystemUsageQuery = PrometheusQuery{
+		Name:        "filesystem_usage",
+		Query:       `sum(container_fs_usage_bytes{pod=~"POD_REGEX", namespace="NAMESPACE", container!="", container!="POD"}) / 1024 / 1024`,
+		Unit:        "MB",
+		Description: "Container filesystem usage in MB",
+	}
+
+	DiskReadQuery = PrometheusQuery{
+		Name:        "disk_read",
+		Query:       `sum(rate(container_fs_reads_bytes_total{pod=~"POD_REGEX", namespace="NAMESPACE", container!="", container!="POD"}[5m])) / 1024 / 1024`,
+		Unit:        "MB/s",
+		Description: "Disk read throughput in MB/s",
+	}
+
+	DiskWriteQuery = PrometheusQuery{
+		Name:        "disk_write",
+		Query:       `sum(rate(container_fs_writes_bytes_total{pod=~"POD_REGEX", namespace="NAMESPACE", container!="", container!="POD"}[5m])) / 1024 / 1024`,
+		Unit:        "MB/s",
+		Description: "Disk write throughput in MB/s",
+	}
+
+	// PVC metrics from the kubelet, limited to the claims the workload's pods mount according to
+	// kube-state-metrics, so similarly named claims of other workloads aren't counted
+	PVCUsageQuery = PrometheusQuery{
+		Name:        "pvc_usage",
+		Query:       `sum(kubelet_volume_stats_used_bytes{namespace="NAMESPACE"} * on(namespace, persistentvolumeclaim) group_left() ` + workloadClaims + `) / 1024 / 1024`,
+		Unit:        "MB",
+		Description: "Persistent volume usage in MB",
+	}
+
+	PVCCapacityQuery = PrometheusQuery{
+		Name:        "pvc_capacity",
+		Query:       `sum(kubelet_volume_stats_capacity_bytes{namespace="NAMESPACE"} * on(namespace, persistentvolumeclaim) group_left() ` + workloadClaims + `) / 1024 / 1024`,
+		Unit:        "MB",
+		Description: "Persistent volume capacity in MB",
+	}
+
 	// Pod metrics
 	PodReplicasQuery = PrometheusQuery{
 		Name:        "pod_replicas",
@@ -215,6 +321,149 @@ var (
 	}
 )
 
+// StatefulSet replica metrics, swapped in for the deployment ones when analyzing a StatefulSet
+var (
+	StatefulSetReplicasQuery = PrometheusQuery{
+		Name:        "pod_replicas",
+		Query:       `kube_statefulset_status_replicas{statefulset="RESOURCE_NAME", namespace="NAMESPACE"}`,
+		Unit:        "count",
+		Description: "Number of pod replicas",
+	}
+
+	StatefulSetAvailableQuery = PrometheusQuery{
+		Name:        "pod_available",
+		Query:       `kube_statefulset_status_replicas_ready{statefulset="RESOURCE_NAME", namespace="NAMESPACE"}`,
+		Unit:        "count",
+		Description: "Number of ready pod replicas",
+	}
+)
+
+// DaemonSet pod metrics. DaemonSets scale per node, so desired/ready/available pods replace replicas.
+var (
+	DaemonSetDesiredQuery = PrometheusQuery{
+		Name:        "pod_replicas",
+		Query:       `kube_daemonset_status_desired_number_scheduled{daemonset="RESOURCE_NAME", namespace="NAMESPACE"}`,
+		Unit:        "count",
+		Description: "Number of nodes that should run the daemon pod",
+	}
+
+	DaemonSetAvailableQuery = PrometheusQuery{
+		Name:        "pod_available",
+		Query:       `kube_daemonset_status_number_available{daemonset="RESOURCE_NAME", namespace="NAMESPACE"}`,
+		Unit:        "count",
+		Description: "Number of nodes running an available daemon pod",
+	}
+
+	DaemonSetReadyQuery = PrometheusQuery{
+		Name:        "pod_ready",
+		Query:       `kube_daemonset_status_number_ready{daemonset="RESOURCE_NAME", namespace="NAMESPACE"}`,
+		Unit:        "count",
+		Description: "Number of nodes running a ready daemon pod",
+	}
+)
+
+// Job and CronJob metrics. Batch pods run to completion, so active, succeeded and failed pods
+// replace replicas. CronJob counts are summed over the Jobs it owns.
+var (
+	JobActiveQuery = PrometheusQuery{
+		Name:        "pod_replicas",
+		Query:       `kube_job_status_active{job_name="RESOURCE_NAME", namespace="NAMESPACE"}`,
+		Unit:        "count",
+		Description: "Number of running job pods",
+	}
+
+	JobSucceededQuery = PrometheusQuery{
+		Name:        "job_succeeded",
+		Query:       `kube_job_status_succeeded{job_name="RESOURCE_NAME", namespace="NAMESPACE"}`,
+		Unit:        "count",
+		Description: "Number of job pods that completed successfully",
+	}
+
+	JobFailedQuery = PrometheusQuery{
+		Name:        "job_failed",
+		Query:       `kube_job_status_failed{job_name="RESOURCE_NAME", namespace="NAMESPACE"}`,
+		Unit:        "count",
+		Description: "Number of job pods that failed",
+	}
+
+	CronJobActiveQuery = PrometheusQuery{
+		Name:        "pod_replicas",
+		Query:       `kube_cronjob_status_active{cronjob="RESOURCE_NAME", namespace="NAMESPACE"}`,
+		Unit:        "count",
+		Description: "Number of running jobs",
+	}
+
+	CronJobSucceededQuery = PrometheusQuery{
+		Name:        "job_succeeded",
+		Query:       `sum(kube_job_status_succeeded{namespace="NAMESPACE"} * on(job_name, namespace) group_left() kube_job_owner{owner_kind="CronJob", owner_name="RESOURCE_NAME", namespace="NAMESPACE"})`,
+		Unit:        "count",
+		Description: "Number of job pods that completed successfully across the cronjob's jobs",
+	}
+
+	CronJobFailedQuery = PrometheusQuery{
+		Name:        "job_failed",
+		Query:       `sum(kube_job_status_failed{namespace="NAMESPACE"} * on(job_name, namespace) group_left() kube_job_owner{owner_kind="CronJob", owner_name="RESOURCE_NAME", namespace="NAMESPACE"})`,
+		Unit:        "count",
+		Description: "Number of job pods that failed across the cronjob's jobs",
+	}
+)
+
+// IsSupportedResourceType reports whether metrics can be collected for the resource kind
+func IsSupportedResourceType(resourceType string) bool {
+	switch resourceType {
+	case "Deployment", "StatefulSet", "DaemonSet", "Job", "CronJob", "Pod":
+		return true
+	default:
+		return false
+	}
+}
+
+// SupportsAutoscaling reports whether HPA/KEDA can scale the resource kind
+func SupportsAutoscaling(resourceType string) bool {
+	switch resourceType {
+	case "DaemonSet", "Job", "CronJob", "Pod":
+		return false
+	default:
+		return true
+	}
+}
+
+// queriesForResourceType adapts the deployment replica queries to the given resource kind
+func queriesForResourceType(queries []PrometheusQuery, resourceType string) []PrometheusQuery {
+	var replicas, available []PrometheusQuery
+	switch resourceType {
+	case "StatefulSet":
+		replicas = []PrometheusQuery{StatefulSetReplicasQuery}
+		available = []PrometheusQuery{StatefulSetAvailableQuery}
+	case "DaemonSet":
+		replicas = []PrometheusQuery{DaemonSetDesiredQuery}
+		available = []PrometheusQuery{DaemonSetReadyQuery, DaemonSetAvailableQuery}
+	case "Job":
+		replicas = []PrometheusQuery{JobActiveQuery}
+		available = []PrometheusQuery{JobSucceededQuery, JobFailedQuery}
+	case "CronJob":
+		replicas = []PrometheusQuery{CronJobActiveQuery}
+		available = []PrometheusQuery{CronJobSucceededQuery, CronJobFailedQuery}
+	case "Pod":
+		// A bare pod has no replicas, so both queries are dropped
+	default:
+		return queries
+	}
+
+	adapted := make([]PrometheusQuery, 0, len(queries)+1)
+	for _, query := range queries {
+		switch query.Query {
+		case PodReplicasQuery.Query:
+			adapted = append(adapted, replicas...)
+		case PodAvailableQuery.Query:
+			adapted = append(adapted, available...)
+		default:
+			adapted = append(adapted, query)
+		}
+	}
+	return adapted
+}
+
 // GetStandardQueries returns the standard set of Prometheus queries
 func GetStandardQueries() []PrometheusQuery {
 	return []PrometheusQuery{
@@ -224,7 +473,29 @@ func GetStandardQueries() []PrometheusQuery {
 		MemoryUtilizationQuery,
 		MemoryRequestsQuery,
 		MemoryLimitsQuery,
+		NetworkReceiveQuery,
+		NetworkTransmitQuery,
+		FilesystemUsageQuery,
+		DiskReadQuery,
+		DiskWriteQuery,
+		PVCUsageQuery,
+		PVCCapacityQuery,
 		PodReplicasQuery,
 		PodAvailableQuery,
 	}
 }
+
+// IsStandardQuery reports whether the metric name belongs to the standard query set
+func IsStandardQuery(name string) bool {
+	for _, query := range GetStandardQueries() {
+		if query.Name == name {
+			return true
+		}
+	}
+	switch name {
+	case DaemonSetReadyQuery.Name, JobSucceededQuery.Name, JobFailedQuery.Name:
+		return true
+	default:
+		return false
+	}
+}
@@ -1,12 +1,21 @@
 package model
 
+import "time"
+
+// SchemaVersion identifies the layout of Analysis in machine-readable output. Bump it on any
+// breaking change (renamed or removed fields, changed types) so consumers can detect it.
+const SchemaVersion = "v1"
+
 type Analysis struct {
+    SchemaVersion string     `json:"schema_version"`
+    GeneratedAt  time.Time  `json:"generated_at"`
     Problem      string     `json:"problem"`
     RootCause    string     `json:"root_cause"`
     Severity     string     `json:"severity"`
     Issues       []Issue    `json:"issues"`
     Suggestions  []Suggestion `json:"suggestions"`
     QuickFix     string     `json:"quick_fix,omitempty"`
+    Commands     []RemediationCommand `json:"commands,omitempty"`
     FullAnalysis string     `json:"full_analysis"`
 }
 
@@ -23,3 +32,11 @@ type Suggestion struct {
     Command     string `json:"command,omitempty"`
     Explanation string `json:"explanation"`
 }
+
+// RemediationCommand is a single kubectl command that fixes part of the problem, kept apart
+// from the free-text suggestions so it can be reviewed, copied or applied as is
+type RemediationCommand struct {
+    Description string `json:"description"`
+    Command     string `json:"command"`
+    Destructive bool   `json:"destructive"` // Anything but scale, annotate or label, or one that forces, prunes, selects many resources, removes a key or scales to zero
+}
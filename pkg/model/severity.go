@@ -0,0 +1,40 @@
+package model
+
+import "strings"
+
+// severityLevels orders the severities the LLM is asked to report, lowest first
+var severityLevels = map[string]int{
+    "low":      1,
+    "medium":   2,
+    "high":     3,
+    "critical": 4,
+}
+
+// SeverityLevel maps a severity to its position on the ordered scale. Unknown
+// severities are reported as 0 and false.
+func SeverityLevel(severity string) (int, bool) {
+    level, ok := severityLevels[strings.ToLower(strings.TrimSpace(severity))]
+    return level, ok
+}
+
+// MaxSeverity returns the highest severity across the overall analysis and its issues
+func (a *Analysis) MaxSeverity() string {
+    highest := a.Severity
+    highestLevel, _ := SeverityLevel(a.Severity)
+    for _, issue := range a.Issues {
+        if level, _ := SeverityLevel(issue.Severity); level > highestLevel {
+            highest, highestLevel = issue.Severity, level
+        }
+    }
+    return highest
+}
+
+// MeetsSeverity reports whether the analysis or any of its issues is at or above threshold
+func (a *Analysis) MeetsSeverity(threshold string) bool {
+    thresholdLevel, ok := SeverityLevel(threshold)
+    if !ok {
+        return false
+    }
+    level, _ := SeverityLevel(a.MaxSeverity())
+    return level >= thresholdLevel
+}
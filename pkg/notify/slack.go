@@ -0,0 +1,154 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/helmcode/kubectl-ai/pkg/model"
+)
+
+const (
+	// maxSlackSuggestions is how many of the highest priority suggestions are posted
+	maxSlackSuggestions = 3
+	// maxSectionText stays below Slack's 3000 character limit for a section's text
+	maxSectionText = 2900
+	// maxHeaderText is Slack's limit for a header block
+	maxHeaderText = 150
+)
+
+// slackBlock is a Block Kit block. Only the fields used by header, section, divider and
+// context blocks are modelled.
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Fields   []slackText `json:"fields,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+// slackText is a plain_text or mrkdwn text object
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// slackMessage is the payload of an incoming webhook. Text is the fallback shown in notifications.
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+// PostSlack posts a summary of the analysis to a Slack incoming webhook: the problem, severity,
+// root cause, quick fix and the highest priority suggestions. The full analysis is left out to
+// keep the message readable.
+func PostSlack(ctx context.Context, webhookURL string, analysis *model.Analysis) error {
+	body, err := json.Marshal(slackSummary(analysis))
+	if err != nil {
+		return fmt.Errorf("marshal Slack message: %w", err)
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	return post(ctx, "Slack webhook", webhookURL, header, body)
+}
+
+// slackSummary builds the Block Kit message for an analysis
+func slackSummary(analysis *model.Analysis) slackMessage {
+	severity := analysis.MaxSeverity()
+	if severity == "" {
+		severity = "unknown"
+	}
+	title := fmt.Sprintf("%s kubectl-ai: %s", severityIcon(severity), analysis.Problem)
+
+	blocks := []slackBlock{
+		{Type: "header", Text: &slackText{Type: "plain_text", Text: truncate(title, maxHeaderText)}},
+		{Type: "section", Fields: []slackText{
+			{Type: "mrkdwn", Text: "*Severity*\n" + strings.ToUpper(severity)},
+			{Type: "mrkdwn", Text: fmt.Sprintf("*Issues found*\n%d", len(analysis.Issues))},
+		}},
+	}
+
+	if analysis.RootCause != "" {
+		blocks = append(blocks, section("*Root cause*\n"+analysis.RootCause))
+	}
+	if analysis.QuickFix != "" {
+		blocks = append(blocks, section("*Quick fix*\n```"+analysis.QuickFix+"```"))
+	}
+
+	if suggestions := topSuggestions(analysis.Suggestions, maxSlackSuggestions); len(suggestions) > 0 {
+		var text strings.Builder
+		text.WriteString("*Top suggestions*")
+		for i, suggestion := range suggestions {
+			fmt.Fprintf(&text, "\n%d. %s", i+1, suggestion.Action)
+			if suggestion.Priority != "" {
+				fmt.Fprintf(&text, " _(%s)_", strings.ToLower(suggestion.Priority))
+			}
+			if suggestion.Command != "" {
+				fmt.Fprintf(&text, "\n`%s`", suggestion.Command)
+			}
+		}
+		blocks = append(blocks, slackBlock{Type: "divider"}, section(text.String()))
+	}
+
+	if remaining := len(analysis.Suggestions) - maxSlackSuggestions; remaining > 0 {
+		more := fmt.Sprintf("%d more suggestions in the full analysis", remaining)
+		if remaining == 1 {
+			more = "1 more suggestion in the full analysis"
+		}
+		blocks = append(blocks, slackBlock{Type: "context", Elements: []slackText{{Type: "mrkdwn", Text: more}}})
+	}
+
+	return slackMessage{
+		Text:   truncate(fmt.Sprintf("[%s] %s: %s", strings.ToUpper(severity), analysis.Problem, analysis.RootCause), maxSectionText),
+		Blocks: blocks,
+	}
+}
+
+// topSuggestions returns up to n suggestions, highest priority first. Suggestions of the same
+// priority keep the order the model gave them in.
+func topSuggestions(suggestions []model.Suggestion, n int) []model.Suggestion {
+	sorted := make([]model.Suggestion, len(suggestions))
+	copy(sorted, suggestions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, _ := model.SeverityLevel(sorted[i].Priority)
+		b, _ := model.SeverityLevel(sorted[j].Priority)
+		return a > b
+	})
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// section is a mrkdwn section block, truncated to fit Slack's limit
+func section(text string) slackBlock {
+	return slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: truncate(text, maxSectionText)}}
+}
+
+// truncate shortens text to at most max characters, marking the cut with an ellipsis
+func truncate(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max-1]) + "…"
+}
+
+// severityIcon matches the icons the terminal output uses for each severity
+func severityIcon(severity string) string {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return "🔴"
+	case "high":
+		return "🟠"
+	case "medium":
+		return "🟡"
+	case "low":
+		return "🟢"
+	default:
+		return "⚪"
+	}
+}
@@ -0,0 +1,74 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// postTimeout bounds a webhook post, which should never hold up the command for long
+const postTimeout = 15 * time.Second
+
+// ParseHeaders parses --webhook-header values of the form "Name: value"
+func ParseHeaders(values []string) (http.Header, error) {
+	header := http.Header{}
+	for _, value := range values {
+		name, content, ok := strings.Cut(value, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid header %q: expected \"Name: value\"", value)
+		}
+		header.Add(name, strings.TrimSpace(content))
+	}
+	return header, nil
+}
+
+// PostJSON posts payload as JSON to a webhook, with the given extra headers (e.g. Authorization).
+// The payload is encoded exactly like -o json output, so receivers can share one schema.
+func PostJSON(ctx context.Context, webhookURL string, header http.Header, payload interface{}) error {
+	body, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal webhook payload: %w", err)
+	}
+
+	withContentType := header.Clone()
+	if withContentType == nil {
+		withContentType = http.Header{}
+	}
+	if withContentType.Get("Content-Type") == "" {
+		withContentType.Set("Content-Type", "application/json")
+	}
+	return post(ctx, "webhook", webhookURL, withContentType, body)
+}
+
+// post sends body to url and treats any non-2xx response as an error, quoting the start of the
+// response body since receivers usually explain rejections there
+func post(ctx context.Context, name, url string, header http.Header, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, postTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid %s URL: %w", name, err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("post to %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		reason, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s returned %s: %s", name, resp.Status, strings.TrimSpace(string(reason)))
+	}
+	return nil
+}
@@ -2,20 +2,20 @@ package parser
 
 import (
     "encoding/json"
+    "fmt"
     "regexp"
+    "strconv"
     "strings"
+    "time"
 )
 
 import "github.com/helmcode/kubectl-ai/pkg/model"
 
 func ParseDebugResponse(raw string, problem string) (*model.Analysis, error) {
-    // Remove markdown code fences if present
-    cleaned := stripFences(raw)
-
-    var analysis model.Analysis
-    if err := json.Unmarshal([]byte(cleaned), &analysis); err != nil {
+    analysis, err := ParseDebugJSON(raw, problem)
+    if err != nil {
         // Fallback – could not parse JSON, embed entire text.
-        analysis = model.Analysis{
+        analysis = &model.Analysis{
             Problem:   problem,
             RootCause: "Analysis completed (see full analysis for details)",
             Severity:  "medium",
@@ -31,11 +31,227 @@ func ParseDebugResponse(raw string, problem string) (*model.Analysis, error) {
                 Explanation: raw,
             }},
         }
+        stamp(analysis)
+    }
+    return analysis, nil
+}
+
+// ParseDebugJSON parses a response that must be the JSON analysis the prompts ask for. Unlike
+// ParseDebugResponse it fails instead of falling back to the raw text, so callers can re-ask.
+func ParseDebugJSON(raw string, problem string) (*model.Analysis, error) {
+    var analysis model.Analysis
+    if err := json.Unmarshal([]byte(stripFences(raw)), &analysis); err != nil {
+        return nil, fmt.Errorf("response is not valid JSON: %w", err)
+    }
+    if analysis.RootCause == "" && analysis.FullAnalysis == "" && len(analysis.Issues) == 0 {
+        return nil, fmt.Errorf("response JSON doesn't match the analysis schema")
     }
     if analysis.Problem == "" {
         analysis.Problem = problem
     }
-        return &analysis, nil
+    analysis.Commands = ValidCommands(analysis.Commands)
+    stamp(&analysis)
+    return &analysis, nil
+}
+
+// shellOperators would make a command run more than one program, or something other than kubectl
+var shellOperators = regexp.MustCompile("[;&|<>`\n]|\\$\\(")
+
+// allowedVerbs are the built-in kubectl subcommands offered as fixes. Every other verb is dropped:
+// exec, run, cp, debug, edit, attach, port-forward and proxy run code or open sessions, config
+// rewrites the kubeconfig, and an unknown verb may be a plugin, which runs any kubectl-<verb>
+// binary on the PATH.
+var allowedVerbs = map[string]bool{
+    "annotate":  true,
+    "apply":     true,
+    "autoscale": true,
+    "cordon":    true,
+    "create":    true,
+    "delete":    true,
+    "describe":  true,
+    "drain":     true,
+    "expose":    true,
+    "get":       true,
+    "label":     true,
+    "logs":      true,
+    "patch":     true,
+    "replace":   true,
+    "rollout":   true,
+    "scale":     true,
+    "set":       true,
+    "taint":     true,
+    "top":       true,
+    "uncordon":  true,
+}
+
+// safeVerbs change a single field in place without deleting anything. Every other verb counts as
+// destructive, including apply, patch and set: the model's output is shaped by cluster data, and
+// those can swap an image, rewrite the pod template or replace the whole object.
+var safeVerbs = map[string]bool{
+    "scale":    true,
+    "annotate": true,
+    "label":    true,
+}
+
+// destructiveFlags force deletions, prune, or widen a command from one resource to many, even with a safe verb
+var destructiveFlags = map[string]bool{
+    "--force":          true,
+    "--prune":          true,
+    "--all":            true,
+    "--all-namespaces": true,
+    "-A":               true,
+    "-l":               true,
+    "--selector":       true,
+    "--overwrite":      true,
+}
+
+// zeroFlags are destructive when set to zero: no graceful termination, or no replicas left
+var zeroFlags = map[string]bool{
+    "--grace-period": true,
+    "--replicas":     true,
+}
+
+// ValidCommands keeps the remediation commands that are a single kubectl invocation starting with
+// its subcommand, so they can be copied or applied safely, and drops the ones whose verb isn't
+// allowed or that read manifests from a URL or a kustomization. Commands are destructive unless
+// Destructive says otherwise, whatever the model claimed.
+func ValidCommands(commands []model.RemediationCommand) []model.RemediationCommand {
+    var valid []model.RemediationCommand
+    for _, command := range commands {
+        command.Command = strings.TrimSpace(command.Command)
+        command.Description = strings.TrimSpace(command.Description)
+        if shellOperators.MatchString(command.Command) {
+            continue
+        }
+        args, err := SplitCommand(command.Command)
+        if err != nil || len(args) < 2 || !allowedVerbs[args[1]] || RemoteManifest(args[1:]) != "" {
+            continue
+        }
+        if Destructive(args[1:]) {
+            command.Destructive = true
+        }
+        valid = append(valid, command)
+    }
+    return valid
+}
+
+// Destructive reports whether kubectl arguments, starting with the subcommand, may delete or
+// replace resources or act on more than the named ones. Only scale, annotate and label are safe,
+// and only without a selector, --all, --overwrite, a key removal (app-) or zero replicas.
+func Destructive(args []string) bool {
+    if len(args) == 0 || !safeVerbs[args[0]] {
+        return true
+    }
+    for i := 1; i < len(args); i++ {
+        arg := args[i]
+        if !strings.HasPrefix(arg, "-") {
+            // label and annotate remove a key given as key-
+            if strings.HasSuffix(arg, "-") && !strings.Contains(arg, "=") {
+                return true
+            }
+            continue
+        }
+
+        name, value, hasValue := strings.Cut(arg, "=")
+        if strings.HasPrefix(arg, "-l") && arg != "-l" && !strings.HasPrefix(arg, "--") {
+            name = "-l" // Attached selector, e.g. -lapp=api
+        }
+        if destructiveFlags[name] {
+            // Boolean flags can be turned off explicitly, e.g. --overwrite=false
+            if !hasValue || name == "-l" || name == "--selector" || value != "false" {
+                return true
+            }
+            continue
+        }
+        if zeroFlags[name] {
+            if !hasValue {
+                if i+1 >= len(args) {
+                    return true
+                }
+                i++
+                value = args[i]
+            }
+            if n, err := strconv.Atoi(strings.TrimSpace(value)); err != nil || n <= 0 {
+                return true
+            }
+        }
+    }
+    return false
+}
+
+// RemoteManifest returns the flag of a command that reads what to change from a URL or a
+// kustomization, which can't be reviewed from the command line, or "" when there is none
+func RemoteManifest(args []string) string {
+    for i, arg := range args {
+        value := ""
+        switch {
+        case arg == "-k" || strings.HasPrefix(arg, "-k") && !strings.HasPrefix(arg, "--"), arg == "--kustomize" || strings.HasPrefix(arg, "--kustomize="):
+            return arg
+        case (arg == "-f" || arg == "--filename") && i+1 < len(args):
+            value = args[i+1]
+        case strings.HasPrefix(arg, "--filename="):
+            value = strings.TrimPrefix(arg, "--filename=")
+        case strings.HasPrefix(arg, "-f") && !strings.HasPrefix(arg, "--"):
+            value = strings.TrimPrefix(strings.TrimPrefix(arg, "-f"), "=")
+        }
+        if strings.Contains(value, "://") {
+            return value
+        }
+    }
+    return ""
+}
+
+// SplitCommand splits a command line into arguments like a shell would for the quoting the
+// model uses, e.g. kubectl patch ... -p '{"spec": ...}'
+func SplitCommand(command string) ([]string, error) {
+    var args []string
+    var current strings.Builder
+    inArg := false
+    var quote rune
+    escaped := false
+
+    for _, r := range command {
+        switch {
+        case escaped:
+            current.WriteRune(r)
+            escaped = false
+        case r == '\\' && quote != '\'':
+            escaped, inArg = true, true
+        case quote != 0:
+            if r == quote {
+                quote = 0
+            } else {
+                current.WriteRune(r)
+            }
+        case r == '\'' || r == '"':
+            quote, inArg = r, true
+        case r == ' ' || r == '\t':
+            if inArg {
+                args = append(args, current.String())
+                current.Reset()
+                inArg = false
+            }
+        default:
+            current.WriteRune(r)
+            inArg = true
+        }
+    }
+    if quote != 0 || escaped {
+        return nil, fmt.Errorf("unterminated quote in %q", command)
+    }
+    if inArg {
+        args = append(args, current.String())
+    }
+    if len(args) == 0 || args[0] != "kubectl" {
+        return nil, fmt.Errorf("not a kubectl command: %q", command)
+    }
+    return args, nil
+}
+
+// stamp records the schema version and time of an analysis
+func stamp(analysis *model.Analysis) {
+    analysis.SchemaVersion = model.SchemaVersion
+    analysis.GeneratedAt = time.Now().UTC()
 }
 
 // stripFences removes markdown code fences such as ```json ... ``` so JSON can be parsed
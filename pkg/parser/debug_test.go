@@ -0,0 +1,162 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/helmcode/kubectl-ai/pkg/model"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+		wantErr bool
+	}{
+		{"kubectl scale deploy/api --replicas=3", []string{"kubectl", "scale", "deploy/api", "--replicas=3"}, false},
+		{"kubectl  scale\tdeploy/api", []string{"kubectl", "scale", "deploy/api"}, false},
+		{`kubectl patch deploy api -p '{"spec": {"replicas": 2}}'`, []string{"kubectl", "patch", "deploy", "api", "-p", `{"spec": {"replicas": 2}}`}, false},
+		{`kubectl annotate pod api note="a b"`, []string{"kubectl", "annotate", "pod", "api", "note=a b"}, false},
+		{`kubectl label pod api note=a\ b`, []string{"kubectl", "label", "pod", "api", "note=a b"}, false},
+		{`kubectl label pod api note="say \"hi\""`, []string{"kubectl", "label", "pod", "api", `note=say "hi"`}, false},
+		{`kubectl label pod api note='a\b'`, []string{"kubectl", "label", "pod", "api", `note=a\b`}, false},
+		{`kubectl label pod api ''`, []string{"kubectl", "label", "pod", "api", ""}, false},
+		{`kubectl label pod api note='unterminated`, nil, true},
+		{`kubectl label pod api note="unterminated`, nil, true},
+		{`kubectl label pod api note\`, nil, true},
+		{"helm upgrade api", nil, true},
+		{"", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			got, err := SplitCommand(tt.command)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error: %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoteManifest(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"apply", "-f", "fix.yaml"}, ""},
+		{[]string{"apply", "--filename=fix.yaml"}, ""},
+		{[]string{"apply", "-f", "https://example.com/fix.yaml"}, "https://example.com/fix.yaml"},
+		{[]string{"apply", "-f=https://example.com/fix.yaml"}, "https://example.com/fix.yaml"},
+		{[]string{"apply", "-fhttps://example.com/fix.yaml"}, "https://example.com/fix.yaml"},
+		{[]string{"apply", "--filename", "http://example.com/fix.yaml"}, "http://example.com/fix.yaml"},
+		{[]string{"apply", "--filename=https://example.com/fix.yaml"}, "https://example.com/fix.yaml"},
+		{[]string{"apply", "-k", "overlays/prod"}, "-k"},
+		{[]string{"apply", "-koverlays/prod"}, "-koverlays/prod"},
+		{[]string{"apply", "--kustomize=overlays/prod"}, "--kustomize=overlays/prod"},
+		{[]string{"scale", "deploy/api", "--force"}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := RemoteManifest(tt.args); got != tt.want {
+			t.Errorf("RemoteManifest(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestDestructive(t *testing.T) {
+	tests := []struct {
+		command string
+		want    bool
+	}{
+		{"kubectl scale deploy/api --replicas=3", false},
+		{"kubectl scale deploy/api --replicas 3", false},
+		{"kubectl annotate deploy api note=restarted", false},
+		{"kubectl label pod api tier=web", false},
+		{"kubectl label pod api tier=web --overwrite=false", false},
+		{"kubectl apply -f fix.yaml", true},
+		{"kubectl patch deploy api -p '{}'", true},
+		{"kubectl set image deploy/api app=api:2", true},
+		{"kubectl delete pod api", true},
+		{"kubectl scale deploy/api --replicas=0", true},
+		{"kubectl scale deploy/api --replicas=00", true},
+		{"kubectl scale deploy/api --replicas 0", true},
+		{"kubectl scale deploy/api '--replicas' '0'", true},
+		{"kubectl scale deploy/api --replicas\t0", true},
+		{"kubectl scale deploy/api --replicas=-1", true},
+		{"kubectl scale deploy/api --replicas=many", true},
+		{"kubectl scale deploy/api --replicas", true},
+		{"kubectl scale deploy/api --replicas=2 --grace-period=0", true},
+		{"kubectl scale deploy/api --replicas=2 --force", true},
+		{"kubectl scale deploy --all --replicas=2", true},
+		{"kubectl label pods --all app-", true},
+		{"kubectl label pods --all=true tier=web", true},
+		{"kubectl label pods -A tier=web", true},
+		{"kubectl label pods --all-namespaces tier=web", true},
+		{"kubectl label pods -l app=api tier=web", true},
+		{"kubectl label pods -lapp=api tier=web", true},
+		{"kubectl label pods --selector=app=api tier=web", true},
+		{"kubectl label pods --selector app=api tier=web", true},
+		{"kubectl label pod api tier=web --overwrite", true},
+		{"kubectl label pod api app-", true},
+		{"kubectl annotate pod api note-", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			args, err := SplitCommand(tt.command)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := Destructive(args[1:]); got != tt.want {
+				t.Errorf("Destructive = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidCommands(t *testing.T) {
+	tests := []struct {
+		command         string
+		wantKept        bool
+		wantDestructive bool
+	}{
+		{"kubectl scale deploy/api --replicas=3", true, false},
+		{"kubectl label pod api tier=web", true, false},
+		{"kubectl rollout restart deploy/api", true, true},
+		{"kubectl delete pod api-0", true, true},
+		{"kubectl apply -f fix.yaml", true, true},
+		{"kubectl scale deploy/api --replicas=0", true, true},
+		{"kubectl exec -it api -- sh", false, false},
+		{"kubectl run shell --image=busybox", false, false},
+		{"kubectl cp api:/etc/passwd .", false, false},
+		{"kubectl port-forward svc/api 8080", false, false},
+		{"kubectl config use-context prod", false, false},
+		{"kubectl plugin list", false, false},
+		{"kubectl krew install foo", false, false},
+		{"kubectl alpha events", false, false},
+		{"kubectl foo --bar", false, false},
+		{"kubectl --namespace prod scale deploy/api --replicas=3", false, false},
+		{"kubectl apply -f https://example.com/fix.yaml", false, false},
+		{"kubectl apply -k overlays/prod", false, false},
+		{"kubectl scale deploy/api --replicas=3; rm -rf /", false, false},
+		{"kubectl scale deploy/api --replicas=$(cat n)", false, false},
+		{"kubectl label pod api note='open", false, false},
+		{"helm rollback api", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			// The model's own flag never makes a command safe
+			valid := ValidCommands([]model.RemediationCommand{{Description: "fix", Command: "  " + tt.command + " "}})
+			if kept := len(valid) == 1; kept != tt.wantKept {
+				t.Fatalf("kept = %v, want %v", kept, tt.wantKept)
+			}
+			if tt.wantKept && valid[0].Destructive != tt.wantDestructive {
+				t.Errorf("destructive = %v, want %v", valid[0].Destructive, tt.wantDestructive)
+			}
+		})
+	}
+}
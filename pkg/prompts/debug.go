@@ -3,26 +3,60 @@ package prompts
 import (
     "encoding/json"
     "fmt"
+    "strings"
+
+    "github.com/helmcode/kubectl-ai/pkg/k8s"
 )
 
 func BuildDebugPrompt(problem string, resources map[string]interface{}) (string, error) {
+    return buildDebugPrompt(problem, resources, nil)
+}
+
+// buildDebugPrompt renders the debug prompt, noting any resources omitted to fit the context window
+func buildDebugPrompt(problem string, resources map[string]interface{}, omitted []string) (string, error) {
+    // Crashed containers, quota warnings and manifest files get their own sections below instead of being listed as resources
+    notes := manifestNote(resources) + restartNote(resources) + terminationNote(resources) + quotaNote(resources)
+    _, hasQuotaNote := resources[k8s.QuotaHeadroomKey]
+    _, hasTerminations := resources[k8s.TerminationsKey]
+    _, hasRestarts := resources[k8s.RestartsKey]
+    _, hasManifests := resources[k8s.ManifestsKey]
+    if hasQuotaNote || hasTerminations || hasRestarts || hasManifests {
+        withoutNotes := make(map[string]interface{}, len(resources))
+        for key, value := range resources {
+            if key != k8s.QuotaHeadroomKey && key != k8s.TerminationsKey && key != k8s.RestartsKey && key != k8s.ManifestsKey {
+                withoutNotes[key] = value
+            }
+        }
+        resources = withoutNotes
+    }
+
     resourcesJSON, err := json.MarshalIndent(resources, "", "  ")
     if err != nil {
         return "", fmt.Errorf("marshal resources: %w", err)
     }
 
-    return fmt.Sprintf(`You are a Kubernetes expert helping to debug configuration issues.
+    var truncationNote string
+    if len(omitted) > 0 {
+        truncationNote = fmt.Sprintf("\nNOTE: The resources were truncated to fit the model's context window. "+
+            "managedFields were removed and these entries were omitted: %s. Mention if the omitted data could matter.\n",
+            strings.Join(omitted, ", "))
+    }
 
-User's Problem: %s
+    return debugRole + fmt.Sprintf("\n\nUser's Problem: %s\n\nKubernetes Resources:\n%s\n%s%s\n",
+        problem, string(resourcesJSON), truncationNote, notes) + debugInstructions, nil
+}
 
-Kubernetes Resources:
-%s
+// debugRole opens the debug prompt
+const debugRole = "You are a Kubernetes expert helping to debug configuration issues."
 
-Please analyze these Kubernetes resources and provide:
+// debugInstructions closes the debug prompt with the analysis steps and the response format.
+// Together with debugRole it is the static part of every debug prompt.
+const debugInstructions = `Please analyze these Kubernetes resources and provide:
 1. The root cause of the problem
 2. Specific issues found in the configuration
 3. Actionable suggestions to fix the problem
 4. If possible, a quick fix command
+5. The kubectl commands that apply the fix, one per step
 
 Respond in JSON format with this structure:
 {
@@ -45,8 +79,157 @@ Respond in JSON format with this structure:
     }
   ],
   "quick_fix": "single kubectl command for immediate fix if possible",
+  "commands": [
+    {
+      "description": "what this command changes",
+      "command": "a single complete kubectl command, no pipes, placeholders or shell operators",
+      "destructive": false
+    }
+  ],
   "full_analysis": "detailed explanation of the problem and solution"
 }
 
-Focus on the specific problem mentioned. Be concise but thorough.`, problem, string(resourcesJSON)), nil
+List commands in the order they should run, with real resource names and namespaces, and leave
+"commands" empty rather than guessing. Set "destructive" to true for commands that delete, evict or
+replace resources or scale them to zero. Only use built-in subcommands that change resources (such as
+scale, label, patch, set, apply, rollout or delete), and never suggest exec, run, cp, debug, edit,
+attach, port-forward, proxy, config or plugins. Focus on the specific problem mentioned. Be concise but thorough.`
+
+// SplitDebugPrompt separates a debug prompt into its static instructions and the problem with
+// the resources, so LLMs with prompt caching can send the instructions as a cached system
+// prompt. system is empty when prompt isn't a debug prompt.
+func SplitDebugPrompt(prompt string) (system, user string) {
+    if !strings.HasPrefix(prompt, debugRole) || !strings.HasSuffix(prompt, debugInstructions) {
+        return "", prompt
+    }
+    user = strings.TrimSuffix(strings.TrimPrefix(prompt, debugRole), debugInstructions)
+    return debugRole + "\n\n" + debugInstructions, strings.TrimSpace(user)
+}
+
+// manifestNote tells the model the resources come from manifests that may not be applied yet, so
+// it reviews them as written instead of looking for pods, events and status that don't exist
+func manifestNote(resources map[string]interface{}) string {
+    var files []string
+    switch v := resources[k8s.ManifestsKey].(type) {
+    case []string:
+        files = v
+    case []interface{}:
+        // Generic copies made while trimming the prompt
+        for _, f := range v {
+            files = append(files, fmt.Sprint(f))
+        }
+    }
+    if len(files) == 0 {
+        return ""
+    }
+
+    return fmt.Sprintf("\nNOTE: These resources were read from the manifest files %s, not from a cluster, and may not be applied yet. "+
+        "There is no status, pods, events or logs. Review whether the manifests will work as written: references between them "+
+        "(selectors, service ports, ConfigMaps, Secrets, PVCs), probes, resource requests and limits, images and fields that are "+
+        "invalid or deprecated. Don't report missing status as an issue, and name the manifest to change in each suggestion.\n",
+        strings.Join(files, ", "))
+}
+
+// quotaNote calls out ResourceQuotas that are nearly exhausted, since the API server rejects
+// pods that would exceed them and the only trace is an event on the ReplicaSet
+func quotaNote(resources map[string]interface{}) string {
+    var warnings []string
+    switch v := resources[k8s.QuotaHeadroomKey].(type) {
+    case []string:
+        warnings = v
+    case []interface{}:
+        // Generic copies made while trimming the prompt
+        for _, w := range v {
+            warnings = append(warnings, fmt.Sprint(w))
+        }
+    }
+    if len(warnings) == 0 {
+        return ""
+    }
+
+    return fmt.Sprintf("\nWARNING: These ResourceQuotas are at or near their hard limits. New pods that would exceed them "+
+        "are rejected at creation (look for \"exceeded quota\" events), which can leave workloads with fewer pods than desired:\n- %s\n",
+        strings.Join(warnings, "\n- "))
+}
+
+// terminationNote lists OOMKilled and crashed containers at the top of the analysis, since their
+// reason is buried in lastState.terminated and memory limits are a common root cause
+func terminationNote(resources map[string]interface{}) string {
+    value, ok := resources[k8s.TerminationsKey]
+    if !ok {
+        return ""
+    }
+    // Round-trip through JSON so generic copies made while trimming the prompt work too
+    data, err := json.Marshal(value)
+    if err != nil {
+        return ""
+    }
+    var terminations []k8s.ContainerTermination
+    if err := json.Unmarshal(data, &terminations); err != nil || len(terminations) == 0 {
+        return ""
+    }
+
+    lines := make([]string, 0, len(terminations))
+    oomKilled := false
+    for _, t := range terminations {
+        state := "last terminated"
+        if t.Current {
+            state = "terminated"
+        }
+        line := fmt.Sprintf("%s/%s container %s %s with %s (exit code %d, %d restarts", t.Namespace, t.Pod, t.Container, state, t.Reason, t.ExitCode, t.Restarts)
+        if t.MemoryLimit != "" {
+            line += ", memory limit " + t.MemoryLimit
+        }
+        lines = append(lines, line+")")
+        if t.Reason == "OOMKilled" {
+            oomKilled = true
+        }
+    }
+
+    note := "\nIMPORTANT: These containers were OOMKilled or exited with an error. Report each as a high severity issue " +
+        "and check whether it explains the problem"
+    if oomKilled {
+        note += "; for OOMKilled containers compare the memory limit with the workload's needs and suggest a concrete new limit"
+    }
+    return fmt.Sprintf("%s:\n- %s\n", note, strings.Join(lines, "\n- "))
+}
+
+// restartNote spells out crash loops and restart rates, which the model tends to overlook when
+// they are only a restartCount in the resources JSON
+func restartNote(resources map[string]interface{}) string {
+    value, ok := resources[k8s.RestartsKey]
+    if !ok {
+        return ""
+    }
+    // Round-trip through JSON so generic copies made while trimming the prompt work too
+    data, err := json.Marshal(value)
+    if err != nil {
+        return ""
+    }
+    var restarts []k8s.ContainerRestarts
+    if err := json.Unmarshal(data, &restarts); err != nil || len(restarts) == 0 {
+        return ""
+    }
+
+    lines := make([]string, 0, len(restarts))
+    for _, r := range restarts {
+        var line string
+        if r.PodAgeMinutes <= 60 {
+            line = fmt.Sprintf("pod %s/%s container %s restarted %d times in the last hour", r.Namespace, r.Pod, r.Container, r.Restarts)
+        } else {
+            line = fmt.Sprintf("pod %s/%s container %s restarted %d times in %dh (about %.1f per hour)",
+                r.Namespace, r.Pod, r.Container, r.Restarts, r.PodAgeMinutes/60, r.RestartsPerHour)
+        }
+        if r.WaitingReason != "" {
+            line += ", now in " + r.WaitingReason
+        }
+        if r.Storm {
+            line += " [restart storm]"
+        }
+        lines = append(lines, line)
+    }
+
+    return fmt.Sprintf("\nIMPORTANT: These containers are crash looping or restarting repeatedly. Report each as an issue, "+
+        "critical for a restart storm and high otherwise, and use the logs and last termination state to explain why they restart:\n- %s\n",
+        strings.Join(lines, "\n- "))
 }
@@ -0,0 +1,62 @@
+package prompts
+
+import (
+    "encoding/json"
+    "fmt"
+)
+
+// BuildDiffPrompt asks what changed between two versions of a resource and, when a symptom is
+// given, whether the change is a likely cause of it
+func BuildDiffPrompt(subject, from, to, symptom string, changes interface{}) (string, error) {
+    changesJSON, err := json.MarshalIndent(changes, "", "  ")
+    if err != nil {
+        return "", fmt.Errorf("marshal changes: %w", err)
+    }
+
+    if symptom == "" {
+        symptom = "None reported. Assess the risk of the change instead."
+    }
+
+    return fmt.Sprintf(`You are a Kubernetes expert reviewing a change to a resource.
+
+Resource: %s
+Before: %s
+After: %s
+
+Symptom observed after the change: %s
+
+Field-level changes (path, change type, old and new value; list items such as containers and env vars are addressed by name; when several resources are compared, paths start with kind/name and whole added or removed resources are listed by kind/name):
+%s
+
+Please provide:
+1. A plain-English summary of what changed
+2. For each change, whether it could cause the symptom (or what risk it carries) and why
+3. The change most likely responsible, if any, and how to confirm it
+4. How to fix it or roll back, including a command where possible
+
+Respond in JSON format with this structure:
+{
+  "root_cause": "The change most likely behind the symptom, or a summary of the change if none is",
+  "severity": "low|medium|high|critical",
+  "issues": [
+    {
+      "component": "changed field path",
+      "severity": "low|medium|high|critical",
+      "description": "what changed and its likely effect",
+      "evidence": "old value -> new value"
+    }
+  ],
+  "suggestions": [
+    {
+      "priority": "high|medium|low",
+      "action": "what to do",
+      "command": "kubectl command if applicable, e.g. kubectl rollout undo",
+      "explanation": "why this helps"
+    }
+  ],
+  "quick_fix": "single kubectl command to revert or fix the change if possible",
+  "full_analysis": "detailed explanation of the change and its connection to the symptom"
+}
+
+Only treat a change as the cause when the symptom plausibly follows from it, and say so when none of the changes explain it.`, subject, from, to, symptom, string(changesJSON)), nil
+}
@@ -0,0 +1,55 @@
+package prompts
+
+import (
+    "encoding/json"
+    "fmt"
+)
+
+// BuildExplainPrompt asks for a plain-English description of a resource for someone new to it,
+// rather than a search for problems
+func BuildExplainPrompt(resource string, resources map[string]interface{}) (string, error) {
+    resourcesJSON, err := json.MarshalIndent(resources, "", "  ")
+    if err != nil {
+        return "", fmt.Errorf("marshal resources: %w", err)
+    }
+
+    return fmt.Sprintf(`You are a Kubernetes expert explaining a resource to a new team member.
+
+Resource: %s
+
+Resource and related objects (pods, claims, the Services and Ingresses that route to it):
+%s
+
+Explain in plain English what this resource does and how it is configured. Cover:
+1. Its purpose, as far as names, images and labels reveal it
+2. The container images and what they likely run
+3. Ports, and how traffic reaches it through Services and Ingresses
+4. Health checks (liveness, readiness and startup probes) and what they test
+5. Resources, replicas, volumes, config and secrets it depends on
+6. Anything a newcomer should be careful about
+
+Respond in JSON format with this structure:
+{
+  "root_cause": "One or two sentence summary of what this resource is and does",
+  "severity": "low unless the configuration has a notable risk, then medium|high|critical",
+  "issues": [
+    {
+      "component": "aspect, e.g. image, ports, probes, resources, exposure",
+      "severity": "low|medium|high|critical",
+      "description": "plain-English explanation of this aspect",
+      "evidence": "the relevant field and value"
+    }
+  ],
+  "suggestions": [
+    {
+      "priority": "high|medium|low",
+      "action": "something worth knowing or improving",
+      "command": "kubectl command to look closer, if applicable",
+      "explanation": "why it matters"
+    }
+  ],
+  "full_analysis": "A few paragraphs describing the resource end to end, as you would to a colleague"
+}
+
+Describe rather than diagnose, and avoid jargon where a simpler word works.`, resource, string(resourcesJSON)), nil
+}
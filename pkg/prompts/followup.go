@@ -0,0 +1,13 @@
+package prompts
+
+import "fmt"
+
+// BuildFollowUpPrompt wraps a question asked after the initial analysis. The first
+// prompt asked for JSON, so remind the model that follow-ups are answered in plain text.
+func BuildFollowUpPrompt(question string) string {
+    return fmt.Sprintf(`Follow-up question about the same Kubernetes resources and your analysis above:
+
+%s
+
+Answer in plain text (not JSON), concisely, and include kubectl commands where they help.`, question)
+}
@@ -0,0 +1,70 @@
+package prompts
+
+import (
+    "encoding/json"
+    "fmt"
+
+    "github.com/helmcode/kubectl-ai/pkg/k8s"
+)
+
+// BuildLogsPromptWithLimit builds the logs prompt and, when it exceeds maxTokens, keeps only the most
+// recent logs that fit, truncated like the logs gathered by debug. maxTokens <= 0 disables trimming.
+func BuildLogsPromptWithLimit(podName string, pod interface{}, logs string, maxTokens int) (string, error) {
+    prompt, err := BuildLogsPrompt(podName, pod, logs)
+    if err != nil || maxTokens <= 0 || EstimateTokens(prompt) <= maxTokens {
+        return prompt, err
+    }
+
+    // Whatever the pod and instructions leave of the budget goes to the logs, at ~4 characters per token
+    maxBytes := max((maxTokens-EstimateTokens(prompt)+EstimateTokens(logs))*4, 0)
+    return BuildLogsPrompt(podName, pod, k8s.TruncateLogs(logs, maxBytes))
+}
+
+func BuildLogsPrompt(podName string, pod interface{}, logs string) (string, error) {
+    podJSON, err := json.MarshalIndent(pod, "", "  ")
+    if err != nil {
+        return "", fmt.Errorf("marshal pod: %w", err)
+    }
+
+    return fmt.Sprintf(`You are a Kubernetes expert analyzing container logs to find the cause of failures.
+
+Pod: %s
+
+Pod Specification and Status:
+%s
+
+Container Logs:
+%s
+
+Please analyze these logs together with the pod status and provide:
+1. The root cause of any errors, crashes or restarts visible in the logs
+2. Specific log lines that show the problem
+3. Actionable suggestions to fix the problem
+4. If possible, a quick fix command
+
+Respond in JSON format with this structure:
+{
+  "root_cause": "Brief explanation of the root cause",
+  "severity": "low|medium|high|critical",
+  "issues": [
+    {
+      "component": "container name",
+      "severity": "low|medium|high|critical",
+      "description": "what's wrong",
+      "evidence": "relevant log line"
+    }
+  ],
+  "suggestions": [
+    {
+      "priority": "high|medium|low",
+      "action": "what to do",
+      "command": "kubectl command if applicable",
+      "explanation": "why this helps"
+    }
+  ],
+  "quick_fix": "single kubectl command for immediate fix if possible",
+  "full_analysis": "detailed explanation of what the logs show and how to solve it"
+}
+
+If the logs look healthy, say so and use severity "low". Be concise but thorough.`, podName, string(podJSON), logs), nil
+}
@@ -0,0 +1,9 @@
+package prompts
+
+// BuildJSONRetryPrompt asks the model to resend its previous answer as the JSON object
+// the original prompt requested, after that answer failed to parse.
+func BuildJSONRetryPrompt() string {
+    return `Your previous response could not be parsed as JSON.
+
+Reply again with ONLY a valid JSON object using exactly the structure requested in the first message (root_cause, severity, issues, suggestions, quick_fix, commands if requested, full_analysis). Do not wrap it in markdown code fences and do not add any text before or after the JSON.`
+}
@@ -0,0 +1,133 @@
+package prompts
+
+import (
+    "encoding/json"
+    "fmt"
+    "sort"
+    "strings"
+
+    "github.com/helmcode/kubectl-ai/pkg/k8s"
+)
+
+// EstimateTokens roughly estimates the number of tokens in s (about 4 characters per token)
+func EstimateTokens(s string) int {
+    return (len(s) + 3) / 4
+}
+
+// BuildDebugPromptWithLimit builds the debug prompt and, when it exceeds maxTokens, trims the
+// least important resources until it fits: managedFields first, then logs, related pods, events
+// and the largest resources, and the diagnostic notes last. The prompt notes what was left out. maxTokens <= 0 disables trimming.
+func BuildDebugPromptWithLimit(problem string, resources map[string]interface{}, maxTokens int) (string, error) {
+    prompt, err := BuildDebugPrompt(problem, resources)
+    if err != nil || maxTokens <= 0 || EstimateTokens(prompt) <= maxTokens {
+        return prompt, err
+    }
+
+    // Work on a generic copy so the caller's objects are left untouched
+    trimmed, err := toGenericResources(resources)
+    if err != nil {
+        return "", err
+    }
+    for _, value := range trimmed {
+        stripManagedFields(value)
+    }
+
+    sizes := make(map[string]int, len(trimmed))
+    for key, value := range trimmed {
+        data, _ := json.Marshal(value)
+        sizes[key] = EstimateTokens(string(data))
+    }
+
+    prompt, err = buildDebugPrompt(problem, trimmed, nil)
+    if err != nil {
+        return "", err
+    }
+    estimate := EstimateTokens(prompt)
+
+    var omitted []string
+    for _, key := range dropOrder(trimmed, sizes) {
+        // Only re-render once the running estimate says the prompt may fit
+        if estimate <= maxTokens {
+            prompt, err = buildDebugPrompt(problem, trimmed, omitted)
+            if err != nil {
+                return "", err
+            }
+            if estimate = EstimateTokens(prompt); estimate <= maxTokens {
+                return prompt, nil
+            }
+        }
+        delete(trimmed, key)
+        omitted = append(omitted, key)
+        estimate -= sizes[key]
+    }
+
+    // Nothing left to drop; send what remains and let the provider decide
+    return buildDebugPrompt(problem, trimmed, omitted)
+}
+
+// dropOrder returns resource keys from least to most important. Logs go first, as they are the
+// largest; the quota, termination, restart and manifest notes carry the diagnosis and go last.
+func dropOrder(resources map[string]interface{}, sizes map[string]int) []string {
+    rank := func(key string) int {
+        switch {
+        case strings.HasSuffix(key, "_logs"):
+            return 0
+        case strings.HasSuffix(key, "_pods"):
+            return 1
+        case key == "events" || strings.HasSuffix(key, "/events"):
+            return 2
+        case key == k8s.QuotaHeadroomKey || key == k8s.TerminationsKey || key == k8s.RestartsKey || key == k8s.ManifestsKey:
+            return 4
+        default:
+            return 3
+        }
+    }
+
+    keys := make([]string, 0, len(resources))
+    for key := range resources {
+        keys = append(keys, key)
+    }
+    sort.Slice(keys, func(i, j int) bool {
+        ri, rj := rank(keys[i]), rank(keys[j])
+        if ri != rj {
+            return ri < rj
+        }
+        if sizes[keys[i]] != sizes[keys[j]] {
+            return sizes[keys[i]] > sizes[keys[j]]
+        }
+        return keys[i] < keys[j]
+    })
+    return keys
+}
+
+// toGenericResources converts typed objects into plain maps via JSON
+func toGenericResources(resources map[string]interface{}) (map[string]interface{}, error) {
+    data, err := json.Marshal(resources)
+    if err != nil {
+        return nil, fmt.Errorf("marshal resources: %w", err)
+    }
+    var generic map[string]interface{}
+    if err := json.Unmarshal(data, &generic); err != nil {
+        return nil, fmt.Errorf("unmarshal resources: %w", err)
+    }
+    return generic, nil
+}
+
+// stripManagedFields removes metadata.managedFields from an object and any nested items
+func stripManagedFields(value interface{}) {
+    switch v := value.(type) {
+    case map[string]interface{}:
+        if metadata, ok := v["metadata"].(map[string]interface{}); ok {
+            delete(metadata, "managedFields")
+        }
+        if items, ok := v["items"].([]interface{}); ok {
+            for _, item := range items {
+                stripManagedFields(item)
+            }
+        }
+    case []interface{}:
+        for _, item := range v {
+            stripManagedFields(item)
+        }
+    }
+}